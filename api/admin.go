@@ -0,0 +1,461 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrAccountDisabled      = errors.New("Account is disabled")
+	ErrSelfAdministration   = errors.New("Cannot change your own permission or account status")
+	ErrPermissionGranted    = errors.New("User already holds the permission")
+	ErrPermissionNotGranted = errors.New("User does not hold the permission")
+	ErrAccountNotDisabled   = errors.New("Account is not disabled")
+	ErrUserNotVoted         = errors.New("User has not voted")
+	ErrRoleAssigned         = errors.New("User already holds the role")
+	ErrRoleNotAssigned      = errors.New("User does not hold the role")
+	ErrElectionNotFound     = errors.New("Election not found")
+)
+
+type listUsersRequest struct {
+	Search     string `form:"search"`
+	Permission string `form:"permission" binding:"omitempty,oneof=MANAGE_ELECTION VOTE"`
+	PageSize   int32  `form:"page_size" binding:"required,min=5,max=50"`
+	Cursor     string `form:"cursor"`
+}
+
+// userCursor is the position after the last user of a page, together with the
+// filters it was issued for
+type userCursor struct {
+	Search     string `json:"search"`
+	Permission string `json:"permission"`
+	NationalID string `json:"national_id"`
+}
+
+type listUsersResponse struct {
+	Data       []userProfileResponse `json:"data"`
+	PageSize   int32                 `json:"page_size"`
+	HasNext    bool                  `json:"has_next"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+// listUsers searches users by national ID, name or email for election managers
+func (server *Server) listUsers(ctx *gin.Context) {
+	var req listUsersRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var after userCursor
+	if req.Cursor != "" {
+		if err := util.VerifyCursor(server.config.TokenSymmetricKey, req.Cursor, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+		if after.Search != req.Search || after.Permission != req.Permission {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, util.ErrInvalidCursor))
+			return
+		}
+	}
+
+	users, err := server.store.ListUsers(ctx, db.ListUsersParams{
+		Search:          req.Search,
+		Permission:      req.Permission,
+		AfterNationalID: after.NationalID,
+		Limit:           req.PageSize + 1,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := listUsersResponse{
+		Data:     make([]userProfileResponse, 0, len(users)),
+		PageSize: req.PageSize,
+		HasNext:  len(users) > int(req.PageSize),
+	}
+	if rsp.HasNext {
+		users = users[:req.PageSize]
+
+		next := userCursor{
+			Search:     req.Search,
+			Permission: req.Permission,
+			NationalID: users[len(users)-1].NationalID,
+		}
+		rsp.NextCursor, err = util.SignCursor(server.config.TokenSymmetricKey, next)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+	for _, user := range users {
+		rsp.Data = append(rsp.Data, newUserProfileResponse(user))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type adminUserURI struct {
+	NationalID string `uri:"national_id" binding:"required,number,len=13"`
+}
+
+type adminUserRequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+type adminPermissionRequest struct {
+	Permission string `json:"permission" binding:"required,oneof=MANAGE_ELECTION VOTE"`
+	Reason     string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+func (server *Server) grantPermission(ctx *gin.Context) {
+	var req adminPermissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action:     util.GrantPermission,
+		Permission: req.Permission,
+		Reason:     req.Reason,
+	})
+}
+
+func (server *Server) revokePermission(ctx *gin.Context) {
+	var req adminPermissionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action:     util.RevokePermission,
+		Permission: req.Permission,
+		Reason:     req.Reason,
+	})
+}
+
+func (server *Server) disableUser(ctx *gin.Context) {
+	var req adminUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action: util.DisableAccount,
+		Reason: req.Reason,
+	})
+}
+
+func (server *Server) enableUser(ctx *gin.Context) {
+	var req adminUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action: util.EnableAccount,
+		Reason: req.Reason,
+	})
+}
+
+type resetVoterStatusRequest struct {
+	ElectionID int64  `json:"election_id" binding:"required,min=1"`
+	Reason     string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// resetVoterStatus lets a voter cast a ballot again in an open election, e.g. after a ballot was spoiled
+// at a polling station
+func (server *Server) resetVoterStatus(ctx *gin.Context) {
+	var req resetVoterStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, req.ElectionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, ErrElectionNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action:     util.ResetVoterStatus,
+		ElectionID: sql.NullInt64{Int64: election.ID, Valid: true},
+		Reason:     req.Reason,
+	})
+}
+
+type adminRoleRequest struct {
+	Role       string `json:"role" binding:"required,oneof=OBSERVER AUDITOR CANDIDATE_MANAGER ELECTION_OFFICER"`
+	ElectionID int64  `json:"election_id" binding:"omitempty,min=1"`
+	Reason     string `json:"reason" binding:"required,min=5,max=500"`
+}
+
+// assignRole assigns a role to the user for all elections, or for a single election when election_id is set
+func (server *Server) assignRole(ctx *gin.Context) {
+	var req adminRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action:     util.AssignRole,
+		Role:       req.Role,
+		ElectionID: sql.NullInt64{Int64: req.ElectionID, Valid: req.ElectionID != 0},
+		Reason:     req.Reason,
+	})
+}
+
+func (server *Server) unassignRole(ctx *gin.Context) {
+	var req adminRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	server.administerUser(ctx, db.AdminUpdateUserTxParams{
+		Action:     util.UnassignRole,
+		Role:       req.Role,
+		ElectionID: sql.NullInt64{Int64: req.ElectionID, Valid: req.ElectionID != 0},
+		Reason:     req.Reason,
+	})
+}
+
+// adminUserPrecondition returns why the action does not apply to the user, or nil when it does
+func adminUserPrecondition(user db.User, arg db.AdminUpdateUserTxParams) error {
+	switch arg.Action {
+	case util.GrantPermission:
+		if userHasPermission(user, arg.Permission) {
+			return ErrPermissionGranted
+		}
+	case util.RevokePermission:
+		if !userHasPermission(user, arg.Permission) {
+			return ErrPermissionNotGranted
+		}
+	case util.DisableAccount:
+		if user.DisabledAt.Valid {
+			return ErrAccountDisabled
+		}
+	case util.EnableAccount:
+		if !user.DisabledAt.Valid {
+			return ErrAccountNotDisabled
+		}
+	}
+	return nil
+}
+
+// administerUser applies an administrative change to the user of the URI, the change and its reason
+// are recorded for audit in the same transaction
+func (server *Server) administerUser(ctx *gin.Context, arg db.AdminUpdateUserTxParams) {
+	var uri adminUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if authPayload.NationalID == uri.NationalID && arg.Action != util.ResetVoterStatus {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrSelfAdministration))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, uri.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if err := adminUserPrecondition(user, arg); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg.ActorNationalID = authPayload.NationalID
+	arg.TargetNationalID = user.NationalID
+
+	result, err := server.store.AdminUpdateUserTx(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			switch arg.Action {
+			case util.AssignRole:
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrRoleAssigned))
+			case util.UnassignRole:
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrRoleNotAssigned))
+			case util.ResetVoterStatus:
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrUserNotVoted))
+			default:
+				// the user changed since it was read, the precondition explains the current state
+				ctx.JSON(http.StatusConflict, errorResponse(ctx, err))
+			}
+			return
+		}
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "foreign_key_violation" {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, ErrElectionNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserProfileResponse(result.User))
+}
+
+type userRoleResponse struct {
+	Role       string    `json:"role"`
+	ElectionID int64     `json:"election_id,omitempty"`
+	GrantedBy  string    `json:"granted_by"`
+	CreateAt   time.Time `json:"create_at"`
+}
+
+// listUserRoles lists the roles assigned to the user of the URI
+func (server *Server) listUserRoles(ctx *gin.Context) {
+	var uri adminUserURI
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	roles, err := server.store.ListUserRoles(ctx, uri.NationalID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]userRoleResponse, 0, len(roles))
+	for _, role := range roles {
+		rsp = append(rsp, userRoleResponse{
+			Role:       role.Role,
+			ElectionID: role.ElectionID.Int64,
+			GrantedBy:  role.GrantedBy,
+			CreateAt:   role.CreateAt,
+		})
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type userAuditResponse struct {
+	ID               int64     `json:"id"`
+	ActorNationalID  string    `json:"actor_national_id"`
+	TargetNationalID string    `json:"target_national_id"`
+	Action           string    `json:"action"`
+	Permission       string    `json:"permission,omitempty"`
+	Role             string    `json:"role,omitempty"`
+	ElectionID       int64     `json:"election_id,omitempty"`
+	Reason           string    `json:"reason"`
+	CreateAt         time.Time `json:"create_at"`
+}
+
+func newUserAuditResponse(audit db.UserAudit) userAuditResponse {
+	return userAuditResponse{
+		ID:               audit.ID,
+		ActorNationalID:  audit.ActorNationalID,
+		TargetNationalID: audit.TargetNationalID,
+		Action:           audit.Action,
+		Permission:       audit.Permission.String,
+		Role:             audit.Role.String,
+		ElectionID:       audit.ElectionID.Int64,
+		Reason:           audit.Reason,
+		CreateAt:         audit.CreateAt,
+	}
+}
+
+type listUserAuditsRequest struct {
+	NationalID string `form:"national_id" binding:"omitempty,number,len=13"`
+	PageSize   int32  `form:"page_size" binding:"required,min=5,max=50"`
+	Cursor     string `form:"cursor"`
+}
+
+// auditCursor is the position before the last audit of a page, the listing
+// runs newest first
+type auditCursor struct {
+	NationalID string `json:"national_id"`
+	ID         int64  `json:"id"`
+}
+
+type listUserAuditsResponse struct {
+	Data       []userAuditResponse `json:"data"`
+	PageSize   int32               `json:"page_size"`
+	HasNext    bool                `json:"has_next"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// listUserAudits lists the administrative changes to users, newest first
+func (server *Server) listUserAudits(ctx *gin.Context) {
+	var req listUserAuditsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var before auditCursor
+	if req.Cursor != "" {
+		if err := util.VerifyCursor(server.config.TokenSymmetricKey, req.Cursor, &before); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+		if before.NationalID != req.NationalID {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, util.ErrInvalidCursor))
+			return
+		}
+	}
+
+	audits, err := server.store.ListUserAudits(ctx, db.ListUserAuditsParams{
+		TargetNationalID: req.NationalID,
+		BeforeID:         before.ID,
+		Limit:            req.PageSize + 1,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := listUserAuditsResponse{
+		Data:     make([]userAuditResponse, 0, len(audits)),
+		PageSize: req.PageSize,
+		HasNext:  len(audits) > int(req.PageSize),
+	}
+	if rsp.HasNext {
+		audits = audits[:req.PageSize]
+
+		next := auditCursor{NationalID: req.NationalID, ID: audits[len(audits)-1].ID}
+		rsp.NextCursor, err = util.SignCursor(server.config.TokenSymmetricKey, next)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+	for _, audit := range audits {
+		rsp.Data = append(rsp.Data, newUserAuditResponse(audit))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
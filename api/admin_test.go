@@ -0,0 +1,847 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListUsersAPI(t *testing.T) {
+	admin := CreateRandomManager(t)
+	user, _ := CreateRandomUser(t)
+
+	testCases := []struct {
+		name          string
+		query         string
+		nationalID    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			query:      "?search=jo&permission=VOTE&page_size=5",
+			nationalID: admin.NationalID,
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.ListUsersParams{
+					Search:     "jo",
+					Permission: util.Vote,
+					Limit:      6,
+				}
+				store.EXPECT().
+					ListUsers(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.User{user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp listUsersResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.Data, 1)
+				require.Equal(t, user.NationalID, rsp.Data[0].NationalID)
+				require.False(t, rsp.HasNext)
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name:       "NoPermission",
+			query:      "?page_size=5",
+			nationalID: user.NationalID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user.Permission, nil)
+				store.EXPECT().
+					ListUserRoles(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return([]db.UserRole{}, nil)
+				store.EXPECT().
+					ListUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "InvalidPermission",
+			query:      "?permission=ROOT&page_size=5",
+			nationalID: admin.NationalID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUsers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "InternalError",
+			query:      "?page_size=5",
+			nationalID: admin.NationalID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUsers(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/api/admin/users"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.nationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestListUsersCursorAPI(t *testing.T) {
+	admin := CreateRandomManager(t)
+	pageSize := 5
+
+	users := make([]db.User, pageSize+2)
+	for i := range users {
+		users[i] = db.User{
+			NationalID: fmt.Sprintf("%013d", i+1),
+			FullName:   util.RandomString(10),
+			Email:      util.RandomEmail(),
+			Permission: []string{util.Vote},
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	listUsers := func(search string, cursor string) *httptest.ResponseRecorder {
+		query := url.Values{}
+		query.Add("search", search)
+		query.Add("page_size", fmt.Sprintf("%d", pageSize))
+		if cursor != "" {
+			query.Add("cursor", cursor)
+		}
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/admin/users?"+query.Encode(), nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	store.EXPECT().
+		ListUsers(gomock.Any(), gomock.Eq(db.ListUsersParams{
+			Search: "jo",
+			Limit:  int32(pageSize + 1),
+		})).
+		Times(1).
+		Return(users[:pageSize+1], nil)
+	store.EXPECT().
+		ListUsers(gomock.Any(), gomock.Eq(db.ListUsersParams{
+			Search:          "jo",
+			AfterNationalID: users[pageSize-1].NationalID,
+			Limit:           int32(pageSize + 1),
+		})).
+		Times(1).
+		Return(users[pageSize:], nil)
+
+	recorder := listUsers("jo", "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp listUsersResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data, pageSize)
+	require.True(t, rsp.HasNext)
+	require.NotEmpty(t, rsp.NextCursor)
+
+	recorder = listUsers("jo", rsp.NextCursor)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var next listUsersResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &next))
+	require.Len(t, next.Data, 2)
+	require.Equal(t, users[pageSize].NationalID, next.Data[0].NationalID)
+	require.False(t, next.HasNext)
+	require.Empty(t, next.NextCursor)
+
+	// a cursor only pages through the search it was issued for
+	recorder = listUsers("ann", rsp.NextCursor)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = listUsers("jo", rsp.NextCursor[1:])
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
+func TestAdministerUserAPI(t *testing.T) {
+	admin := CreateRandomManager(t)
+	user, _ := CreateRandomUser(t)
+	reason := "Appointed as election officer"
+
+	granted := user
+	granted.Permission = []string{util.Vote, util.ManageElection}
+
+	disabled := user
+	disabled.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	electionID := util.RandomInt(1, 1000)
+
+	testCases := []struct {
+		name          string
+		path          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "GrantPermission",
+			path: "permissions/grant",
+			body: gin.H{"permission": util.ManageElection, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				arg := db.AdminUpdateUserTxParams{
+					ActorNationalID:  admin.NationalID,
+					TargetNationalID: user.NationalID,
+					Action:           util.GrantPermission,
+					Permission:       util.ManageElection,
+					Reason:           reason,
+				}
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{User: granted}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userProfileResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, granted.Permission, rsp.Permission)
+			},
+		},
+		{
+			name: "PermissionAlreadyGranted",
+			path: "permissions/grant",
+			body: gin.H{"permission": util.Vote, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "RevokePermission",
+			path: "permissions/revoke",
+			body: gin.H{"permission": util.ManageElection, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(granted, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.AdminUpdateUserTxParams) (db.AdminUpdateUserTxResult, error) {
+						require.Equal(t, util.RevokePermission, arg.Action)
+						require.Equal(t, util.ManageElection, arg.Permission)
+						return db.AdminUpdateUserTxResult{User: user}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidPermission",
+			path: "permissions/grant",
+			body: gin.H{"permission": "ROOT", "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingReason",
+			path: "disable",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DisableAccount",
+			path: "disable",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{User: disabled}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userProfileResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Disabled)
+			},
+		},
+		{
+			name: "AlreadyDisabled",
+			path: "disable",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(disabled, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EnableAccount",
+			path: "enable",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(disabled, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ResetVoterStatus",
+			path: "reset-vote",
+			body: gin.H{"election_id": electionID, "reason": "Ballot spoiled at the polling station"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(electionID)).
+					Times(1).
+					Return(db.Election{ID: electionID}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				arg := db.AdminUpdateUserTxParams{
+					ActorNationalID:  admin.NationalID,
+					TargetNationalID: user.NationalID,
+					Action:           util.ResetVoterStatus,
+					ElectionID:       sql.NullInt64{Int64: electionID, Valid: true},
+					Reason:           "Ballot spoiled at the polling station",
+				}
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp userProfileResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, user.NationalID, rsp.NationalID)
+			},
+		},
+		{
+			name: "NotVoted",
+			path: "reset-vote",
+			body: gin.H{"election_id": electionID, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(electionID)).
+					Times(1).
+					Return(db.Election{ID: electionID}, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ResetVoterStatusClosedElection",
+			path: "reset-vote",
+			body: gin.H{"election_id": electionID, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(electionID)).
+					Times(1).
+					Return(db.Election{ID: electionID, Closed: true}, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ResetVoterStatusElectionNotFound",
+			path: "reset-vote",
+			body: gin.H{"election_id": electionID, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(electionID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ResetVoterStatusMissingElection",
+			path: "reset-vote",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UserNotFound",
+			path: "disable",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ChangedConcurrently",
+			path: "disable",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "AssignRole",
+			path: "roles/assign",
+			body: gin.H{"role": util.ElectionOfficerRole, "election_id": electionID, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				arg := db.AdminUpdateUserTxParams{
+					ActorNationalID:  admin.NationalID,
+					TargetNationalID: user.NationalID,
+					Action:           util.AssignRole,
+					Role:             util.ElectionOfficerRole,
+					ElectionID:       sql.NullInt64{Int64: electionID, Valid: true},
+					Reason:           reason,
+				}
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RoleAlreadyAssigned",
+			path: "roles/assign",
+			body: gin.H{"role": util.ObserverRole, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionNotFound",
+			path: "roles/assign",
+			body: gin.H{"role": util.ObserverRole, "election_id": electionID, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{}, &pq.Error{Code: "23503"})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRole",
+			path: "roles/assign",
+			body: gin.H{"role": "ROOT", "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnassignRole",
+			path: "roles/unassign",
+			body: gin.H{"role": util.AuditorRole, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				arg := db.AdminUpdateUserTxParams{
+					ActorNationalID:  admin.NationalID,
+					TargetNationalID: user.NationalID,
+					Action:           util.UnassignRole,
+					Role:             util.AuditorRole,
+					Reason:           reason,
+				}
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{User: user}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RoleNotAssigned",
+			path: "roles/unassign",
+			body: gin.H{"role": util.AuditorRole, "reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			path: "disable",
+			body: gin.H{"reason": reason},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.AdminUpdateUserTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/admin/users/%s/%s", user.NationalID, tc.path)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestAdministerOwnAccountAPI(t *testing.T) {
+	admin := CreateRandomManager(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(0)
+	store.EXPECT().
+		AdminUpdateUserTx(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(gin.H{"permission": util.ManageElection, "reason": "Stepping down"})
+	require.NoError(t, err)
+
+	url := fmt.Sprintf("/api/admin/users/%s/permissions/revoke", admin.NationalID)
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusForbidden, recorder.Code)
+}
+
+func TestListUserRolesAPI(t *testing.T) {
+	admin := CreateRandomManager(t)
+	user, _ := CreateRandomUser(t)
+
+	role := db.UserRole{
+		ID:         util.RandomInt(1, 1000),
+		NationalID: user.NationalID,
+		Role:       util.ObserverRole,
+		ElectionID: sql.NullInt64{Int64: util.RandomInt(1, 1000), Valid: true},
+		GrantedBy:  admin.NationalID,
+		CreateAt:   time.Now(),
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListUserRoles(gomock.Any(), gomock.Eq(user.NationalID)).
+		Times(1).
+		Return([]db.UserRole{role}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	url := fmt.Sprintf("/api/admin/users/%s/roles", user.NationalID)
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp []userRoleResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp, 1)
+	require.Equal(t, role.Role, rsp[0].Role)
+	require.Equal(t, role.ElectionID.Int64, rsp[0].ElectionID)
+	require.Equal(t, role.GrantedBy, rsp[0].GrantedBy)
+}
+
+func TestListUserAuditsAPI(t *testing.T) {
+	admin := CreateRandomManager(t)
+	user, _ := CreateRandomUser(t)
+	pageSize := 5
+
+	// newest first, as the store returns them
+	audits := make([]db.UserAudit, pageSize+2)
+	for i := range audits {
+		audits[i] = db.UserAudit{
+			ID:               int64(len(audits) - i),
+			ActorNationalID:  admin.NationalID,
+			TargetNationalID: user.NationalID,
+			Action:           util.GrantPermission,
+			Permission:       sql.NullString{String: util.ManageElection, Valid: true},
+			Reason:           "Appointed as election officer",
+			CreateAt:         time.Now(),
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	listAudits := func(nationalID string, cursor string) *httptest.ResponseRecorder {
+		query := url.Values{}
+		query.Add("national_id", nationalID)
+		query.Add("page_size", fmt.Sprintf("%d", pageSize))
+		if cursor != "" {
+			query.Add("cursor", cursor)
+		}
+
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/admin/audits?"+query.Encode(), nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	store.EXPECT().
+		ListUserAudits(gomock.Any(), gomock.Eq(db.ListUserAuditsParams{
+			TargetNationalID: user.NationalID,
+			Limit:            int32(pageSize + 1),
+		})).
+		Times(1).
+		Return(audits[:pageSize+1], nil)
+	store.EXPECT().
+		ListUserAudits(gomock.Any(), gomock.Eq(db.ListUserAuditsParams{
+			TargetNationalID: user.NationalID,
+			BeforeID:         audits[pageSize-1].ID,
+			Limit:            int32(pageSize + 1),
+		})).
+		Times(1).
+		Return(audits[pageSize:], nil)
+
+	recorder := listAudits(user.NationalID, "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp listUserAuditsResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp.Data, pageSize)
+	require.True(t, rsp.HasNext)
+	require.NotEmpty(t, rsp.NextCursor)
+	require.Equal(t, audits[0].ID, rsp.Data[0].ID)
+	require.Equal(t, audits[0].Action, rsp.Data[0].Action)
+	require.Equal(t, audits[0].Permission.String, rsp.Data[0].Permission)
+	require.Equal(t, audits[0].Reason, rsp.Data[0].Reason)
+
+	recorder = listAudits(user.NationalID, rsp.NextCursor)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var next listUserAuditsResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &next))
+	require.Len(t, next.Data, 2)
+	require.Equal(t, audits[pageSize].ID, next.Data[0].ID)
+	require.False(t, next.HasNext)
+	require.Empty(t, next.NextCursor)
+
+	// a cursor only pages through the user it was issued for
+	recorder = listAudits(admin.NationalID, rsp.NextCursor)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = listAudits(user.NationalID, rsp.NextCursor[1:])
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
@@ -0,0 +1,286 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrIncompleteBallot = errors.New("Ballot has no selection for every contest")
+	ErrInvalidBallot    = errors.New("Invalid ballot")
+	ErrBallotRequired   = errors.New("Election has several contests, submit the whole ballot")
+	ErrEmptyBallot      = errors.New("Election has no contest to vote on")
+)
+
+type measureSelection struct {
+	MeasureId int64 `json:"measureId" binding:"required,min=1"`
+	OptionId  int64 `json:"optionId" binding:"required,min=1"`
+}
+
+type submitBallotRequest struct {
+	NationalId string             `json:"nationalId" binding:"required,number,len=13"`
+	ElectionId int64              `json:"electionId" binding:"required,min=1"`
+	Candidates []int64            `json:"candidates" binding:"dive,min=1"`
+	Measures   []measureSelection `json:"measures" binding:"dive"`
+}
+
+// submitBallot records the selections of every contest of the election together, so a ballot is
+// never partially recorded: one candidate for the main race and for each contest the voter's
+// district has candidates in, and one option for each ballot measure. A referendum has measures
+// only. Every candidate vote gets its own receipt.
+func (server Server) submitBallot(ctx *gin.Context) {
+	var req submitBallotRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.NationalId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	election, valid := server.validBallotElection(ctx, req.ElectionId)
+	if !valid {
+		return
+	}
+
+	castBy, valid := server.validBallotCaster(ctx, user, election.ID)
+	if !valid {
+		return
+	}
+
+	candidates, err := server.store.ListBallotCandidates(ctx, db.ListBallotCandidatesParams{
+		ElectionID: election.ID,
+		DistrictID: user.DistrictID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	allMeasures, err := server.store.ListBallotMeasures(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// only the measures of the election are on the ballot, a runoff has none
+	measures := make([]db.BallotMeasure, 0, len(allMeasures))
+	for _, measure := range allMeasures {
+		if measure.ElectionID == election.ID {
+			measures = append(measures, measure)
+		}
+	}
+
+	if len(candidates) == 0 && len(measures) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrEmptyBallot))
+		return
+	}
+
+	options, err := server.store.ListBallotMeasureOptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if err := validateCandidateSelections(req.Candidates, candidates); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if err := validateMeasureSelections(req.Measures, measures, options); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.CastBallotTxParams{
+		Votes:        make([]db.CreateVoteParams, 0, len(req.Candidates)),
+		MeasureVotes: make([]db.CreateMeasureVoteParams, 0, len(req.Measures)),
+	}
+	receipts := make([]string, 0, len(req.Candidates))
+	for _, candidateID := range req.Candidates {
+		receiptCode, err := util.NewReceiptCode()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		receipts = append(receipts, receiptCode)
+
+		arg.Votes = append(arg.Votes, db.CreateVoteParams{
+			VoteNationalID:   user.NationalID,
+			CandidateID:      candidateID,
+			ReceiptHash:      util.HashReceiptCode(receiptCode),
+			CastByNationalID: castBy,
+		})
+	}
+	for _, selection := range req.Measures {
+		arg.MeasureVotes = append(arg.MeasureVotes, db.CreateMeasureVoteParams{
+			VoteNationalID:   user.NationalID,
+			MeasureID:        selection.MeasureId,
+			OptionID:         selection.OptionId,
+			CastByNationalID: castBy,
+		})
+	}
+
+	_, err = server.store.CastBallotTx(ctx, arg)
+	if err != nil {
+		var pqError *pq.Error
+		if errors.As(err, &pqError) {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrAlreadyVoted))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.metrics.votesCast.Inc(ballotVote)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "ok",
+		"receipts": receipts,
+	})
+}
+
+// validBallotElection checks that the election is open and voted by candidate, a party-list
+// election is voted by party instead
+func (server Server) validBallotElection(ctx *gin.Context, electionID int64) (db.Election, bool) {
+	isClosedElection, err := server.store.GetElectionProperty(ctx, util.ElectionClosed)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.Election{}, false
+	}
+
+	if isClosedElection.Value {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return db.Election{}, false
+	}
+
+	election, err := server.store.GetElection(ctx, electionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.Election{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.Election{}, false
+	}
+
+	if election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return db.Election{}, false
+	}
+
+	if election.BallotType == util.BallotTypePartyList {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyListElection))
+		return db.Election{}, false
+	}
+
+	return election, true
+}
+
+// validateCandidateSelections checks that there is exactly one selected candidate for every contest
+// on the voter's ballot, the main race included, and that each selected candidate is on that ballot
+func validateCandidateSelections(selections []int64, candidates []db.ListBallotCandidatesRow) error {
+	// the main race has no contest id, it is keyed 0
+	contestOf := make(map[int64]int64, len(candidates))
+	for _, candidate := range candidates {
+		contestOf[candidate.ID] = candidate.ContestID.Int64
+	}
+
+	selected := make(map[int64]bool, len(selections))
+	for _, candidateID := range selections {
+		contestID, ok := contestOf[candidateID]
+		if !ok {
+			return fmt.Errorf("%w: candidate %d is not on the ballot", ErrInvalidBallot, candidateID)
+		}
+		if selected[contestID] {
+			return fmt.Errorf("%w: contest %d is selected more than once", ErrInvalidBallot, contestID)
+		}
+		selected[contestID] = true
+	}
+
+	for _, candidate := range candidates {
+		if !selected[candidate.ContestID.Int64] {
+			return fmt.Errorf("%w: contest %d is missing", ErrIncompleteBallot, candidate.ContestID.Int64)
+		}
+	}
+
+	return nil
+}
+
+// validateMeasureSelections checks that there is exactly one selection for every ballot measure
+// and that each selected option belongs to one of these measures
+func validateMeasureSelections(selections []measureSelection, measures []db.BallotMeasure, options []db.BallotMeasureOption) error {
+	onBallot := make(map[int64]bool, len(measures))
+	for _, measure := range measures {
+		onBallot[measure.ID] = true
+	}
+
+	optionMeasure := make(map[int64]int64, len(options))
+	for _, option := range options {
+		if onBallot[option.MeasureID] {
+			optionMeasure[option.ID] = option.MeasureID
+		}
+	}
+
+	selected := make(map[int64]bool, len(selections))
+	for _, selection := range selections {
+		if selected[selection.MeasureId] {
+			return fmt.Errorf("%w: measure %d is selected more than once", ErrInvalidBallot, selection.MeasureId)
+		}
+		selected[selection.MeasureId] = true
+
+		measureID, ok := optionMeasure[selection.OptionId]
+		if !ok || measureID != selection.MeasureId {
+			return fmt.Errorf("%w: measure %d: %v", ErrInvalidBallot, selection.MeasureId, ErrOptionNotInMeasure)
+		}
+	}
+
+	for _, measure := range measures {
+		if !selected[measure.ID] {
+			return fmt.Errorf("%w: measure %d is missing", ErrIncompleteBallot, measure.ID)
+		}
+	}
+
+	return nil
+}
+
+// validSingleContestVote checks that the election has no contest besides its main race and no
+// ballot measures, the contests of such an election are only accepted together through submitBallot
+func (server Server) validSingleContestVote(ctx *gin.Context, electionID int64) bool {
+	measures, err := server.store.CountElectionBallotMeasures(ctx, electionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return false
+	}
+
+	contests, err := server.store.CountElectionContests(ctx, electionID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return false
+	}
+
+	if measures > 0 || contests > 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrBallotRequired))
+		return false
+	}
+
+	return true
+}
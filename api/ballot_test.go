@@ -0,0 +1,562 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+type eqCastBallotTxParamsMatcher struct {
+	arg           db.CastBallotTxParams
+	receiptHashes *[]string
+}
+
+func (e eqCastBallotTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CastBallotTxParams)
+	if !ok {
+		return false
+	}
+
+	if len(arg.Votes) != len(e.arg.Votes) {
+		return false
+	}
+
+	hashes := make([]string, len(arg.Votes))
+	expected := e.arg
+	expected.Votes = append([]db.CreateVoteParams{}, e.arg.Votes...)
+	for i, vote := range arg.Votes {
+		if len(vote.ReceiptHash) == 0 {
+			return false
+		}
+		hashes[i] = vote.ReceiptHash
+		expected.Votes[i].ReceiptHash = vote.ReceiptHash
+	}
+
+	*e.receiptHashes = hashes
+
+	return reflect.DeepEqual(expected, arg)
+}
+
+func (e eqCastBallotTxParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v with any receipt hashes", e.arg)
+}
+
+func eqCastBallotTxParams(arg db.CastBallotTxParams, receiptHashes *[]string) gomock.Matcher {
+	return eqCastBallotTxParamsMatcher{
+		arg:           arg,
+		receiptHashes: receiptHashes,
+	}
+}
+
+func TestSubmitBallotAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	closedElectionProperty := CreateClosedElectionProperty()
+
+	election := RandomElection()
+	election.ID = 1
+	runoff := RandomElection()
+	runoff.ID = 2
+	partyListElection := RandomElection()
+	partyListElection.BallotType = util.BallotTypePartyList
+	closedElection := RandomElection()
+	closedElection.Closed = true
+
+	// president runs in the main race, treasurer in a contest of the election
+	president1 := db.ListBallotCandidatesRow{ID: util.RandomInt(1, 1000)}
+	president2 := db.ListBallotCandidatesRow{ID: president1.ID + 1}
+	treasurer := db.ListBallotCandidatesRow{ID: president1.ID + 2, ContestID: sql.NullInt64{Int64: 7, Valid: true}}
+	candidates := []db.ListBallotCandidatesRow{president1, president2, treasurer}
+
+	measure1 := RandomBallotMeasure()
+	measure2 := RandomBallotMeasure()
+	measure2.ID = measure1.ID + 1
+	options1 := RandomBallotMeasureOptions(measure1.ID, defaultMeasureOptions)
+	options2 := RandomBallotMeasureOptions(measure2.ID, defaultMeasureOptions)
+	options2[0].ID = options1[0].ID + 1000
+	options2[1].ID = options1[1].ID + 1000
+
+	measures := []db.BallotMeasure{measure1, measure2}
+	options := append(append([]db.BallotMeasureOption{}, options1...), options2...)
+	measureSelections := []gin.H{
+		{"measureId": measure1.ID, "optionId": options1[0].ID},
+		{"measureId": measure2.ID, "optionId": options2[1].ID},
+	}
+	measureVotes := []db.CreateMeasureVoteParams{
+		{VoteNationalID: user.NationalID, MeasureID: measure1.ID, OptionID: options1[0].ID},
+		{VoteNationalID: user.NationalID, MeasureID: measure2.ID, OptionID: options2[1].ID},
+	}
+
+	voted := CreateVoted(user.NationalID, president1.ID)
+	var receiptHashes []string
+
+	buildBallot := func(store *mockdb.MockStore, election db.Election, candidates []db.ListBallotCandidatesRow) {
+		store.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+			Times(1).
+			Return(user, nil)
+		store.EXPECT().
+			GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+			Times(1).
+			Return(closedElectionProperty, nil)
+		store.EXPECT().
+			GetElection(gomock.Any(), gomock.Eq(election.ID)).
+			Times(1).
+			Return(election, nil)
+		store.EXPECT().
+			GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(db.Delegation{}, sql.ErrNoRows)
+		store.EXPECT().
+			ListBallotCandidates(gomock.Any(), gomock.Eq(db.ListBallotCandidatesParams{
+				ElectionID: election.ID,
+				DistrictID: user.DistrictID,
+			})).
+			Times(1).
+			Return(candidates, nil)
+		store.EXPECT().
+			ListBallotMeasures(gomock.Any()).
+			Times(1).
+			Return(measures, nil)
+	}
+
+	buildValidVoter := func(store *mockdb.MockStore) {
+		buildBallot(store, election, candidates)
+		store.EXPECT().
+			ListBallotMeasureOptions(gomock.Any()).
+			Times(1).
+			Return(options, nil)
+	}
+
+	buildInvalidElection := func(store *mockdb.MockStore, election db.Election, err error) {
+		store.EXPECT().
+			GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+			Times(1).
+			Return(user, nil)
+		store.EXPECT().
+			GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+			Times(1).
+			Return(closedElectionProperty, nil)
+		store.EXPECT().
+			GetElection(gomock.Any(), gomock.Eq(election.ID)).
+			Times(1).
+			Return(election, err)
+		store.EXPECT().
+			ListBallotCandidates(gomock.Any(), gomock.Any()).
+			Times(0)
+		store.EXPECT().
+			CastBallotTx(gomock.Any(), gomock.Any()).
+			Times(0)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID},
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+
+				arg := db.CastBallotTxParams{
+					Votes: []db.CreateVoteParams{
+						{VoteNationalID: user.NationalID, CandidateID: president1.ID},
+						{VoteNationalID: user.NationalID, CandidateID: treasurer.ID},
+					},
+					MeasureVotes: measureVotes,
+				}
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), eqCastBallotTxParams(arg, &receiptHashes)).
+					Times(1).
+					Return(db.CastBallotTxResult{Votes: []db.Vote{voted, voted}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBallotReceipts(t, recorder.Body, receiptHashes)
+			},
+		},
+		{
+			name: "Referendum",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildBallot(store, election, []db.ListBallotCandidatesRow{})
+				store.EXPECT().
+					ListBallotMeasureOptions(gomock.Any()).
+					Times(1).
+					Return(options, nil)
+
+				arg := db.CastBallotTxParams{
+					Votes:        []db.CreateVoteParams{},
+					MeasureVotes: measureVotes,
+				}
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), eqCastBallotTxParams(arg, &receiptHashes)).
+					Times(1).
+					Return(db.CastBallotTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBallotReceipts(t, recorder.Body, []string{})
+			},
+		},
+		{
+			name: "RunoffWithoutMeasures",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": runoff.ID,
+				"candidates": []int64{president1.ID},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildBallot(store, runoff, []db.ListBallotCandidatesRow{president1, president2})
+				store.EXPECT().
+					ListBallotMeasureOptions(gomock.Any()).
+					Times(1).
+					Return(options, nil)
+
+				arg := db.CastBallotTxParams{
+					Votes: []db.CreateVoteParams{
+						{VoteNationalID: user.NationalID, CandidateID: president1.ID},
+					},
+					MeasureVotes: []db.CreateMeasureVoteParams{},
+				}
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), eqCastBallotTxParams(arg, &receiptHashes)).
+					Times(1).
+					Return(db.CastBallotTxResult{Votes: []db.Vote{voted}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBallotReceipts(t, recorder.Body, receiptHashes)
+			},
+		},
+		{
+			name: "EmptyBallot",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": runoff.ID,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildBallot(store, runoff, []db.ListBallotCandidatesRow{})
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingContest",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID},
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "TwoCandidatesSameContest",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, president2.ID, treasurer.ID},
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CandidateNotOnBallot",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID + 1},
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "IncompleteBallot",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID},
+				"measures": []gin.H{
+					{"measureId": measure1.ID, "optionId": options1[0].ID},
+				},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateSelection",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID},
+				"measures": []gin.H{
+					{"measureId": measure1.ID, "optionId": options1[0].ID},
+					{"measureId": measure1.ID, "optionId": options1[1].ID},
+					{"measureId": measure2.ID, "optionId": options2[1].ID},
+				},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OptionNotInMeasure",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID},
+				"measures": []gin.H{
+					{"measureId": measure1.ID, "optionId": options2[0].ID},
+					{"measureId": measure2.ID, "optionId": options2[1].ID},
+				},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PartyListElection",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": partyListElection.ID,
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildInvalidElection(store, partyListElection, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ClosedElection",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": closedElection.ID,
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildInvalidElection(store, closedElection, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionNotFound",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildInvalidElection(store, election, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVoted",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID},
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CastBallotTxResult{}, fmt.Errorf("candidate %d: %w", treasurer.ID, &pq.Error{Code: "23505"}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID, treasurer.ID},
+				"measures":   measureSelections,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildValidVoter(store)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CastBallotTxResult{}, sql.ErrTxDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSelection",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": election.ID,
+				"candidates": []int64{president1.ID},
+				"measures": []gin.H{
+					{"measureId": measure1.ID},
+				},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "MissingElection",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"candidates": []int64{president1.ID},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CastBallotTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/ballot"
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func requireBodyMatchBallotReceipts(t *testing.T, body *bytes.Buffer, receiptHashes []string) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotResponse struct {
+		Status   string   `json:"status"`
+		Receipts []string `json:"receipts"`
+	}
+	err = json.Unmarshal(data, &gotResponse)
+	require.NoError(t, err)
+	require.Equal(t, "ok", gotResponse.Status)
+	require.Len(t, gotResponse.Receipts, len(receiptHashes))
+	for i, receipt := range gotResponse.Receipts {
+		require.Equal(t, receiptHashes[i], util.HashReceiptCode(receipt))
+	}
+}
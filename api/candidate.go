@@ -2,100 +2,246 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	db "election/db/sqlc"
+	"election/token"
+	"election/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
+var (
+	ErrProfileLocked         = errors.New("Candidate profile cannot change once voting has started")
+	ErrRelevanceNeedsSearch  = errors.New("Sorting by relevance needs a search query")
+	ErrCandidateWithdrawn    = errors.New("Candidate is withdrawn")
+	ErrCandidateNotWithdrawn = errors.New("Candidate is not withdrawn")
+	ErrCandidateHasVotes     = errors.New("Candidate already has votes")
+)
+
+type policyItem struct {
+	Title    string `json:"title" binding:"required"`
+	Body     string `json:"body" binding:"required"`
+	Category string `json:"category" binding:"required"`
+}
+
+type candidateLink struct {
+	Label string `json:"label" binding:"required"`
+	Url   string `json:"url" binding:"required,url"`
+}
+
 type createCandidateRequest struct {
-	Name      string `json:"name" binding:"required"`
-	Dob       string `json:"dob" binding:"required,dateOfBirth"`
-	BioLink   string `json:"bioLink" binding:"required,url"`
-	ImageLink string `json:"imageLink" binding:"required,url"`
-	Policy    string `json:"policy" binding:"required"`
+	Name        string          `json:"name" binding:"required"`
+	Dob         string          `json:"dob" binding:"required,dateOfBirth"`
+	BioLink     string          `json:"bioLink" binding:"required,url"`
+	ImageLink   string          `json:"imageLink" binding:"omitempty,url"`
+	Policy      string          `json:"policy" binding:"required"`
+	PolicyItems []policyItem    `json:"policyItems" binding:"omitempty,dive"`
+	Links       []candidateLink `json:"links" binding:"omitempty,dive"`
+	PartyID     int64           `json:"partyId" binding:"omitempty,min=1"`
+	DistrictID  int64           `json:"districtId" binding:"omitempty,min=1"`
+	ContestID   int64           `json:"contestId" binding:"omitempty,min=1"`
 }
 
+// createCandidate adds a candidate to the main race of the general election,
+// or to the contest and with it the election of contestId
 func (server Server) createCandidate(ctx *gin.Context) {
 	var req createCandidateRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
+	policyItems, links, err := marshalProfileLists(req.PolicyItems, req.Links)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	electionID := int64(generalElectionID)
+	if req.ContestID > 0 {
+		contest, err := server.store.GetContest(ctx, req.ContestID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		electionID = contest.ElectionID
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
 	arg := db.CreateCandidateParams{
-		Name:       req.Name,
-		Dob:        req.Dob,
-		BioLink:    req.BioLink,
-		ImageUrl:   req.ImageLink,
-		Policy:     req.Policy,
-		VoteCount:  0,
-		Percentage: 0,
+		Name:        req.Name,
+		Dob:         req.Dob,
+		BioLink:     req.BioLink,
+		ImageUrl:    req.ImageLink,
+		Policy:      req.Policy,
+		VoteCount:   0,
+		Percentage:  0,
+		DistrictID:  nullDistrictID(req.DistrictID),
+		PartyID:     nullPartyID(req.PartyID),
+		PolicyItems: policyItems,
+		Links:       links,
+		EditedBy:    sql.NullString{String: authPayload.NationalID, Valid: true},
+		ElectionID:  electionID,
+		ContestID:   nullContestID(req.ContestID),
 	}
 
 	candidate, err := server.store.CreateCandidate(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	rsp := candidateResponse{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
-		CreateAt:  candidate.CreateAt,
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		Dob:        candidate.Dob,
+		BioLink:    candidate.BioLink,
+		ImageUrl:   candidate.ImageUrl,
+		Policy:     candidate.Policy,
+		PartyID:    candidate.PartyID.Int64,
+		Version:    candidate.Version,
+		VoteCount:  candidate.VoteCount,
+		DistrictID: candidate.DistrictID.Int64,
+		ContestID:  candidate.ContestID.Int64,
+		CreateAt:   candidate.CreateAt,
+	}
+	if !rsp.setProfileLists(ctx, candidate.PolicyItems, candidate.Links) {
+		return
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+// marshalProfileLists encodes the policy items and links for their jsonb columns,
+// missing lists are stored empty
+func marshalProfileLists(items []policyItem, links []candidateLink) (json.RawMessage, json.RawMessage, error) {
+	if items == nil {
+		items = []policyItem{}
+	}
+	if links == nil {
+		links = []candidateLink{}
+	}
+
+	itemsJSON, err := json.Marshal(items)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	linksJSON, err := json.Marshal(links)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return itemsJSON, linksJSON, nil
+}
+
 type getCandidateRequest struct {
 	Id int64 `uri:"id" binding:"required,min=1"`
 }
 
 type candidateResponse struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Dob       string    `json:"dob"`
-	BioLink   string    `json:"bio_link"`
-	ImageUrl  string    `json:"image_url"`
-	Policy    string    `json:"policy"`
-	VoteCount int32     `json:"vote_count"`
-	CreateAt  time.Time `json:"create_at"`
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Dob         string          `json:"dob"`
+	BioLink     string          `json:"bio_link"`
+	ImageUrl    string          `json:"image_url"`
+	Policy      string          `json:"policy"`
+	PolicyItems []policyItem    `json:"policy_items"`
+	Links       []candidateLink `json:"links"`
+	PartyID     int64           `json:"party_id,omitempty"`
+	Version     int32           `json:"version"`
+	VoteCount   int32           `json:"vote_count"`
+	DistrictID  int64           `json:"district_id,omitempty"`
+	ContestID   int64           `json:"contest_id,omitempty"`
+	CreateAt    time.Time       `json:"create_at"`
+
+	WithdrawnAt     *time.Time `json:"withdrawn_at,omitempty"`
+	WithdrawnReason string     `json:"withdrawn_reason,omitempty"`
+}
+
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// setProfileLists decodes the stored policy items and links into the response
+func (rsp *candidateResponse) setProfileLists(ctx *gin.Context, items, links json.RawMessage) bool {
+	rsp.PolicyItems = []policyItem{}
+	rsp.Links = []candidateLink{}
+
+	if len(items) > 0 {
+		if err := json.Unmarshal(items, &rsp.PolicyItems); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return false
+		}
+	}
+
+	if len(links) > 0 {
+		if err := json.Unmarshal(links, &rsp.Links); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return false
+		}
+	}
+
+	return true
 }
 
 func (server Server) getCandidate(ctx *gin.Context) {
 	var req getCandidateRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	candidate, err := server.store.GetCandidate(ctx, req.Id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	rsp := candidateResponse{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
-		CreateAt:  candidate.CreateAt,
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		Dob:        candidate.Dob,
+		BioLink:    candidate.BioLink,
+		ImageUrl:   candidate.ImageUrl,
+		Policy:     candidate.Policy,
+		PartyID:    candidate.PartyID.Int64,
+		Version:    candidate.Version,
+		VoteCount:  candidate.VoteCount,
+		DistrictID: candidate.DistrictID.Int64,
+		ContestID:  candidate.ContestID.Int64,
+		CreateAt:   candidate.CreateAt,
+
+		WithdrawnAt:     nullTimePtr(candidate.WithdrawnAt),
+		WithdrawnReason: candidate.WithdrawnReason,
+	}
+	if !rsp.setProfileLists(ctx, candidate.PolicyItems, candidate.Links) {
+		return
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
@@ -103,109 +249,464 @@ func (server Server) getCandidate(ctx *gin.Context) {
 }
 
 type listCandidateRequest struct {
-	PageID   int32 `form:"page_id" binding:"required,min=1"`
-	PageSize int32 `form:"page_size" binding:"required,min=5,max=10"`
+	PageID     int32  `form:"page_id" binding:"omitempty,min=1"`
+	PageSize   int32  `form:"page_size" binding:"required,min=5,max=50"`
+	Cursor     string `form:"cursor" binding:"omitempty,excluded_with=PageID"`
+	Search     string `form:"q" binding:"omitempty,max=200"`
+	PartyID    int64  `form:"party_id" binding:"omitempty,min=1"`
+	DistrictID int64  `form:"district_id" binding:"omitempty,min=1"`
+	ElectionID int64  `form:"election_id" binding:"omitempty,min=1"`
+	Sort       string `form:"sort" binding:"omitempty,oneof=id name -name votes -votes created -created relevance"`
+	Withdrawn  bool   `form:"withdrawn"`
 }
 
+// candidateQuery is what a candidate page is searched, filtered and sorted by
+type candidateQuery struct {
+	Search     string `json:"q,omitempty"`
+	PartyID    int64  `json:"party_id,omitempty"`
+	DistrictID int64  `json:"district_id,omitempty"`
+	ElectionID int64  `json:"election_id,omitempty"`
+	Sort       string `json:"sort,omitempty"`
+	Withdrawn  bool   `json:"withdrawn,omitempty"`
+}
+
+// candidateCursor is the position after the last candidate of a page, it keeps the
+// query it was made for so it cannot be replayed with other filters or sorting
+type candidateCursor struct {
+	Query    candidateQuery `json:"query"`
+	ID       int64          `json:"id"`
+	Name     string         `json:"name,omitempty"`
+	Votes    int64          `json:"votes,omitempty"`
+	CreateAt time.Time      `json:"create_at"`
+	Rank     float64        `json:"rank,omitempty"`
+}
+
+type candidateSummary struct {
+	ID                int64     `json:"id"`
+	Name              string    `json:"name"`
+	Dob               string    `json:"dob"`
+	BioLink           string    `json:"bio_link"`
+	ImageUrl          string    `json:"image_url"`
+	Policy            string    `json:"policy"`
+	VoteCount         int32     `json:"vote_count"`
+	WeightedVoteCount int64     `json:"weighted_vote_count"`
+	PartyID           int64     `json:"party_id,omitempty"`
+	DistrictID        int64     `json:"district_id,omitempty"`
+	ElectionID        int64     `json:"election_id"`
+	CreateAt          time.Time `json:"create_at"`
+}
+
+// listCandidatesResponse wraps a page of candidates with the total number of
+// matches, NextCursor and NextPageID are left out on the last page
+type listCandidatesResponse struct {
+	Data       []candidateSummary `json:"data"`
+	Total      int64              `json:"total"`
+	PageID     int32              `json:"page_id,omitempty"`
+	PageSize   int32              `json:"page_size"`
+	HasNext    bool               `json:"has_next"`
+	NextPageID int32              `json:"next_page_id,omitempty"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// newListCandidatesResponse takes one row more than the page size when there is a next page
+func newListCandidatesResponse(rows []db.ListCandidatesRow, total int64, pageSize int32) listCandidatesResponse {
+	hasNext := len(rows) > int(pageSize)
+	if hasNext {
+		rows = rows[:pageSize]
+	}
+
+	rsp := listCandidatesResponse{
+		Data:     make([]candidateSummary, len(rows)),
+		Total:    total,
+		PageSize: pageSize,
+		HasNext:  hasNext,
+	}
+
+	for i, row := range rows {
+		rsp.Data[i] = candidateSummary{
+			ID:                row.ID,
+			Name:              row.Name,
+			Dob:               row.Dob,
+			BioLink:           row.BioLink,
+			ImageUrl:          row.ImageUrl,
+			Policy:            row.Policy,
+			VoteCount:         row.VoteCount,
+			WeightedVoteCount: row.WeightedVoteCount,
+			PartyID:           row.PartyID.Int64,
+			DistrictID:        row.DistrictID.Int64,
+			ElectionID:        row.ElectionID,
+			CreateAt:          row.CreateAt,
+		}
+	}
+
+	return rsp
+}
+
+// listCandidates searches the name and policy of the candidates on the ballot, narrowed
+// by party, district and election, sorted by the sort option or by ID. withdrawn=true
+// lists the withdrawn candidates instead.
+// Pages are read after the signed cursor of the previous page, page_id keeps
+// the offset pagination for older clients.
 func (server Server) listCandidates(ctx *gin.Context) {
 	var req listCandidateRequest
 	if err := ctx.ShouldBindQuery(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	query := candidateQuery{
+		Search:     strings.TrimSpace(req.Search),
+		PartyID:    req.PartyID,
+		DistrictID: req.DistrictID,
+		ElectionID: req.ElectionID,
+		Sort:       req.Sort,
+		Withdrawn:  req.Withdrawn,
+	}
+	if query.Sort == "relevance" && query.Search == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrRelevanceNeedsSearch))
 		return
 	}
 
 	arg := db.ListCandidatesParams{
-		Limit:  req.PageSize,
-		Offset: (req.PageID - 1) * req.PageSize,
+		Search:     query.Search,
+		PartyID:    query.PartyID,
+		DistrictID: query.DistrictID,
+		ElectionID: query.ElectionID,
+		Withdrawn:  query.Withdrawn,
+		Sort:       query.Sort,
+		Limit:      req.PageSize + 1,
+	}
+
+	if req.PageID > 0 {
+		arg.Offset = (req.PageID - 1) * req.PageSize
+	}
+
+	if req.Cursor != "" {
+		var after candidateCursor
+		if err := util.VerifyCursor(server.config.TokenSymmetricKey, req.Cursor, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+
+		if after.Query != query || after.ID <= 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, util.ErrInvalidCursor))
+			return
+		}
+
+		arg.AfterID = after.ID
+		arg.AfterName = after.Name
+		arg.AfterVotes = after.Votes
+		arg.AfterCreateAt = after.CreateAt
+		arg.AfterRank = after.Rank
+	}
+
+	total, err := server.store.CountCandidates(ctx, db.CountCandidatesParams{
+		Search:     query.Search,
+		PartyID:    query.PartyID,
+		DistrictID: query.DistrictID,
+		ElectionID: query.ElectionID,
+		Withdrawn:  query.Withdrawn,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
 	}
 
 	candidates, err := server.store.ListCandidates(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, candidates)
+	rsp := newListCandidatesResponse(candidates, total, req.PageSize)
+	if rsp.HasNext {
+		last := candidates[req.PageSize-1]
+
+		next := candidateCursor{
+			Query:    query,
+			ID:       last.ID,
+			Name:     last.Name,
+			Votes:    last.WeightedVoteCount,
+			CreateAt: last.CreateAt,
+			Rank:     last.Rank,
+		}
+
+		rsp.NextCursor, err = util.SignCursor(server.config.TokenSymmetricKey, next)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	if req.PageID > 0 {
+		rsp.PageID = req.PageID
+		if rsp.HasNext {
+			rsp.NextPageID = req.PageID + 1
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 
 }
 
 type updateCandidateRequest struct {
-	CandidateId int64  `json:"candidateId" binding:"required,min=1"`
-	Name        string `json:"name" binding:"required"`
-	Dob         string `json:"dob" binding:"required,dateOfBirth"`
-	BioLink     string `json:"bioLink" binding:"required,url"`
-	ImageLink   string `json:"imageLink" binding:"required,url"`
-	Policy      string `json:"policy" binding:"required"`
+	CandidateId int64           `json:"candidateId" binding:"required,min=1"`
+	Name        string          `json:"name" binding:"required"`
+	Dob         string          `json:"dob" binding:"required,dateOfBirth"`
+	BioLink     string          `json:"bioLink" binding:"required,url"`
+	ImageLink   string          `json:"imageLink" binding:"omitempty,url"`
+	Policy      string          `json:"policy" binding:"required"`
+	PolicyItems []policyItem    `json:"policyItems" binding:"omitempty,dive"`
+	Links       []candidateLink `json:"links" binding:"omitempty,dive"`
+	PartyID     int64           `json:"partyId" binding:"omitempty,min=1"`
+	DistrictID  int64           `json:"districtId" binding:"omitempty,min=1"`
 }
 
 func (server Server) updateCandidate(ctx *gin.Context) {
 	var req updateCandidateRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	current, valid := server.editableCandidate(ctx, req.CandidateId)
+	if !valid {
 		return
 	}
 
+	imageUrl := req.ImageLink
+	if imageUrl == "" {
+		// keep an uploaded image when no external link is given
+		imageUrl = current.ImageUrl
+	}
+
+	policyItems, links, err := marshalProfileLists(req.PolicyItems, req.Links)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
 	arg := db.UpdateCandidateParams{
-		ID:       req.CandidateId,
-		Name:     req.Name,
-		Dob:      req.Dob,
-		BioLink:  req.BioLink,
-		ImageUrl: req.ImageLink,
-		Policy:   req.Policy,
+		ID:          req.CandidateId,
+		Name:        req.Name,
+		Dob:         req.Dob,
+		BioLink:     req.BioLink,
+		ImageUrl:    imageUrl,
+		Policy:      req.Policy,
+		DistrictID:  nullDistrictID(req.DistrictID),
+		PartyID:     nullPartyID(req.PartyID),
+		PolicyItems: policyItems,
+		Links:       links,
+		EditedBy:    sql.NullString{String: authPayload.NationalID, Valid: true},
+	}
+
+	server.saveCandidate(ctx, arg)
+}
+
+// editableCandidate returns the candidate when its profile may still be edited, once any
+// ballot was cast in its election the profile is locked. The update locks the election row
+// and checks again, so a first ballot cast in between is ordered before or after the edit.
+func (server Server) editableCandidate(ctx *gin.Context, candidateID int64) (db.GetCandidateRow, bool) {
+	candidate, err := server.store.GetCandidate(ctx, candidateID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.GetCandidateRow{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.GetCandidateRow{}, false
+	}
+
+	if candidate.ElectionVotingStarted {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrProfileLocked))
+		return db.GetCandidateRow{}, false
 	}
 
+	return candidate, true
+}
+
+// saveCandidate updates the candidate profile, the database records the new version
+func (server Server) saveCandidate(ctx *gin.Context, arg db.UpdateCandidateParams) {
 	candidate, err := server.store.UpdateCandidate(ctx, arg)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			// the candidate was found editable, the first ballot came in since
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrProfileLocked))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	rsp := candidateResponse{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
-		CreateAt:  candidate.CreateAt,
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		Dob:        candidate.Dob,
+		BioLink:    candidate.BioLink,
+		ImageUrl:   candidate.ImageUrl,
+		Policy:     candidate.Policy,
+		PartyID:    candidate.PartyID.Int64,
+		Version:    candidate.Version,
+		VoteCount:  candidate.VoteCount,
+		DistrictID: candidate.DistrictID.Int64,
+		CreateAt:   candidate.CreateAt,
+	}
+	if !rsp.setProfileLists(ctx, candidate.PolicyItems, candidate.Links) {
+		return
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
 }
 
-type deleteCandidateRequest struct {
-	Id int64 `uri:"id" binding:"required,min=1"`
+type withdrawCandidateRequest struct {
+	Reason string `form:"reason" binding:"required,min=5,max=500"`
+}
+
+// withdrawCandidate takes a candidate off the ballot, the candidate and any votes
+// already cast stay in the database. A candidate who received votes can only be
+// withdrawn while the WITHDRAW_AFTER_VOTES policy is enabled.
+func (server Server) withdrawCandidate(ctx *gin.Context) {
+	var uri getCandidateRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req withdrawCandidateRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	candidate, err := server.store.GetCandidate(ctx, uri.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if candidate.WithdrawnAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateWithdrawn))
+		return
+	}
+
+	if candidate.ElectionClosed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return
+	}
+
+	allowVoted := false
+	if candidate.VoteCount > 0 {
+		withdrawAfterVotes, err := server.store.GetElectionProperty(ctx, util.WithdrawAfterVotes)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		if !withdrawAfterVotes.Value {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateHasVotes))
+			return
+		}
+		allowVoted = true
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.WithdrawCandidateParams{
+		ID:              candidate.ID,
+		WithdrawnReason: strings.TrimSpace(req.Reason),
+		WithdrawnBy:     sql.NullString{String: authPayload.NationalID, Valid: true},
+		AllowVoted:      allowVoted,
+	}
+
+	withdrawn, err := server.store.WithdrawCandidate(ctx, arg)
+	if err != nil {
+		// the first vote arrived after the check above
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateHasVotes))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	writeCandidate(ctx, withdrawn)
 }
 
-func (server Server) deleteCandidate(ctx *gin.Context) {
-	var req deleteCandidateRequest
+// restoreCandidate puts a withdrawn candidate back on the ballot
+func (server Server) restoreCandidate(ctx *gin.Context) {
+	var req getCandidateRequest
 	if err := ctx.ShouldBindUri(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	//TODO: replace with more efficient query for checking candidate exist e.g. select top 1
 	candidate, err := server.store.GetCandidate(ctx, req.Id)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if !candidate.WithdrawnAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateNotWithdrawn))
+		return
+	}
+
+	if candidate.ElectionClosed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
 		return
 	}
 
-	err = server.store.DeleteCandidate(ctx, candidate.ID)
+	restored, err := server.store.RestoreCandidate(ctx, candidate.ID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateNotWithdrawn))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse())
+	writeCandidate(ctx, restored)
+}
+
+func writeCandidate(ctx *gin.Context, candidate db.Candidate) {
+	rsp := candidateResponse{
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		Dob:        candidate.Dob,
+		BioLink:    candidate.BioLink,
+		ImageUrl:   candidate.ImageUrl,
+		Policy:     candidate.Policy,
+		PartyID:    candidate.PartyID.Int64,
+		Version:    candidate.Version,
+		VoteCount:  candidate.VoteCount,
+		DistrictID: candidate.DistrictID.Int64,
+		ContestID:  candidate.ContestID.Int64,
+		CreateAt:   candidate.CreateAt,
+
+		WithdrawnAt:     nullTimePtr(candidate.WithdrawnAt),
+		WithdrawnReason: candidate.WithdrawnReason,
+	}
+	if !rsp.setProfileLists(ctx, candidate.PolicyItems, candidate.Links) {
+		return
+	}
 
+	ctx.JSON(http.StatusOK, rsp)
 }
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	db "election/db/sqlc"
+	"election/token"
+
+	"github.com/gin-gonic/gin"
+)
+
+type candidateRevisionResponse struct {
+	Version  int32           `json:"version"`
+	Profile  json.RawMessage `json:"profile"`
+	EditedBy string          `json:"edited_by,omitempty"`
+	CreateAt time.Time       `json:"create_at"`
+}
+
+func newCandidateRevisionResponse(revision db.CandidateRevision) candidateRevisionResponse {
+	return candidateRevisionResponse{
+		Version:  revision.Version,
+		Profile:  revision.Profile,
+		EditedBy: revision.EditedBy.String,
+		CreateAt: revision.CreateAt,
+	}
+}
+
+func (server Server) listCandidateRevisions(ctx *gin.Context) {
+	var req getCandidateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	revisions, err := server.store.ListCandidateRevisions(ctx, req.Id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if len(revisions) == 0 {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, sql.ErrNoRows))
+		return
+	}
+
+	rsp := make([]candidateRevisionResponse, len(revisions))
+	for i, revision := range revisions {
+		rsp[i] = newCandidateRevisionResponse(revision)
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type diffCandidateRevisionsRequest struct {
+	From int32 `form:"from" binding:"required,min=1"`
+	To   int32 `form:"to" binding:"required,min=1"`
+}
+
+type profileChange struct {
+	Field string          `json:"field"`
+	From  json.RawMessage `json:"from"`
+	To    json.RawMessage `json:"to"`
+}
+
+type candidateRevisionDiffResponse struct {
+	From    int32           `json:"from"`
+	To      int32           `json:"to"`
+	Changes []profileChange `json:"changes"`
+}
+
+func (server Server) diffCandidateRevisions(ctx *gin.Context) {
+	var uri getCandidateRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req diffCandidateRevisionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	from, valid := server.candidateRevision(ctx, uri.Id, req.From)
+	if !valid {
+		return
+	}
+
+	to, valid := server.candidateRevision(ctx, uri.Id, req.To)
+	if !valid {
+		return
+	}
+
+	changes, err := diffProfiles(from.Profile, to.Profile)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := candidateRevisionDiffResponse{
+		From:    from.Version,
+		To:      to.Version,
+		Changes: changes,
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// diffProfiles lists the profile fields whose value differs between two snapshots,
+// ordered by field name
+func diffProfiles(from, to json.RawMessage) ([]profileChange, error) {
+	var fromFields, toFields map[string]json.RawMessage
+	if err := json.Unmarshal(from, &fromFields); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &toFields); err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]bool, len(fromFields)+len(toFields))
+	for field := range fromFields {
+		fields[field] = true
+	}
+	for field := range toFields {
+		fields[field] = true
+	}
+
+	changes := []profileChange{}
+	for field := range fields {
+		fromValue, toValue := profileValue(fromFields[field]), profileValue(toFields[field])
+		if bytes.Equal(fromValue, toValue) {
+			continue
+		}
+		changes = append(changes, profileChange{Field: field, From: fromValue, To: toValue})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes, nil
+}
+
+// profileValue compacts a snapshot value so formatting does not count as a change,
+// a missing field reads as null
+func profileValue(value json.RawMessage) json.RawMessage {
+	if len(value) == 0 {
+		return json.RawMessage("null")
+	}
+
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, value); err != nil {
+		return value
+	}
+	return compacted.Bytes()
+}
+
+type rollbackCandidateRequest struct {
+	Id      int64 `uri:"id" binding:"required,min=1"`
+	Version int32 `uri:"version" binding:"required,min=1"`
+}
+
+// candidateProfile is the snapshot of a candidate profile stored with each revision
+type candidateProfile struct {
+	Name        string          `json:"name"`
+	Dob         string          `json:"dob"`
+	BioLink     string          `json:"bio_link"`
+	ImageUrl    string          `json:"image_url"`
+	Policy      string          `json:"policy"`
+	PolicyItems json.RawMessage `json:"policy_items"`
+	Links       json.RawMessage `json:"links"`
+	PartyID     *int64          `json:"party_id"`
+	DistrictID  *int64          `json:"district_id"`
+}
+
+func (server Server) rollbackCandidate(ctx *gin.Context) {
+	var req rollbackCandidateRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if _, valid := server.editableCandidate(ctx, req.Id); !valid {
+		return
+	}
+
+	revision, valid := server.candidateRevision(ctx, req.Id, req.Version)
+	if !valid {
+		return
+	}
+
+	var profile candidateProfile
+	if err := json.Unmarshal(revision.Profile, &profile); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	var districtID, partyID int64
+	if profile.DistrictID != nil {
+		districtID = *profile.DistrictID
+	}
+	if profile.PartyID != nil {
+		partyID = *profile.PartyID
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.UpdateCandidateParams{
+		ID:          req.Id,
+		Name:        profile.Name,
+		Dob:         profile.Dob,
+		BioLink:     profile.BioLink,
+		ImageUrl:    profile.ImageUrl,
+		Policy:      profile.Policy,
+		DistrictID:  nullDistrictID(districtID),
+		PartyID:     nullPartyID(partyID),
+		PolicyItems: profileList(profile.PolicyItems),
+		Links:       profileList(profile.Links),
+		EditedBy:    sql.NullString{String: authPayload.NationalID, Valid: true},
+	}
+
+	server.saveCandidate(ctx, arg)
+}
+
+// profileList defaults a list missing from an older snapshot to an empty list
+func profileList(list json.RawMessage) json.RawMessage {
+	if len(list) == 0 || bytes.Equal(list, []byte("null")) {
+		return json.RawMessage("[]")
+	}
+	return list
+}
+
+func (server Server) candidateRevision(ctx *gin.Context, candidateID int64, version int32) (db.CandidateRevision, bool) {
+	revision, err := server.store.GetCandidateRevision(ctx, db.GetCandidateRevisionParams{
+		CandidateID: candidateID,
+		Version:     version,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.CandidateRevision{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.CandidateRevision{}, false
+	}
+
+	return revision, true
+}
@@ -0,0 +1,401 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListCandidateRevisionsAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	revisions := []db.CandidateRevision{
+		randomCandidateRevision(t, candidate, 1),
+		randomCandidateRevision(t, candidate, 2),
+	}
+
+	testCases := []struct {
+		name          string
+		candidateID   int64
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			candidateID: candidate.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidateRevisions(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(revisions, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp []candidateRevisionResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Len(t, rsp, len(revisions))
+				for i, revision := range revisions {
+					require.Equal(t, revision.Version, rsp[i].Version)
+					require.Equal(t, revision.EditedBy.String, rsp[i].EditedBy)
+					require.JSONEq(t, string(revision.Profile), string(rsp[i].Profile))
+				}
+			},
+		},
+		{
+			name:        "NotFound",
+			candidateID: candidate.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidateRevisions(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return([]db.CandidateRevision{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "InternalError",
+			candidateID: candidate.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidateRevisions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:        "InvalidID",
+			candidateID: 0,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidateRevisions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/candidates/%d/revisions", tc.candidateID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDiffCandidateRevisionsAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	from := randomCandidateRevision(t, candidate, 1)
+
+	edited := candidate
+	edited.Policy = util.RandomString(15)
+	edited.PartyID = sql.NullInt64{Int64: candidate.PartyID.Int64 + 1, Valid: true}
+	to := randomCandidateRevision(t, edited, 2)
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "from=1&to=2",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Eq(db.GetCandidateRevisionParams{CandidateID: candidate.ID, Version: 1})).
+					Times(1).
+					Return(from, nil)
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Eq(db.GetCandidateRevisionParams{CandidateID: candidate.ID, Version: 2})).
+					Times(1).
+					Return(to, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp candidateRevisionDiffResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, int32(1), rsp.From)
+				require.Equal(t, int32(2), rsp.To)
+				require.Len(t, rsp.Changes, 2)
+
+				require.Equal(t, "party_id", rsp.Changes[0].Field)
+				require.JSONEq(t, fmt.Sprint(candidate.PartyID.Int64), string(rsp.Changes[0].From))
+				require.JSONEq(t, fmt.Sprint(edited.PartyID.Int64), string(rsp.Changes[0].To))
+				require.Equal(t, "policy", rsp.Changes[1].Field)
+				require.JSONEq(t, fmt.Sprintf("%q", edited.Policy), string(rsp.Changes[1].To))
+			},
+		},
+		{
+			name:  "RevisionNotFound",
+			query: "from=1&to=9",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Eq(db.GetCandidateRevisionParams{CandidateID: candidate.ID, Version: 1})).
+					Times(1).
+					Return(from, nil)
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Eq(db.GetCandidateRevisionParams{CandidateID: candidate.ID, Version: 9})).
+					Times(1).
+					Return(db.CandidateRevision{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingVersion",
+			query: "from=1",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/candidates/%d/revisions/diff?%s", candidate.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestRollbackCandidateAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	revision := randomCandidateRevision(t, candidate, 1)
+	currentRow := db.GetCandidateRow{
+		ID:         candidate.ID,
+		Policy:     util.RandomString(15),
+		ElectionID: candidate.ElectionID,
+	}
+	lockedCurrentRow := currentRow
+	lockedCurrentRow.ElectionVotingStarted = true
+
+	testCases := []struct {
+		name          string
+		version       int32
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			version: 1,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(currentRow, nil)
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Eq(db.GetCandidateRevisionParams{CandidateID: candidate.ID, Version: 1})).
+					Times(1).
+					Return(revision, nil)
+
+				arg := db.UpdateCandidateParams{
+					ID:          candidate.ID,
+					Name:        candidate.Name,
+					Dob:         candidate.Dob,
+					BioLink:     candidate.BioLink,
+					ImageUrl:    candidate.ImageUrl,
+					Policy:      candidate.Policy,
+					PartyID:     candidate.PartyID,
+					PolicyItems: candidate.PolicyItems,
+					Links:       candidate.Links,
+					EditedBy:    sql.NullString{String: user.NationalID, Valid: true},
+				}
+
+				store.EXPECT().
+					UpdateCandidate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.UpdateCandidateRow{
+						ID:          candidate.ID,
+						Name:        candidate.Name,
+						Dob:         candidate.Dob,
+						BioLink:     candidate.BioLink,
+						ImageUrl:    candidate.ImageUrl,
+						Policy:      candidate.Policy,
+						PartyID:     candidate.PartyID,
+						PolicyItems: candidate.PolicyItems,
+						Links:       candidate.Links,
+						Version:     3,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				rspCandidate := NewCandidateResponse(candidate)
+				rspCandidate.Version = 3
+				requireBodyMatchCandidateResponse(t, recorder.Body, rspCandidate)
+			},
+		},
+		{
+			name:    "ProfileLocked",
+			version: 1,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(lockedCurrentRow, nil)
+				store.EXPECT().
+					UpdateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "RevisionNotFound",
+			version: 7,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(currentRow, nil)
+				store.EXPECT().
+					GetCandidateRevision(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CandidateRevision{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpdateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NoAuthorization",
+			version: 1,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/candidates/%d/revisions/%d/rollback", candidate.ID, tc.version)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func randomCandidateRevision(t *testing.T, candidate db.Candidate, version int32) db.CandidateRevision {
+	profile, err := json.Marshal(candidateProfile{
+		Name:        candidate.Name,
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      candidate.Policy,
+		PolicyItems: candidate.PolicyItems,
+		Links:       candidate.Links,
+		PartyID:     &candidate.PartyID.Int64,
+	})
+	require.NoError(t, err)
+
+	return db.CandidateRevision{
+		ID:          util.RandomInt(1, 1000),
+		CandidateID: candidate.ID,
+		Version:     version,
+		Profile:     profile,
+		EditedBy:    sql.NullString{String: "1234567890123", Valid: true},
+		CreateAt:    time.Now().UTC().Truncate(time.Second),
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -27,6 +28,7 @@ func TestCreateCandidateAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
 	candidate := RandomCandidate()
 	rspCandidate := NewCandidateResponse(candidate)
+	contest := RandomContest()
 
 	testCases := []struct {
 		name          string
@@ -38,11 +40,14 @@ func TestCreateCandidateAPI(t *testing.T) {
 		{
 			name: "OK",
 			body: gin.H{
-				"name":      candidate.Name,
-				"dob":       candidate.Dob,
-				"bioLink":   candidate.BioLink,
-				"imageLink": candidate.ImageUrl,
-				"policy":    candidate.Policy,
+				"name":        candidate.Name,
+				"dob":         candidate.Dob,
+				"bioLink":     candidate.BioLink,
+				"imageLink":   candidate.ImageUrl,
+				"policy":      candidate.Policy,
+				"policyItems": rspCandidate.PolicyItems,
+				"links":       rspCandidate.Links,
+				"partyId":     candidate.PartyID.Int64,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
@@ -50,12 +55,17 @@ func TestCreateCandidateAPI(t *testing.T) {
 			buildStub: func(store *mockdb.MockStore) {
 
 				arg := db.CreateCandidateParams{
-					Name:      candidate.Name,
-					Dob:       candidate.Dob,
-					BioLink:   candidate.BioLink,
-					ImageUrl:  candidate.ImageUrl,
-					Policy:    candidate.Policy,
-					VoteCount: 0,
+					Name:        candidate.Name,
+					Dob:         candidate.Dob,
+					BioLink:     candidate.BioLink,
+					ImageUrl:    candidate.ImageUrl,
+					Policy:      candidate.Policy,
+					VoteCount:   0,
+					PartyID:     candidate.PartyID,
+					PolicyItems: candidate.PolicyItems,
+					Links:       candidate.Links,
+					EditedBy:    sql.NullString{String: user.NationalID, Valid: true},
+					ElectionID:  1,
 				}
 
 				store.EXPECT().
@@ -90,6 +100,114 @@ func TestCreateCandidateAPI(t *testing.T) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "OKContest",
+			body: gin.H{
+				"name":      candidate.Name,
+				"dob":       candidate.Dob,
+				"bioLink":   candidate.BioLink,
+				"imageLink": candidate.ImageUrl,
+				"policy":    candidate.Policy,
+				"contestId": contest.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetContest(gomock.Any(), gomock.Eq(contest.ID)).
+					Times(1).
+					Return(contest, nil)
+
+				contestCandidate := candidate
+				contestCandidate.ElectionID = contest.ElectionID
+				contestCandidate.ContestID = sql.NullInt64{Int64: contest.ID, Valid: true}
+				store.EXPECT().
+					CreateCandidate(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateCandidateParams) (db.Candidate, error) {
+						require.Equal(t, contest.ElectionID, arg.ElectionID)
+						require.Equal(t, sql.NullInt64{Int64: contest.ID, Valid: true}, arg.ContestID)
+						return contestCandidate, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp candidateResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, contest.ID, rsp.ContestID)
+			},
+		},
+		{
+			name: "ContestNotFound",
+			body: gin.H{
+				"name":      candidate.Name,
+				"dob":       candidate.Dob,
+				"bioLink":   candidate.BioLink,
+				"imageLink": candidate.ImageUrl,
+				"policy":    candidate.Policy,
+				"contestId": contest.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetContest(gomock.Any(), gomock.Eq(contest.ID)).
+					Times(1).
+					Return(db.Contest{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidPolicyItem",
+			body: gin.H{
+				"name":        candidate.Name,
+				"dob":         candidate.Dob,
+				"bioLink":     candidate.BioLink,
+				"policy":      candidate.Policy,
+				"policyItems": []gin.H{{"title": "Health"}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidLink",
+			body: gin.H{
+				"name":    candidate.Name,
+				"dob":     candidate.Dob,
+				"bioLink": candidate.BioLink,
+				"policy":  candidate.Policy,
+				"links":   []gin.H{{"label": "Website", "url": "Invalid"}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name: "InvalidBioLink",
 			body: gin.H{
@@ -192,13 +310,17 @@ func TestGetCandidateAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
 	candidate := RandomCandidate()
 	resultRow := db.GetCandidateRow{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
+		ID:          candidate.ID,
+		Name:        candidate.Name,
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      candidate.Policy,
+		VoteCount:   candidate.VoteCount,
+		PartyID:     candidate.PartyID,
+		PolicyItems: candidate.PolicyItems,
+		Links:       candidate.Links,
+		Version:     candidate.Version,
 	}
 	rspCandidate := NewCandidateResponse(candidate)
 
@@ -308,24 +430,31 @@ func TestGetCandidateAPI(t *testing.T) {
 func TestListCandidatesAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
 	n := 5
-	candidates := make([]db.Candidate, n)
-	resultRows := make([]db.ListCandidatesRow, n)
-	for i := 0; i < n; i++ {
+	candidates := make([]db.Candidate, n+1)
+	resultRows := make([]db.ListCandidatesRow, n+1)
+	for i := 0; i <= n; i++ {
 		candidates[i] = RandomCandidate()
 		resultRows[i] = db.ListCandidatesRow{
-			ID:        candidates[i].ID,
-			Name:      candidates[i].Name,
-			Dob:       candidates[i].Dob,
-			BioLink:   candidates[i].BioLink,
-			ImageUrl:  candidates[i].ImageUrl,
-			Policy:    candidates[i].Policy,
-			VoteCount: candidates[i].VoteCount,
+			ID:         candidates[i].ID,
+			Name:       candidates[i].Name,
+			Dob:        candidates[i].Dob,
+			BioLink:    candidates[i].BioLink,
+			ImageUrl:   candidates[i].ImageUrl,
+			Policy:     candidates[i].Policy,
+			VoteCount:  candidates[i].VoteCount,
+			PartyID:    candidates[i].PartyID,
+			ElectionID: candidates[i].ElectionID,
 		}
 	}
 
 	type Query struct {
-		pageID   int
-		pageSize int
+		pageID     int
+		pageSize   int
+		search     string
+		partyID    int64
+		districtID int64
+		electionID int64
+		sort       string
 	}
 
 	testCases := []struct {
@@ -345,9 +474,13 @@ func TestListCandidatesAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountCandidates(gomock.Any(), gomock.Eq(db.CountCandidatesParams{})).
+					Times(1).
+					Return(int64(12), nil)
 
 				arg := db.ListCandidatesParams{
-					Limit:  int32(n),
+					Limit:  int32(n + 1),
 					Offset: 0,
 				}
 
@@ -358,7 +491,101 @@ func TestListCandidatesAPI(t *testing.T) {
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchCandidates(t, recorder.Body, resultRows)
+				rsp := requireBodyMatchCandidates(t, recorder.Body, resultRows[:n])
+				require.Equal(t, int64(12), rsp.Total)
+				require.True(t, rsp.HasNext)
+				require.Equal(t, int32(2), rsp.NextPageID)
+				require.NotEmpty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name: "SearchWithFilters",
+			query: Query{
+				pageID:     3,
+				pageSize:   n,
+				search:     "  clean water  ",
+				partyID:    candidates[0].PartyID.Int64,
+				districtID: 7,
+				electionID: candidates[0].ElectionID,
+				sort:       "relevance",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				countArg := db.CountCandidatesParams{
+					Search:     "clean water",
+					PartyID:    candidates[0].PartyID.Int64,
+					DistrictID: 7,
+					ElectionID: candidates[0].ElectionID,
+				}
+				store.EXPECT().
+					CountCandidates(gomock.Any(), gomock.Eq(countArg)).
+					Times(1).
+					Return(int64(12), nil)
+
+				arg := db.ListCandidatesParams{
+					Search:     "clean water",
+					PartyID:    candidates[0].PartyID.Int64,
+					DistrictID: 7,
+					ElectionID: candidates[0].ElectionID,
+					Sort:       "relevance",
+					Limit:      int32(n + 1),
+					Offset:     int32(2 * n),
+				}
+				store.EXPECT().
+					ListCandidates(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(resultRows[:2], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				rsp := requireBodyMatchCandidates(t, recorder.Body, resultRows[:2])
+				require.Equal(t, int64(12), rsp.Total)
+				require.False(t, rsp.HasNext)
+				require.Zero(t, rsp.NextPageID)
+				require.Empty(t, rsp.NextCursor)
+			},
+		},
+		{
+			name: "RelevanceWithoutSearch",
+			query: Query{
+				pageID:   1,
+				pageSize: n,
+				sort:     "relevance",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountCandidates(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ListCandidates(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSort",
+			query: Query{
+				pageID:   1,
+				pageSize: n,
+				sort:     "dob",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidates(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
@@ -371,6 +598,10 @@ func TestListCandidatesAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CountCandidates(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(n), nil)
 				store.EXPECT().
 					ListCandidates(gomock.Any(), gomock.Any()).
 					Times(1).
@@ -441,6 +672,21 @@ func TestListCandidatesAPI(t *testing.T) {
 			q := request.URL.Query()
 			q.Add("page_id", fmt.Sprintf("%d", tc.query.pageID))
 			q.Add("page_size", fmt.Sprintf("%d", tc.query.pageSize))
+			if tc.query.search != "" {
+				q.Add("q", tc.query.search)
+			}
+			if tc.query.partyID != 0 {
+				q.Add("party_id", fmt.Sprintf("%d", tc.query.partyID))
+			}
+			if tc.query.districtID != 0 {
+				q.Add("district_id", fmt.Sprintf("%d", tc.query.districtID))
+			}
+			if tc.query.electionID != 0 {
+				q.Add("election_id", fmt.Sprintf("%d", tc.query.electionID))
+			}
+			if tc.query.sort != "" {
+				q.Add("sort", tc.query.sort)
+			}
 			request.URL.RawQuery = q.Encode()
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -454,18 +700,147 @@ func TestListCandidatesAPI(t *testing.T) {
 
 }
 
+func TestListCandidatesCursorAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	n := 5
+	resultRows := make([]db.ListCandidatesRow, n+1)
+	for i := range resultRows {
+		candidate := RandomCandidate()
+		resultRows[i] = db.ListCandidatesRow{
+			ID:                candidate.ID,
+			Name:              candidate.Name,
+			WeightedVoteCount: util.RandomInt(1, 100),
+			ElectionID:        candidate.ElectionID,
+			CreateAt:          time.Now().UTC().Truncate(time.Microsecond),
+			Rank:              0.0607927,
+		}
+	}
+	last := resultRows[n-1]
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	listCandidates := func(query url.Values) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, "/api/candidates?"+query.Encode(), nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	store.EXPECT().
+		CountCandidates(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(int64(20), nil)
+
+	firstArg := db.ListCandidatesParams{
+		Search: "water",
+		Sort:   "relevance",
+		Limit:  int32(n + 1),
+	}
+	store.EXPECT().
+		ListCandidates(gomock.Any(), gomock.Eq(firstArg)).
+		Times(1).
+		Return(resultRows, nil)
+
+	query := url.Values{}
+	query.Add("page_size", fmt.Sprintf("%d", n))
+	query.Add("q", "water")
+	query.Add("sort", "relevance")
+
+	recorder := listCandidates(query)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	rsp := requireBodyMatchCandidates(t, recorder.Body, resultRows[:n])
+	require.True(t, rsp.HasNext)
+	require.Zero(t, rsp.PageID)
+	require.Zero(t, rsp.NextPageID)
+	require.NotEmpty(t, rsp.NextCursor)
+
+	nextArg := db.ListCandidatesParams{
+		Search:        "water",
+		Sort:          "relevance",
+		AfterID:       last.ID,
+		AfterName:     last.Name,
+		AfterVotes:    last.WeightedVoteCount,
+		AfterCreateAt: last.CreateAt,
+		AfterRank:     last.Rank,
+		Limit:         int32(n + 1),
+	}
+	store.EXPECT().
+		ListCandidates(gomock.Any(), gomock.Eq(nextArg)).
+		Times(1).
+		Return(resultRows[n:], nil)
+
+	query.Set("cursor", rsp.NextCursor)
+	recorder = listCandidates(query)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	next := requireBodyMatchCandidates(t, recorder.Body, resultRows[n:])
+	require.False(t, next.HasNext)
+	require.Empty(t, next.NextCursor)
+
+	// a cursor is bound to the query it was made for
+	changed := url.Values{}
+	changed.Add("page_size", fmt.Sprintf("%d", n))
+	changed.Add("q", "water")
+	changed.Add("sort", "name")
+	changed.Add("cursor", rsp.NextCursor)
+	recorder = listCandidates(changed)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	tampered := url.Values{}
+	tampered.Add("page_size", fmt.Sprintf("%d", n))
+	tampered.Add("q", "water")
+	tampered.Add("sort", "relevance")
+	tampered.Add("cursor", "x"+rsp.NextCursor)
+	recorder = listCandidates(tampered)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// an offset page and a cursor cannot be asked for together, the binding rejects the request
+	query.Add("page_id", "2")
+	recorder = listCandidates(query)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+	require.Contains(t, recorder.Body.String(), "excluded_with")
+
+	offset := url.Values{}
+	offset.Add("page_size", fmt.Sprintf("%d", n))
+	offset.Add("page_id", "2")
+	offset.Add("cursor", "")
+	store.EXPECT().
+		ListCandidates(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(resultRows[n:], nil)
+	recorder = listCandidates(offset)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
 func TestUpdateCandidateAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
 	candidate := RandomCandidate()
 	resultRow := db.UpdateCandidateRow{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
+		ID:          candidate.ID,
+		Name:        candidate.Name,
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      candidate.Policy,
+		VoteCount:   candidate.VoteCount,
+		PartyID:     candidate.PartyID,
+		PolicyItems: candidate.PolicyItems,
+		Links:       candidate.Links,
+		Version:     candidate.Version,
+	}
+	currentRow := db.GetCandidateRow{
+		ID:         candidate.ID,
+		ImageUrl:   util.RandomImageLink(),
+		ElectionID: candidate.ElectionID,
 	}
+	lockedCurrentRow := currentRow
+	lockedCurrentRow.ElectionVotingStarted = true
 	rspCandidate := NewCandidateResponse(candidate)
 
 	testCases := []struct {
@@ -484,19 +859,70 @@ func TestUpdateCandidateAPI(t *testing.T) {
 				"bioLink":     candidate.BioLink,
 				"imageLink":   candidate.ImageUrl,
 				"policy":      candidate.Policy,
+				"policyItems": rspCandidate.PolicyItems,
+				"links":       rspCandidate.Links,
+				"partyId":     candidate.PartyID.Int64,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(currentRow, nil)
 
 				arg := db.UpdateCandidateParams{
-					ID:       candidate.ID,
-					Name:     candidate.Name,
-					Dob:      candidate.Dob,
-					BioLink:  candidate.BioLink,
-					ImageUrl: candidate.ImageUrl,
-					Policy:   candidate.Policy,
+					ID:          candidate.ID,
+					Name:        candidate.Name,
+					Dob:         candidate.Dob,
+					BioLink:     candidate.BioLink,
+					ImageUrl:    candidate.ImageUrl,
+					Policy:      candidate.Policy,
+					PartyID:     candidate.PartyID,
+					PolicyItems: candidate.PolicyItems,
+					Links:       candidate.Links,
+					EditedBy:    sql.NullString{String: user.NationalID, Valid: true},
+				}
+
+				store.EXPECT().
+					UpdateCandidate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(resultRow, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchCandidateResponse(t, recorder.Body, rspCandidate)
+			},
+		},
+		{
+			name: "KeepUploadedImage",
+			body: gin.H{
+				"candidateId": candidate.ID,
+				"name":        candidate.Name,
+				"dob":         candidate.Dob,
+				"bioLink":     candidate.BioLink,
+				"policy":      candidate.Policy,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(db.GetCandidateRow{ID: candidate.ID, ImageUrl: candidate.ImageUrl}, nil)
+
+				arg := db.UpdateCandidateParams{
+					ID:          candidate.ID,
+					Name:        candidate.Name,
+					Dob:         candidate.Dob,
+					BioLink:     candidate.BioLink,
+					ImageUrl:    candidate.ImageUrl,
+					Policy:      candidate.Policy,
+					PolicyItems: json.RawMessage("[]"),
+					Links:       json.RawMessage("[]"),
+					EditedBy:    sql.NullString{String: user.NationalID, Valid: true},
 				}
 
 				store.EXPECT().
@@ -523,6 +949,10 @@ func TestUpdateCandidateAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(currentRow, nil)
 				store.EXPECT().
 					UpdateCandidate(gomock.Any(), gomock.Any()).
 					Times(1).
@@ -546,13 +976,71 @@ func TestUpdateCandidateAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(db.GetCandidateRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpdateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ProfileLocked",
+			body: gin.H{
+				"candidateId": candidate.ID,
+				"name":        candidate.Name,
+				"dob":         candidate.Dob,
+				"bioLink":     candidate.BioLink,
+				"imageLink":   candidate.ImageUrl,
+				"policy":      candidate.Policy,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(lockedCurrentRow, nil)
+				store.EXPECT().
+					UpdateCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "LockedSinceRead",
+			body: gin.H{
+				"candidateId": candidate.ID,
+				"name":        candidate.Name,
+				"dob":         candidate.Dob,
+				"bioLink":     candidate.BioLink,
+				"imageLink":   candidate.ImageUrl,
+				"policy":      candidate.Policy,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(currentRow, nil)
+
+				// a ballot cast after the read makes the update match no row
 				store.EXPECT().
 					UpdateCandidate(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.UpdateCandidateRow{}, sql.ErrNoRows)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
@@ -678,22 +1166,33 @@ func TestUpdateCandidateAPI(t *testing.T) {
 
 }
 
-func TestDeleteCandidateAPI(t *testing.T) {
+func TestWithdrawCandidateAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
 	candidate := RandomCandidate()
 	resultRow := db.GetCandidateRow{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		Dob:        candidate.Dob,
+		BioLink:    candidate.BioLink,
+		ImageUrl:   candidate.ImageUrl,
+		Policy:     candidate.Policy,
+		ElectionID: candidate.ElectionID,
 	}
+	votedRow := resultRow
+	votedRow.VoteCount = 3
+	withdrawnRow := resultRow
+	withdrawnRow.WithdrawnAt = sql.NullTime{Time: time.Now(), Valid: true}
+	closedRow := resultRow
+	closedRow.ElectionClosed = true
+
+	withdrawn := candidate
+	withdrawn.WithdrawnAt = sql.NullTime{Time: time.Now().UTC().Truncate(time.Second), Valid: true}
+	withdrawn.WithdrawnReason = "Left the race"
 
 	testCases := []struct {
 		name          string
 		candidateID   int64
+		reason        string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStub     func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
@@ -701,6 +1200,7 @@ func TestDeleteCandidateAPI(t *testing.T) {
 		{
 			name:        "OK",
 			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
@@ -710,17 +1210,151 @@ func TestDeleteCandidateAPI(t *testing.T) {
 					Times(1).
 					Return(resultRow, nil)
 				store.EXPECT().
-					DeleteCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					GetElectionProperty(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				arg := db.WithdrawCandidateParams{
+					ID:              candidate.ID,
+					WithdrawnReason: withdrawn.WithdrawnReason,
+					WithdrawnBy:     sql.NullString{String: user.NationalID, Valid: true},
+				}
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
-					Return(nil)
+					Return(withdrawn, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp candidateResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.NotNil(t, rsp.WithdrawnAt)
+				require.WithinDuration(t, withdrawn.WithdrawnAt.Time, *rsp.WithdrawnAt, time.Second)
+				require.Equal(t, withdrawn.WithdrawnReason, rsp.WithdrawnReason)
+			},
+		},
+		{
+			name:        "HasVotes",
+			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(votedRow, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.WithdrawAfterVotes)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.WithdrawAfterVotes, Value: false}, nil)
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "HasVotesPolicyAllows",
+			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(votedRow, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.WithdrawAfterVotes)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.WithdrawAfterVotes, Value: true}, nil)
+
+				arg := db.WithdrawCandidateParams{
+					ID:              candidate.ID,
+					WithdrawnReason: withdrawn.WithdrawnReason,
+					WithdrawnBy:     sql.NullString{String: user.NationalID, Valid: true},
+					AllowVoted:      true,
+				}
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(withdrawn, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "FirstVoteRace",
+			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(resultRow, nil)
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Candidate{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "AlreadyWithdrawn",
+			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(withdrawnRow, nil)
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "ElectionClosed",
+			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(closedRow, nil)
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
 			name:        "GetCandidateInternalError",
 			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
@@ -730,7 +1364,7 @@ func TestDeleteCandidateAPI(t *testing.T) {
 					Times(1).
 					Return(db.GetCandidateRow{}, sql.ErrConnDone)
 				store.EXPECT().
-					DeleteCandidate(gomock.Any(), gomock.Any()).
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -738,8 +1372,9 @@ func TestDeleteCandidateAPI(t *testing.T) {
 			},
 		},
 		{
-			name:        "DeleteCandidateInternalError",
+			name:        "WithdrawCandidateInternalError",
 			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
@@ -749,9 +1384,9 @@ func TestDeleteCandidateAPI(t *testing.T) {
 					Times(1).
 					Return(resultRow, nil)
 				store.EXPECT().
-					DeleteCandidate(gomock.Any(), gomock.Any()).
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(sql.ErrConnDone)
+					Return(db.Candidate{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -760,6 +1395,7 @@ func TestDeleteCandidateAPI(t *testing.T) {
 		{
 			name:        "NotFound",
 			candidateID: candidate.ID,
+			reason:      withdrawn.WithdrawnReason,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
@@ -769,16 +1405,48 @@ func TestDeleteCandidateAPI(t *testing.T) {
 					Times(1).
 					Return(db.GetCandidateRow{}, sql.ErrNoRows)
 				store.EXPECT().
-					DeleteCandidate(gomock.Any(), gomock.Any()).
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
 			},
 		},
+		{
+			name:        "MissingReason",
+			candidateID: candidate.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "ShortReason",
+			candidateID: candidate.ID,
+			reason:      "no",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:        "InvalidID",
 			candidateID: -1,
+			reason:      withdrawn.WithdrawnReason,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
@@ -787,7 +1455,7 @@ func TestDeleteCandidateAPI(t *testing.T) {
 					GetCandidate(gomock.Any(), gomock.Any()).
 					Times(0)
 				store.EXPECT().
-					DeleteCandidate(gomock.Any(), gomock.Any()).
+					WithdrawCandidate(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -815,15 +1483,131 @@ func TestDeleteCandidateAPI(t *testing.T) {
 			request, err := http.NewRequest(http.MethodDelete, url, nil)
 			require.NoError(t, err)
 
+			if tc.reason != "" {
+				q := request.URL.Query()
+				q.Add("reason", tc.reason)
+				request.URL.RawQuery = q.Encode()
+			}
+
 			tc.setupAuth(t, request, server.tokenMaker)
 			server.router.ServeHTTP(recorder, request)
 
 			tc.checkResponse(t, recorder)
-
 		})
+	}
+}
 
+func TestRestoreCandidateAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	withdrawnRow := db.GetCandidateRow{
+		ID:              candidate.ID,
+		ElectionID:      candidate.ElectionID,
+		WithdrawnAt:     sql.NullTime{Time: time.Now(), Valid: true},
+		WithdrawnReason: "Left the race",
 	}
+	activeRow := withdrawnRow
+	activeRow.WithdrawnAt = sql.NullTime{}
 
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(withdrawnRow, nil)
+				store.EXPECT().
+					RestoreCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidate, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp candidateResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.Equal(t, candidate.ID, rsp.ID)
+				require.Nil(t, rsp.WithdrawnAt)
+				require.Empty(t, rsp.WithdrawnReason)
+			},
+		},
+		{
+			name: "NotWithdrawn",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(activeRow, nil)
+				store.EXPECT().
+					RestoreCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(db.GetCandidateRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					RestoreCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(withdrawnRow, nil)
+				store.EXPECT().
+					RestoreCandidate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Candidate{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/candidates/%d/restore", candidate.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
 }
 
 func requireBodyMatchCandidateResponse(t *testing.T, body *bytes.Buffer, candidate candidateResponse) {
@@ -846,36 +1630,52 @@ func requireBodyMatchCandidate(t *testing.T, body *bytes.Buffer, candidate db.Ca
 	require.Equal(t, candidate, gotCandidate)
 }
 
-func requireBodyMatchCandidates(t *testing.T, body *bytes.Buffer, candidates []db.ListCandidatesRow) {
+func requireBodyMatchCandidates(t *testing.T, body *bytes.Buffer, candidates []db.ListCandidatesRow) listCandidatesResponse {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
-	var gotCandidates []db.ListCandidatesRow
+	var gotCandidates listCandidatesResponse
 	err = json.Unmarshal(data, &gotCandidates)
 	require.NoError(t, err)
-	require.Equal(t, candidates, gotCandidates)
+	require.Equal(t, newListCandidatesResponse(candidates, 0, int32(len(candidates))).Data, gotCandidates.Data)
+	return gotCandidates
 }
 
 func RandomCandidate() db.Candidate {
 	return db.Candidate{
-		ID:         util.RandomInt(1, 1000),
-		Name:       util.RandomName(),
-		Dob:        util.RandomDob(),
-		BioLink:    util.RandomBioLink(),
-		ImageUrl:   util.RandomImageLink(),
-		Policy:     util.RandomString(15),
-		VoteCount:  0,
-		Percentage: 0,
+		ID:       util.RandomInt(1, 1000),
+		Name:     util.RandomName(),
+		Dob:      util.RandomDob(),
+		BioLink:  util.RandomBioLink(),
+		ImageUrl: util.RandomImageLink(),
+		Policy:   util.RandomString(15),
+		PartyID:  sql.NullInt64{Int64: util.RandomInt(1, 100), Valid: true},
+		PolicyItems: json.RawMessage(fmt.Sprintf(`[{"title":"%s","body":"%s","category":"%s"}]`,
+			util.RandomString(6), util.RandomString(20), util.RandomString(6))),
+		Links:              json.RawMessage(fmt.Sprintf(`[{"label":"%s","url":"%s"}]`, util.RandomString(6), util.RandomBioLink())),
+		Version:            1,
+		ElectionID:         1,
+		VoteCount:          0,
+		Percentage:         0,
+		WeightedVoteCount:  0,
+		WeightedPercentage: 0,
 	}
 }
 func NewCandidateResponse(candidate db.Candidate) candidateResponse {
-	return candidateResponse{
-		ID:        candidate.ID,
-		Name:      candidate.Name,
-		Dob:       candidate.Dob,
-		BioLink:   candidate.BioLink,
-		ImageUrl:  candidate.ImageUrl,
-		Policy:    candidate.Policy,
-		VoteCount: candidate.VoteCount,
+	rsp := candidateResponse{
+		ID:          candidate.ID,
+		Name:        candidate.Name,
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      candidate.Policy,
+		PolicyItems: []policyItem{},
+		Links:       []candidateLink{},
+		PartyID:     candidate.PartyID.Int64,
+		Version:     candidate.Version,
+		VoteCount:   candidate.VoteCount,
 	}
+	json.Unmarshal(candidate.PolicyItems, &rsp.PolicyItems)
+	json.Unmarshal(candidate.Links, &rsp.Links)
+	return rsp
 }
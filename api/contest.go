@@ -0,0 +1,139 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrContestExists        = errors.New("Election already has a contest with this name")
+	ErrContestsLocked       = errors.New("Contests cannot change once voting has started")
+	ErrPartyListContests    = errors.New("Party-list election cannot have contests")
+	ErrContestNotInElection = errors.New("Contest is not part of the election")
+)
+
+// nullContestID converts an optional contest id of a request to its column value,
+// zero means the main race of the election
+func nullContestID(contestID int64) sql.NullInt64 {
+	return sql.NullInt64{
+		Int64: contestID,
+		Valid: contestID > 0,
+	}
+}
+
+type createContestRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// createContest adds a race to the ballot of an election, e.g. a treasurer next to the president.
+// Candidates without a contest run in the main race of the election. The ballot cannot change
+// once voting has started.
+func (server Server) createContest(ctx *gin.Context) {
+	var uri electionRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createContestRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return
+	}
+
+	if election.VotingStartedAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrContestsLocked))
+		return
+	}
+
+	if election.BallotType == util.BallotTypePartyList {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyListContests))
+		return
+	}
+
+	contest, err := server.store.CreateContest(ctx, db.CreateContestParams{
+		ElectionID: election.ID,
+		Name:       req.Name,
+	})
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrContestExists))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, contest)
+}
+
+type contestRequest struct {
+	ContestID int64 `form:"contest_id" binding:"omitempty,min=1"`
+}
+
+// electionContest looks up the contest of the election a result is asked for,
+// there is no contest for the main race
+func (server Server) electionContest(ctx *gin.Context, electionID, contestID int64) (*db.Contest, bool) {
+	if contestID == 0 {
+		return nil, true
+	}
+
+	contest, err := server.store.GetContest(ctx, contestID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return nil, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return nil, false
+	}
+
+	if contest.ElectionID != electionID {
+		ctx.JSON(http.StatusNotFound, errorResponse(ctx, ErrContestNotInElection))
+		return nil, false
+	}
+
+	return &contest, true
+}
+
+// listContests lists the contests of an election besides its main race
+func (server Server) listContests(ctx *gin.Context) {
+	var uri electionRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	contests, err := server.store.ListElectionContests(ctx, uri.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, contests)
+}
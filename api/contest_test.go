@@ -0,0 +1,321 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateContestAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	election := RandomElection()
+	contest := RandomContest()
+	contest.ElectionID = election.ID
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+
+				arg := db.CreateContestParams{
+					ElectionID: election.ID,
+					Name:       contest.Name,
+				}
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(contest, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchContest(t, recorder.Body, contest)
+			},
+		},
+		{
+			name: "ElectionNotFound",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ClosedElection",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				closed := election
+				closed.Closed = true
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(closed, nil)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "VotingStarted",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				started := election
+				started.VotingStartedAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(started, nil)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PartyListElection",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				partyList := election
+				partyList.BallotType = util.BallotTypePartyList
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(partyList, nil)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateName",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Contest{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "MissingName",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoPermission",
+			body: gin.H{
+				"name": contest.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return([]string{util.Vote}, nil)
+				store.EXPECT().
+					ListUserRoles(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return([]db.UserRole{}, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateContest(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/elections/%d/contests", election.ID)
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestListContestsAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	election := RandomElection()
+	contests := []db.Contest{RandomContest(), RandomContest()}
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(contests, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotContests []db.Contest
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotContests)
+				require.NoError(t, err)
+				require.Len(t, gotContests, len(contests))
+				require.Equal(t, contests[0].Name, gotContests[0].Name)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/elections/%d/contests", election.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func RandomContest() db.Contest {
+	return db.Contest{
+		ID:         util.RandomInt(1, 1000),
+		ElectionID: 1,
+		Name:       util.RandomString(10),
+	}
+}
+
+func requireBodyMatchContest(t *testing.T, body *bytes.Buffer, contest db.Contest) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotContest db.Contest
+	err = json.Unmarshal(data, &gotContest)
+	require.NoError(t, err)
+	require.Equal(t, contest.ID, gotContest.ID)
+	require.Equal(t, contest.ElectionID, gotContest.ElectionID)
+	require.Equal(t, contest.Name, gotContest.Name)
+}
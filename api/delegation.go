@@ -0,0 +1,311 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSelfDelegation       = errors.New("Cannot delegate a ballot to yourself")
+	ErrProxyLimitReached    = errors.New("Proxy already holds the maximum number of ballots")
+	ErrDelegationNotPending = errors.New("Delegation is not pending")
+	ErrDelegationRevoked    = errors.New("Delegation is already revoked")
+	ErrDelegationExists     = errors.New("Ballot is already delegated in this election")
+	ErrBallotDelegated      = errors.New("Ballot is delegated to a proxy, revoke the delegation to vote")
+	ErrNoPermission         = errors.New("No permission")
+)
+
+type delegationResponse struct {
+	ID                int64      `json:"id"`
+	GrantorNationalID string     `json:"grantor_national_id"`
+	ProxyNationalID   string     `json:"proxy_national_id"`
+	ElectionID        int64      `json:"election_id"`
+	Status            string     `json:"status"`
+	ReviewedBy        string     `json:"reviewed_by,omitempty"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	CreateAt          time.Time  `json:"create_at"`
+}
+
+func newDelegationResponse(delegation db.Delegation) delegationResponse {
+	rsp := delegationResponse{
+		ID:                delegation.ID,
+		GrantorNationalID: delegation.GrantorNationalID,
+		ProxyNationalID:   delegation.ProxyNationalID,
+		ElectionID:        delegation.ElectionID,
+		Status:            delegation.Status,
+		ReviewedBy:        delegation.ReviewedBy.String,
+		CreateAt:          delegation.CreateAt,
+	}
+	if delegation.ReviewedAt.Valid {
+		rsp.ReviewedAt = &delegation.ReviewedAt.Time
+	}
+	return rsp
+}
+
+type createDelegationRequest struct {
+	ProxyNationalID string `json:"proxy_national_id" binding:"required,number,len=13"`
+	ElectionID      int64  `json:"election_id" binding:"required,min=1"`
+}
+
+// createDelegation lets the authenticated voter ask for their ballot of an election to be
+// cast by a proxy, the delegation is pending until an election manager approves it and does
+// not carry over to a runoff or a later election
+func (server Server) createDelegation(ctx *gin.Context) {
+	var req createDelegationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if req.ProxyNationalID == authPayload.NationalID {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrSelfDelegation))
+		return
+	}
+
+	grantor, err := server.store.GetUser(ctx, authPayload.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	_, err = server.store.GetUser(ctx, req.ProxyNationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, req.ElectionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return
+	}
+
+	voted, err := server.store.HasElectionVoterVoted(ctx, db.HasElectionVoterVotedParams{
+		ElectionID: election.ID,
+		NationalID: grantor.NationalID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if voted {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrAlreadyVoted))
+		return
+	}
+
+	held, err := server.store.CountProxyDelegations(ctx, db.CountProxyDelegationsParams{
+		ProxyNationalID: req.ProxyNationalID,
+		ElectionID:      election.ID,
+		Statuses:        []string{util.DelegationPending, util.DelegationApproved},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if held >= server.config.MaxProxiesPerHolder {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrProxyLimitReached))
+		return
+	}
+
+	arg := db.CreateDelegationParams{
+		GrantorNationalID: grantor.NationalID,
+		ProxyNationalID:   req.ProxyNationalID,
+		ElectionID:        election.ID,
+		Status:            util.DelegationPending,
+	}
+
+	delegation, err := server.store.CreateDelegation(ctx, arg)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrDelegationExists))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDelegationResponse(delegation))
+}
+
+type listDelegationsRequest struct {
+	Status   string `form:"status" binding:"required,oneof=PENDING APPROVED REVOKED"`
+	PageID   int32  `form:"page_id" binding:"required,min=1"`
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=10"`
+}
+
+func (server Server) listDelegations(ctx *gin.Context) {
+	var req listDelegationsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.ListDelegationsParams{
+		Status: req.Status,
+		Limit:  req.PageSize,
+		Offset: (req.PageID - 1) * req.PageSize,
+	}
+
+	delegations, err := server.store.ListDelegations(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := make([]delegationResponse, 0, len(delegations))
+	for _, delegation := range delegations {
+		rsp = append(rsp, newDelegationResponse(delegation))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type delegationRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// approveDelegation lets an election manager allow the proxy to cast the ballot,
+// as long as the proxy stays within the configured number of approved ballots.
+// The limit is checked by the store in the transaction that approves the delegation.
+func (server Server) approveDelegation(ctx *gin.Context) {
+	var req delegationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	delegation, err := server.store.GetDelegation(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if delegation.Status != util.DelegationPending {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrDelegationNotPending))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	result, err := server.store.ApproveDelegationTx(ctx, db.ApproveDelegationTxParams{
+		ID: delegation.ID,
+		ReviewedBy: sql.NullString{
+			String: authPayload.NationalID,
+			Valid:  true,
+		},
+		MaxApproved: server.config.MaxProxiesPerHolder,
+	})
+	if err != nil {
+		switch err {
+		case db.ErrProxyLimitReached:
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrProxyLimitReached))
+		case sql.ErrNoRows:
+			// the delegation was reviewed since it was read
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrDelegationNotPending))
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDelegationResponse(result.Delegation))
+}
+
+// revokeDelegation withdraws a delegation, either by its grantor or a user who manages the voters
+func (server Server) revokeDelegation(ctx *gin.Context) {
+	var req delegationRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	delegation, err := server.store.GetDelegation(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if delegation.GrantorNationalID != authPayload.NationalID {
+		allowed, err := server.hasPermission(ctx, util.ManageVoters)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		if !allowed {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrNoPermission))
+			return
+		}
+	}
+
+	if delegation.Status == util.DelegationRevoked {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrDelegationRevoked))
+		return
+	}
+
+	server.updateDelegationStatus(ctx, delegation.ID, util.DelegationRevoked)
+}
+
+func (server Server) updateDelegationStatus(ctx *gin.Context, id int64, status string) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.UpdateDelegationStatusParams{
+		ID:     id,
+		Status: status,
+		ReviewedBy: sql.NullString{
+			String: authPayload.NationalID,
+			Valid:  true,
+		},
+	}
+
+	delegation, err := server.store.UpdateDelegationStatus(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDelegationResponse(delegation))
+}
@@ -0,0 +1,901 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDelegationAPI(t *testing.T) {
+	grantor, _ := CreateRandomUser(t)
+	proxy := CreateRandomProxy(t)
+	election := RandomElection()
+	delegation := RandomDelegation(grantor.NationalID, proxy.NationalID, election.ID)
+
+	closedElection := election
+	closedElection.Closed = true
+
+	votedArg := db.HasElectionVoterVotedParams{
+		ElectionID: election.ID,
+		NationalID: grantor.NationalID,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(proxy, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					HasElectionVoterVoted(gomock.Any(), gomock.Eq(votedArg)).
+					Times(1).
+					Return(false, nil)
+
+				countArg := db.CountProxyDelegationsParams{
+					ProxyNationalID: proxy.NationalID,
+					ElectionID:      election.ID,
+					Statuses:        []string{util.DelegationPending, util.DelegationApproved},
+				}
+				store.EXPECT().
+					CountProxyDelegations(gomock.Any(), gomock.Eq(countArg)).
+					Times(1).
+					Return(int64(1), nil)
+
+				arg := db.CreateDelegationParams{
+					GrantorNationalID: grantor.NationalID,
+					ProxyNationalID:   proxy.NationalID,
+					ElectionID:        election.ID,
+					Status:            util.DelegationPending,
+				}
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(delegation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchDelegation(t, recorder.Body, delegation)
+			},
+		},
+		{
+			name: "SelfDelegation",
+			body: gin.H{
+				"proxy_national_id": grantor.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVoted",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(proxy, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					HasElectionVoterVoted(gomock.Any(), gomock.Eq(votedArg)).
+					Times(1).
+					Return(true, nil)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ProxyNotFound",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ProxyLimitReached",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(proxy, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					HasElectionVoterVoted(gomock.Any(), gomock.Eq(votedArg)).
+					Times(1).
+					Return(false, nil)
+				store.EXPECT().
+					CountProxyDelegations(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(2), nil)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "ActiveDelegationExists",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(proxy, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					HasElectionVoterVoted(gomock.Any(), gomock.Eq(votedArg)).
+					Times(1).
+					Return(false, nil)
+				store.EXPECT().
+					CountProxyDelegations(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.Contains(t, recorder.Body.String(), ErrDelegationExists.Error())
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionNotFound",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(proxy, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ClosedElection",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(grantor.NationalID)).
+					Times(1).
+					Return(grantor, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return(proxy, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(closedElection, nil)
+				store.EXPECT().
+					CreateDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidElectionID",
+			body: gin.H{
+				"proxy_national_id": proxy.NationalID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidProxyNationalID",
+			body: gin.H{
+				"proxy_national_id": "123",
+				"election_id":       election.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/delegations"
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestApproveDelegationAPI(t *testing.T) {
+	grantor, _ := CreateRandomUser(t)
+	proxy := CreateRandomProxy(t)
+	admin := CreateRandomManager(t)
+	delegation := RandomDelegation(grantor.NationalID, proxy.NationalID, util.RandomInt(1, 1000))
+
+	approved := delegation
+	approved.Status = util.DelegationApproved
+	approved.ReviewedBy = sql.NullString{String: admin.NationalID, Valid: true}
+	approved.ReviewedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		delegationID  int64
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:         "OK",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+
+				arg := db.ApproveDelegationTxParams{
+					ID:          delegation.ID,
+					ReviewedBy:  sql.NullString{String: admin.NationalID, Valid: true},
+					MaxApproved: 2,
+				}
+				store.EXPECT().
+					ApproveDelegationTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ApproveDelegationTxResult{Delegation: approved}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchDelegation(t, recorder.Body, approved)
+			},
+		},
+		{
+			name:         "NoPermission",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, proxy.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return([]string{util.Vote}, nil)
+				store.EXPECT().
+					ListUserRoles(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return([]db.UserRole{}, nil)
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					ApproveDelegationTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:         "ManagerPasswordSession",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				// an enrolled authenticator does not count until the session passes a second factor
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireManagerTwoFactor)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.RequireManagerTwoFactor, Value: true}, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(admin.NationalID)).
+					AnyTimes().
+					Return(db.TwoFactor{
+						NationalID: admin.NationalID,
+						EnabledAt:  sql.NullTime{Time: time.Now(), Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:         "ManagerWithoutTwoFactor",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addTwoFactorAuthorization(t, request, tokenMaker, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireManagerTwoFactor)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.RequireManagerTwoFactor, Value: true}, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(admin.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:         "ManagerWithTwoFactor",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addTwoFactorAuthorization(t, request, tokenMaker, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireManagerTwoFactor)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.RequireManagerTwoFactor, Value: true}, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(admin.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{
+						NationalID: admin.NationalID,
+						EnabledAt:  sql.NullTime{Time: time.Now(), Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(approved, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "NotFound",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					ApproveDelegationTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:         "NotPending",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(approved, nil)
+				store.EXPECT().
+					ApproveDelegationTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "ProxyLimitReached",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					ApproveDelegationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveDelegationTxResult{}, db.ErrProxyLimitReached)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:         "ReviewedConcurrently",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					ApproveDelegationTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ApproveDelegationTxResult{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:         "InternalError",
+			delegationID: delegation.ID,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(admin.NationalID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:         "InvalidID",
+			delegationID: 0,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/delegations/%d/approve", tc.delegationID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestRevokeDelegationAPI(t *testing.T) {
+	grantor, _ := CreateRandomUser(t)
+	proxy := CreateRandomProxy(t)
+	admin := CreateRandomManager(t)
+	delegation := RandomDelegation(grantor.NationalID, proxy.NationalID, util.RandomInt(1, 1000))
+	delegation.Status = util.DelegationApproved
+
+	revoked := delegation
+	revoked.Status = util.DelegationRevoked
+	revoked.ReviewedBy = sql.NullString{String: grantor.NationalID, Valid: true}
+	revoked.ReviewedAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OKByGrantor",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				arg := db.UpdateDelegationStatusParams{
+					ID:         delegation.ID,
+					Status:     util.DelegationRevoked,
+					ReviewedBy: sql.NullString{String: grantor.NationalID, Valid: true},
+				}
+				store.EXPECT().
+					UpdateDelegationStatus(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(revoked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchDelegation(t, recorder.Body, revoked)
+			},
+		},
+		{
+			name: "OKByManager",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, admin.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					UpdateDelegationStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(revoked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoPermission",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, proxy.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return([]string{util.Vote}, nil)
+				store.EXPECT().
+					ListUserRoles(gomock.Any(), gomock.Eq(proxy.NationalID)).
+					Times(1).
+					Return([]db.UserRole{}, nil)
+				store.EXPECT().
+					UpdateDelegationStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyRevoked",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(revoked, nil)
+				store.EXPECT().
+					UpdateDelegationStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, grantor.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDelegation(gomock.Any(), gomock.Eq(delegation.ID)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					UpdateDelegationStatus(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/delegations/%d/revoke", delegation.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func requireBodyMatchDelegation(t *testing.T, body *bytes.Buffer, delegation db.Delegation) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotDelegation delegationResponse
+	err = json.Unmarshal(data, &gotDelegation)
+	require.NoError(t, err)
+	require.Equal(t, delegation.ID, gotDelegation.ID)
+	require.Equal(t, delegation.GrantorNationalID, gotDelegation.GrantorNationalID)
+	require.Equal(t, delegation.ProxyNationalID, gotDelegation.ProxyNationalID)
+	require.Equal(t, delegation.ElectionID, gotDelegation.ElectionID)
+	require.Equal(t, delegation.Status, gotDelegation.Status)
+	require.Equal(t, delegation.ReviewedBy.String, gotDelegation.ReviewedBy)
+	require.Equal(t, delegation.ReviewedAt.Valid, gotDelegation.ReviewedAt != nil)
+}
+
+func CreateRandomProxy(t *testing.T) db.User {
+	proxy, _ := CreateRandomUser(t)
+	proxy.NationalID = "1234567890124"
+	return proxy
+}
+
+func CreateRandomManager(t *testing.T) db.User {
+	manager, _ := CreateRandomUser(t)
+	manager.NationalID = "1234567890125"
+	manager.Permission = []string{util.ManageElection}
+	return manager
+}
+
+func RandomDelegation(grantorNationalID, proxyNationalID string, electionID int64) db.Delegation {
+	return db.Delegation{
+		ID:                util.RandomInt(1, 1000),
+		GrantorNationalID: grantorNationalID,
+		ProxyNationalID:   proxyNationalID,
+		ElectionID:        electionID,
+		Status:            util.DelegationPending,
+		CreateAt:          time.Now(),
+	}
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrInvalidVoterRoll = errors.New("Invalid voter roll")
+)
+
+// nullDistrictID converts an optional district id of a request to its column value,
+// zero means the row is not assigned to any district
+func nullDistrictID(districtID int64) sql.NullInt64 {
+	return sql.NullInt64{
+		Int64: districtID,
+		Valid: districtID > 0,
+	}
+}
+
+type createDistrictRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+func (server Server) createDistrict(ctx *gin.Context) {
+	var req createDistrictRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	district, err := server.store.CreateDistrict(ctx, req.Name)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, district)
+}
+
+func (server Server) listDistricts(ctx *gin.Context) {
+	districts, err := server.store.ListDistricts(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, districts)
+}
+
+// importVoterRoll assigns voters to districts from an uploaded csv file
+// with the same layout as the export: a header row then "National id,District id" rows
+func (server Server) importVoterRoll(ctx *gin.Context) {
+	records, err := readCSVUpload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("%w: %v", ErrInvalidVoterRoll, err)))
+		return
+	}
+
+	entries := make([]db.VoterRollEntry, 0, len(records))
+	for i, record := range records {
+		entry, err := parseVoterRollRecord(record)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("%w: row %d: %v", ErrInvalidVoterRoll, i+2, err)))
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	result, err := server.store.ImportVoterRollTx(ctx, db.ImportVoterRollTxParams{
+		Entries: entries,
+	})
+	if err != nil {
+		ctx.JSON(importErrorStatus(err), errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "ok",
+		"imported": len(result.Users),
+	})
+}
+
+func parseVoterRollRecord(record []string) (db.VoterRollEntry, error) {
+	if len(record) != 2 {
+		return db.VoterRollEntry{}, fmt.Errorf("expected 2 columns, got %d", len(record))
+	}
+
+	nationalID := strings.TrimSpace(record[0])
+	if !util.IsNationalID(nationalID) {
+		return db.VoterRollEntry{}, fmt.Errorf("invalid national id %q", nationalID)
+	}
+
+	districtID, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 64)
+	if err != nil || districtID < 1 {
+		return db.VoterRollEntry{}, fmt.Errorf("invalid district id %q", record[1])
+	}
+
+	return db.VoterRollEntry{
+		NationalID: nationalID,
+		DistrictID: districtID,
+	}, nil
+}
+
+func (server Server) districtsResult(ctx *gin.Context) {
+	districtsResult, err := server.store.ListDistrictsResult(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, districtsResult)
+}
+
+type districtResultRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+type districtResultResponse struct {
+	District   db.District                          `json:"district"`
+	Candidates []db.ListDistrictCandidatesResultRow `json:"candidates"`
+}
+
+func (server Server) districtResult(ctx *gin.Context) {
+	var req districtResultRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	district, err := server.store.GetDistrict(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	candidates, err := server.store.ListDistrictCandidatesResult(ctx, district.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, districtResultResponse{
+		District:   district,
+		Candidates: candidates,
+	})
+}
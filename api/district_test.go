@@ -0,0 +1,523 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDistrictAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	district := RandomDistrict()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name": district.Name,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateDistrict(gomock.Any(), gomock.Eq(district.Name)).
+					Times(1).
+					Return(district, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchDistrict(t, recorder.Body, district)
+			},
+		},
+		{
+			name: "DuplicateName",
+			body: gin.H{
+				"name": district.Name,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateDistrict(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.District{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"name": district.Name,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateDistrict(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.District{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidName",
+			body: gin.H{
+				"name": "",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateDistrict(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/districts")
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestImportVoterRollAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	district := RandomDistrict()
+	roll := fmt.Sprintf("National id,District id\n%s,%d\n", user.NationalID, district.ID)
+
+	assignedUser := user
+	assignedUser.DistrictID = sql.NullInt64{Int64: district.ID, Valid: true}
+
+	testCases := []struct {
+		name          string
+		roll          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			roll: roll,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.ImportVoterRollTxParams{
+					Entries: []db.VoterRollEntry{
+						{NationalID: user.NationalID, DistrictID: district.ID},
+					},
+				}
+				store.EXPECT().
+					ImportVoterRollTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.ImportVoterRollTxResult{Users: []db.User{assignedUser}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownVoter",
+			roll: roll,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportVoterRollTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportVoterRollTxResult{}, fmt.Errorf("roll entry 1: %w", sql.ErrNoRows))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownDistrict",
+			roll: roll,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportVoterRollTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportVoterRollTxResult{}, fmt.Errorf("roll entry 1: %w", &pq.Error{Code: "23503"}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			roll: roll,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportVoterRollTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportVoterRollTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRow",
+			roll: fmt.Sprintf("National id,District id\n%s,invalid\n", user.NationalID),
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportVoterRollTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "EmptyRoll",
+			roll: "National id,District id\n",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportVoterRollTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile("file", "roll.csv")
+			require.NoError(t, err)
+			_, err = part.Write([]byte(tc.roll))
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			url := fmt.Sprintf("/api/districts/roll")
+			request, err := http.NewRequest(http.MethodPost, url, body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestGetDistrictResultAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	district := RandomDistrict()
+	candidate := RandomCandidate()
+	resultRows := []db.ListDistrictCandidatesResultRow{
+		{
+			ID:         candidate.ID,
+			Name:       candidate.Name,
+			Dob:        candidate.Dob,
+			BioLink:    candidate.BioLink,
+			ImageUrl:   candidate.ImageUrl,
+			Policy:     candidate.Policy,
+			VoteCount:  candidate.VoteCount,
+			Percentage: "0%",
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		districtID    int64
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			districtID: district.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDistrict(gomock.Any(), gomock.Eq(district.ID)).
+					Times(1).
+					Return(district, nil)
+				store.EXPECT().
+					ListDistrictCandidatesResult(gomock.Any(), gomock.Eq(district.ID)).
+					Times(1).
+					Return(resultRows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var gotResult districtResultResponse
+				err = json.Unmarshal(data, &gotResult)
+				require.NoError(t, err)
+				require.Equal(t, district.ID, gotResult.District.ID)
+				require.Equal(t, resultRows, gotResult.Candidates)
+			},
+		},
+		{
+			name:       "NotFound",
+			districtID: district.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDistrict(gomock.Any(), gomock.Eq(district.ID)).
+					Times(1).
+					Return(db.District{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListDistrictCandidatesResult(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "InternalError",
+			districtID: district.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDistrict(gomock.Any(), gomock.Eq(district.ID)).
+					Times(1).
+					Return(district, nil)
+				store.EXPECT().
+					ListDistrictCandidatesResult(gomock.Any(), gomock.Eq(district.ID)).
+					Times(1).
+					Return([]db.ListDistrictCandidatesResultRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name:       "InvalidID",
+			districtID: 0,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDistrict(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/election/result/districts/%d", tc.districtID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestListDistrictsResultAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	district := RandomDistrict()
+	resultRows := []db.ListDistrictsResultRow{
+		{
+			ID:         district.ID,
+			Name:       district.Name,
+			VoterCount: 10,
+			VotedCount: 4,
+			VoteCount:  4,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDistrictsResult(gomock.Any()).
+					Times(1).
+					Return(resultRows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				data, err := ioutil.ReadAll(recorder.Body)
+				require.NoError(t, err)
+
+				var gotResult []db.ListDistrictsResultRow
+				err = json.Unmarshal(data, &gotResult)
+				require.NoError(t, err)
+				require.Equal(t, resultRows, gotResult)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDistrictsResult(gomock.Any()).
+					Times(1).
+					Return([]db.ListDistrictsResultRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/election/result/districts")
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func requireBodyMatchDistrict(t *testing.T, body *bytes.Buffer, district db.District) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotDistrict db.District
+	err = json.Unmarshal(data, &gotDistrict)
+	require.NoError(t, err)
+	require.Equal(t, district.ID, gotDistrict.ID)
+	require.Equal(t, district.Name, gotDistrict.Name)
+}
+
+func RandomDistrict() db.District {
+	return db.District{
+		ID:   util.RandomInt(1, 1000),
+		Name: util.RandomName(),
+	}
+}
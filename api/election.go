@@ -2,9 +2,10 @@ package api
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/csv"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
 	"strconv"
 
@@ -12,6 +13,15 @@ import (
 	"election/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrElectionStarted     = errors.New("Election rules cannot change once voting has started")
+	ErrElectionNotClosed   = errors.New("Election must be closed before a runoff")
+	ErrRunoffExists        = errors.New("Election already has a runoff")
+	ErrNotEnoughCandidates = errors.New("Runoff needs at least two candidates")
+	ErrPartyListMeasures   = errors.New("Party-list election cannot have ballot measures")
 )
 
 type toggleElectionRequest struct {
@@ -21,7 +31,7 @@ type toggleElectionRequest struct {
 func (server Server) toggleElection(ctx *gin.Context) {
 	var req toggleElectionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
@@ -32,7 +42,7 @@ func (server Server) toggleElection(ctx *gin.Context) {
 
 	electionProperty, err := server.store.UpdateElectionProperty(ctx, arg)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -42,46 +52,546 @@ func (server Server) toggleElection(ctx *gin.Context) {
 	})
 }
 
+type toggleRevoteRequest struct {
+	Enable bool `json:"enable"`
+}
+
+func (server Server) toggleRevote(ctx *gin.Context) {
+	var req toggleRevoteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateElectionPropertyParams{
+		Name:  util.RevoteEnabled,
+		Value: req.Enable,
+	}
+
+	electionProperty, err := server.store.UpdateElectionProperty(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"enable": electionProperty.Value,
+	})
+}
+
+type toggleWithdrawAfterVotesRequest struct {
+	Enable bool `json:"enable"`
+}
+
+// toggleWithdrawAfterVotes sets whether a candidate who already received votes may be withdrawn
+func (server Server) toggleWithdrawAfterVotes(ctx *gin.Context) {
+	var req toggleWithdrawAfterVotesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateElectionPropertyParams{
+		Name:  util.WithdrawAfterVotes,
+		Value: req.Enable,
+	}
+
+	electionProperty, err := server.store.UpdateElectionProperty(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"enable": electionProperty.Value,
+	})
+}
+
+type toggleVerifiedVotingRequest struct {
+	Enable bool `json:"enable"`
+}
+
+// toggleVerifiedVoting sets whether only accounts with a verified email may vote
+func (server Server) toggleVerifiedVoting(ctx *gin.Context) {
+	var req toggleVerifiedVotingRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateElectionPropertyParams{
+		Name:  util.RequireVerifiedEmail,
+		Value: req.Enable,
+	}
+
+	electionProperty, err := server.store.UpdateElectionProperty(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"enable": electionProperty.Value,
+	})
+}
+
+type toggleManagerTwoFactorRequest struct {
+	Enable bool `json:"enable"`
+}
+
+// toggleManagerTwoFactor sets whether managing the election needs two-factor authentication
+func (server Server) toggleManagerTwoFactor(ctx *gin.Context) {
+	var req toggleManagerTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdateElectionPropertyParams{
+		Name:  util.RequireManagerTwoFactor,
+		Value: req.Enable,
+	}
+
+	electionProperty, err := server.store.UpdateElectionProperty(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"enable": electionProperty.Value,
+	})
+}
+
 func (server Server) electionResult(ctx *gin.Context) {
 
 	electionResults, err := server.store.ListCandidatesResult(ctx)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	ctx.JSON(http.StatusOK, electionResults)
 }
 
+type electionRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server Server) getElection(ctx *gin.Context) {
+	var req electionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, election)
+}
+
+type updateElectionRulesRequest struct {
+	WinnerRule       string `json:"winner_rule" binding:"required,oneof=PLURALITY ABSOLUTE_MAJORITY"`
+	QuorumPercentage int32  `json:"quorum_percentage" binding:"min=0,max=100"`
+	TieBreak         string `json:"tie_break" binding:"required,oneof=LOTTERY RUNOFF"`
+	TieBreakSeed     string `json:"tie_break_seed"`
+	BallotType       string `json:"ballot_type" binding:"omitempty,oneof=CANDIDATE PARTY_LIST"`
+	Seats            int32  `json:"seats" binding:"min=0"`
+	SeatMethod       string `json:"seat_method" binding:"omitempty,oneof=DHONDT SAINTE_LAGUE"`
+}
+
+// updateElectionRules changes how the winner is determined, the rules and the lottery
+// seed are locked once the first vote is cast so they cannot be tuned to the tally
+func (server Server) updateElectionRules(ctx *gin.Context) {
+	var uri electionRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req updateElectionRulesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	votes, err := server.store.CountActiveVotes(ctx, election.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if votes > 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrElectionStarted))
+		return
+	}
+
+	seed := req.TieBreakSeed
+	if seed == "" {
+		seed = election.TieBreakSeed
+	}
+
+	ballotType := req.BallotType
+	if ballotType == "" {
+		ballotType = election.BallotType
+	}
+
+	// a party-list election is not voted through a whole ballot, so it cannot carry measures or contests
+	if ballotType == util.BallotTypePartyList {
+		measures, err := server.store.CountElectionBallotMeasures(ctx, election.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		if measures > 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyListMeasures))
+			return
+		}
+
+		contests, err := server.store.CountElectionContests(ctx, election.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		if contests > 0 {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyListContests))
+			return
+		}
+	}
+
+	seatMethod := req.SeatMethod
+	if seatMethod == "" {
+		seatMethod = election.SeatMethod
+	}
+
+	arg := db.UpdateElectionRulesParams{
+		ID:               election.ID,
+		WinnerRule:       req.WinnerRule,
+		QuorumPercentage: req.QuorumPercentage,
+		TieBreak:         req.TieBreak,
+		TieBreakSeed:     seed,
+		BallotType:       ballotType,
+		Seats:            req.Seats,
+		SeatMethod:       seatMethod,
+	}
+
+	election, err = server.store.UpdateElectionRules(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, election)
+}
+
+type outcomeCandidate struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	VoteCount         int32  `json:"vote_count"`
+	WeightedVoteCount int64  `json:"weighted_vote_count"`
+	Withdrawn         bool   `json:"withdrawn"`
+}
+
+// standingTallies returns the tallies of the candidates still standing, a candidate withdrawn
+// after receiving votes keeps them in the result but can neither win nor go to a runoff
+func standingTallies(candidates []db.ListElectionCandidatesResultRow) []util.Tally {
+	tallies := make([]util.Tally, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.WithdrawnAt.Valid {
+			continue
+		}
+		tallies = append(tallies, util.Tally{
+			CandidateID: candidate.ID,
+			Votes:       candidate.WeightedVoteCount,
+		})
+	}
+	return tallies
+}
+
+// electionOutcomeResponse publishes the rules with the lottery seed next to the decision,
+// so anyone can reproduce how the winner was determined
+type electionOutcomeResponse struct {
+	Election       db.Election        `json:"election"`
+	Contest        *db.Contest        `json:"contest,omitempty"`
+	RunoffElection *db.Election       `json:"runoff_election,omitempty"`
+	EligibleVoters int64              `json:"eligible_voters"`
+	Turnout        int64              `json:"turnout"`
+	QuorumMet      bool               `json:"quorum_met"`
+	Outcome        string             `json:"outcome"`
+	Tie            bool               `json:"tie"`
+	Candidates     []outcomeCandidate `json:"candidates"`
+	Winners        []outcomeCandidate `json:"winners"`
+	Runoff         []outcomeCandidate `json:"runoff"`
+}
+
+func newElectionOutcomeResponse(election db.Election, candidates []db.ListElectionCandidatesResultRow, turnout, eligibleVoters int64) electionOutcomeResponse {
+	results := make([]outcomeCandidate, 0, len(candidates))
+	byID := make(map[int64]outcomeCandidate, len(candidates))
+	for _, candidate := range candidates {
+		result := outcomeCandidate{
+			ID:                candidate.ID,
+			Name:              candidate.Name,
+			VoteCount:         candidate.VoteCount,
+			WeightedVoteCount: candidate.WeightedVoteCount,
+			Withdrawn:         candidate.WithdrawnAt.Valid,
+		}
+		results = append(results, result)
+		byID[candidate.ID] = result
+	}
+
+	outcome := util.DetermineOutcome(standingTallies(candidates), turnout, eligibleVoters, util.OutcomeRules{
+		WinnerRule:       election.WinnerRule,
+		QuorumPercentage: election.QuorumPercentage,
+		TieBreak:         election.TieBreak,
+		TieBreakSeed:     election.TieBreakSeed,
+	})
+
+	rsp := electionOutcomeResponse{
+		Election:       election,
+		EligibleVoters: eligibleVoters,
+		Turnout:        turnout,
+		QuorumMet:      outcome.Result != util.OutcomeNoQuorum,
+		Outcome:        outcome.Result,
+		Tie:            outcome.Tie,
+		Candidates:     results,
+		Winners:        []outcomeCandidate{},
+		Runoff:         []outcomeCandidate{},
+	}
+	if outcome.Result == util.OutcomeWinner {
+		rsp.Winners = append(rsp.Winners, byID[outcome.Winner])
+	}
+	for _, id := range outcome.Runoff {
+		rsp.Runoff = append(rsp.Runoff, byID[id])
+	}
+	return rsp
+}
+
+func (server Server) electionOutcome(ctx *gin.Context) {
+	var req electionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var query contestRequest
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	contest, valid := server.electionContest(ctx, election.ID, query.ContestID)
+	if !valid {
+		return
+	}
+
+	candidates, err := server.store.ListElectionCandidatesResult(ctx, db.ListElectionCandidatesResultParams{
+		ElectionID: election.ID,
+		ContestID:  query.ContestID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	turnout, err := server.store.CountActiveVotes(ctx, election.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	eligibleVoters, err := server.store.CountEligibleVoters(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := newElectionOutcomeResponse(election, candidates, turnout, eligibleVoters)
+	rsp.Contest = contest
+
+	runoff, err := server.store.GetRunoffElection(ctx, sql.NullInt64{Int64: election.ID, Valid: true})
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err == nil {
+		rsp.RunoffElection = &runoff
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type createRunoffRequest struct {
+	TopN      int   `json:"top_n" binding:"omitempty,min=2"`
+	ContestID int64 `json:"contest_id" binding:"omitempty,min=1"`
+}
+
+// createRunoff closes a finished election and creates its runoff between the top candidates of
+// the main race or of contest_id, the runoff keeps the rules and the voter roll with a fresh lottery
+// seed. An election has one runoff at most. Voting is then reopened with the toggle.
+func (server Server) createRunoff(ctx *gin.Context) {
+	var uri electionRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createRunoffRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	topN := req.TopN
+	if topN == 0 {
+		topN = 2
+	}
+
+	election, err := server.store.GetElection(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	isClosedElection, err := server.store.GetElectionProperty(ctx, util.ElectionClosed)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if !isClosedElection.Value && !election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrElectionNotClosed))
+		return
+	}
+
+	_, err = server.store.GetRunoffElection(ctx, sql.NullInt64{Int64: election.ID, Valid: true})
+	if err == nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrRunoffExists))
+		return
+	}
+	if err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if _, valid := server.electionContest(ctx, election.ID, req.ContestID); !valid {
+		return
+	}
+
+	candidates, err := server.store.ListElectionCandidatesResult(ctx, db.ListElectionCandidatesResultParams{
+		ElectionID: election.ID,
+		ContestID:  req.ContestID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	candidateIDs := util.RunoffCandidates(standingTallies(candidates), topN)
+	if len(candidateIDs) < 2 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrNotEnoughCandidates))
+		return
+	}
+
+	// the seed of the election is known since it was published, the runoff draws a new one
+	seed, err := util.NewTieBreakSeed()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.CreateRunoffTxParams{
+		Election:     election,
+		CandidateIDs: candidateIDs,
+		TieBreakSeed: seed,
+	}
+
+	result, err := server.store.CreateRunoffTx(ctx, arg)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrRunoffExists))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
 func (server Server) exportCSVElectionResult(ctx *gin.Context) {
 
 	FileName := "export.csv"
 	votedLists, err := server.store.ListVoteOrderByCandidate(ctx)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	b := &bytes.Buffer{}
 	w := csv.NewWriter(b)
 
-	if err := w.Write([]string{"Candidate id", "National id"}); err != nil {
-		log.Fatalln("error writing record to csv:", err)
+	if err := w.Write([]string{"Candidate id", "National id", "Cast by national id"}); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
 	}
 
 	for _, votedList := range votedLists {
 		var record []string
 		record = append(record, strconv.FormatInt(votedList.CandidateID, 10))
 		record = append(record, votedList.VoteNationalID)
+		record = append(record, votedList.CastByNationalID.String)
 		if err := w.Write(record); err != nil {
-			log.Fatalln("error writing record to csv:", err)
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
 		}
 	}
 	w.Flush()
 
 	if err := w.Error(); err != nil {
-		log.Fatal(err)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
 	}
 
 	ctx.Header("Content-Description", "File Transfer")
@@ -119,63 +119,79 @@ func TestToggleElectionAPI(t *testing.T) {
 
 }
 
-func TestGetElectionResultAPI(t *testing.T) {
+func TestToggleRevoteAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
-	n := 1
-	candidates := make([]db.Candidate, n)
-	resultRows := make([]db.ListCandidatesResultRow, n)
-	for i := 0; i < n; i++ {
-		candidates[i] = RandomCandidate()
-		pst := fmt.Sprintf("%d", candidates[i].Percentage) + "%"
-		resultRows[i] = db.ListCandidatesResultRow{
-			ID:         candidates[i].ID,
-			Name:       candidates[i].Name,
-			Dob:        candidates[i].Dob,
-			BioLink:    candidates[i].BioLink,
-			ImageUrl:   candidates[i].ImageUrl,
-			Policy:     candidates[i].Policy,
-			VoteCount:  candidates[i].VoteCount,
-			Percentage: pst,
-		}
+	enable := true
+	revoteEnabled := db.ElectionProperty{
+		ID:    util.RandomInt(1, 1000),
+		Name:  util.RevoteEnabled,
+		Value: enable,
 	}
 
 	testCases := []struct {
 		name          string
+		body          gin.H
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStub     func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
+			body: gin.H{
+				"enable": enable,
+			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateElectionPropertyParams{
+					Name:  util.RevoteEnabled,
+					Value: enable,
+				}
 				store.EXPECT().
-					ListCandidatesResult(gomock.Any()).
+					UpdateElectionProperty(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
-					Return(resultRows, nil)
+					Return(revoteEnabled, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchElectionResult(t, recorder.Body, resultRows)
+				requireBodyMatchToggle(t, recorder.Body, enable)
 			},
 		},
 		{
 			name: "InternalError",
+			body: gin.H{
+				"enable": enable,
+			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					ListCandidatesResult(gomock.Any()).
+					UpdateElectionProperty(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return([]db.ListCandidatesResultRow{}, sql.ErrConnDone)
+					Return(db.ElectionProperty{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"enable": enable,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateElectionProperty(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -193,8 +209,11 @@ func TestGetElectionResultAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/election/result")
-			request, err := http.NewRequest(http.MethodGet, url, nil)
+			url := fmt.Sprintf("/api/election/revote")
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
 			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -208,54 +227,79 @@ func TestGetElectionResultAPI(t *testing.T) {
 
 }
 
-func TestExportCSVElectionResultAPI(t *testing.T) {
+func TestToggleVerifiedVotingAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
-	n := 2
-	candidate := RandomCandidate()
-	resultRows := make([]db.ListVoteOrderByCandidateRow, n)
-	for i := 0; i < n; i++ {
-		resultRows[i] = db.ListVoteOrderByCandidateRow{
-			CandidateID:    candidate.ID,
-			VoteNationalID: user.NationalID,
-		}
+	enable := true
+	requireVerified := db.ElectionProperty{
+		ID:    util.RandomInt(1, 1000),
+		Name:  util.RequireVerifiedEmail,
+		Value: enable,
 	}
 
 	testCases := []struct {
 		name          string
+		body          gin.H
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStub     func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
+			body: gin.H{
+				"enable": enable,
+			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateElectionPropertyParams{
+					Name:  util.RequireVerifiedEmail,
+					Value: enable,
+				}
 				store.EXPECT().
-					ListVoteOrderByCandidate(gomock.Any()).
+					UpdateElectionProperty(gomock.Any(), gomock.Eq(arg)).
 					Times(1).
-					Return(resultRows, nil)
+					Return(requireVerified, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchToggle(t, recorder.Body, enable)
 			},
 		},
 		{
 			name: "InternalError",
+			body: gin.H{
+				"enable": enable,
+			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					ListVoteOrderByCandidate(gomock.Any()).
+					UpdateElectionProperty(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return([]db.ListVoteOrderByCandidateRow{}, sql.ErrConnDone)
+					Return(db.ElectionProperty{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"enable": enable,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateElectionProperty(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -273,8 +317,11 @@ func TestExportCSVElectionResultAPI(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/election/export")
-			request, err := http.NewRequest(http.MethodGet, url, nil)
+			url := fmt.Sprintf("/api/election/verification")
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
 			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -288,12 +335,1133 @@ func TestExportCSVElectionResultAPI(t *testing.T) {
 
 }
 
-func requireBodyMatchElectionResult(t *testing.T, body *bytes.Buffer, electionResult []db.ListCandidatesResultRow) {
-	data, err := ioutil.ReadAll(body)
-	require.NoError(t, err)
+func TestToggleManagerTwoFactorAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	enable := true
+	requireTwoFactor := db.ElectionProperty{
+		ID:    util.RandomInt(1, 1000),
+		Name:  util.RequireManagerTwoFactor,
+		Value: enable,
+	}
 
-	var gotElectionResult []db.ListCandidatesResultRow
-	err = json.Unmarshal(data, &gotElectionResult)
-	require.NoError(t, err)
-	require.Equal(t, electionResult, gotElectionResult)
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"enable": enable,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.UpdateElectionPropertyParams{
+					Name:  util.RequireManagerTwoFactor,
+					Value: enable,
+				}
+				store.EXPECT().
+					UpdateElectionProperty(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(requireTwoFactor, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchToggle(t, recorder.Body, enable)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"enable": enable,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateElectionProperty(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ElectionProperty{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{
+				"enable": enable,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateElectionProperty(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/election/manager-2fa")
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestGetElectionResultAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	n := 1
+	candidates := make([]db.Candidate, n)
+	resultRows := make([]db.ListCandidatesResultRow, n)
+	for i := 0; i < n; i++ {
+		candidates[i] = RandomCandidate()
+		pst := fmt.Sprintf("%d", candidates[i].Percentage) + "%"
+		weightedPst := fmt.Sprintf("%d", candidates[i].WeightedPercentage) + "%"
+		resultRows[i] = db.ListCandidatesResultRow{
+			ID:                 candidates[i].ID,
+			Name:               candidates[i].Name,
+			Dob:                candidates[i].Dob,
+			BioLink:            candidates[i].BioLink,
+			ImageUrl:           candidates[i].ImageUrl,
+			Policy:             candidates[i].Policy,
+			VoteCount:          candidates[i].VoteCount,
+			Percentage:         pst,
+			WeightedVoteCount:  candidates[i].WeightedVoteCount,
+			WeightedPercentage: weightedPst,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidatesResult(gomock.Any()).
+					Times(1).
+					Return(resultRows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchElectionResult(t, recorder.Body, resultRows)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListCandidatesResult(gomock.Any()).
+					Times(1).
+					Return([]db.ListCandidatesResultRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/election/result")
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestExportCSVElectionResultAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	n := 2
+	candidate := RandomCandidate()
+	resultRows := make([]db.ListVoteOrderByCandidateRow, n)
+	for i := 0; i < n; i++ {
+		resultRows[i] = db.ListVoteOrderByCandidateRow{
+			CandidateID:    candidate.ID,
+			VoteNationalID: user.NationalID,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListVoteOrderByCandidate(gomock.Any()).
+					Times(1).
+					Return(resultRows, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListVoteOrderByCandidate(gomock.Any()).
+					Times(1).
+					Return([]db.ListVoteOrderByCandidateRow{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/election/export")
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestUpdateElectionRulesAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	election := RandomElection()
+	seed := util.RandomString(32)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"winner_rule":       util.WinnerRuleAbsoluteMajority,
+				"quorum_percentage": 30,
+				"tie_break":         util.TieBreakLottery,
+				"tie_break_seed":    seed,
+				"ballot_type":       util.BallotTypePartyList,
+				"seats":             9,
+				"seat_method":       util.SeatMethodSainteLague,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				arg := db.UpdateElectionRulesParams{
+					ID:               election.ID,
+					WinnerRule:       util.WinnerRuleAbsoluteMajority,
+					QuorumPercentage: 30,
+					TieBreak:         util.TieBreakLottery,
+					TieBreakSeed:     seed,
+					BallotType:       util.BallotTypePartyList,
+					Seats:            9,
+					SeatMethod:       util.SeatMethodSainteLague,
+				}
+				store.EXPECT().
+					UpdateElectionRules(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(election, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "KeepSeed",
+			body: gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakLottery,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				arg := db.UpdateElectionRulesParams{
+					ID:           election.ID,
+					WinnerRule:   util.WinnerRulePlurality,
+					TieBreak:     util.TieBreakLottery,
+					TieBreakSeed: election.TieBreakSeed,
+					BallotType:   election.BallotType,
+					SeatMethod:   election.SeatMethod,
+				}
+				store.EXPECT().
+					UpdateElectionRules(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(election, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "PartyListWithMeasures",
+			body: gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakLottery,
+				"ballot_type": util.BallotTypePartyList,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					UpdateElectionRules(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PartyListWithContests",
+			body: gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakLottery,
+				"ballot_type": util.BallotTypePartyList,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					UpdateElectionRules(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionStarted",
+			body: gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakRunoff,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					UpdateElectionRules(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			body: gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakRunoff,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpdateElectionRules(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSeatMethod",
+			body: gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakRunoff,
+				"seat_method": "HARE",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidRule",
+			body: gin.H{
+				"winner_rule": "RANKED",
+				"tie_break":   util.TieBreakRunoff,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/elections/%d/rules", election.ID)
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestElectionOutcomeAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate1 := RandomCandidate()
+	candidate2 := RandomCandidate()
+	candidate2.ID = candidate1.ID + 1
+
+	resultRows := func(votes1, votes2 int64) []db.ListElectionCandidatesResultRow {
+		return []db.ListElectionCandidatesResultRow{
+			{ID: candidate1.ID, Name: candidate1.Name, VoteCount: int32(votes1), WeightedVoteCount: votes1},
+			{ID: candidate2.ID, Name: candidate2.Name, VoteCount: int32(votes2), WeightedVoteCount: votes2},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		election      func() db.Election
+		rows          []db.ListElectionCandidatesResultRow
+		checkResponse func(t *testing.T, outcome electionOutcomeResponse)
+	}{
+		{
+			name: "Winner",
+			election: func() db.Election {
+				return RandomElection()
+			},
+			rows: resultRows(6, 4),
+			checkResponse: func(t *testing.T, outcome electionOutcomeResponse) {
+				require.Equal(t, util.OutcomeWinner, outcome.Outcome)
+				require.True(t, outcome.QuorumMet)
+				require.Len(t, outcome.Winners, 1)
+				require.Equal(t, candidate1.ID, outcome.Winners[0].ID)
+				require.Empty(t, outcome.Runoff)
+			},
+		},
+		{
+			name: "TieLottery",
+			election: func() db.Election {
+				election := RandomElection()
+				election.TieBreak = util.TieBreakLottery
+				return election
+			},
+			rows: resultRows(5, 5),
+			checkResponse: func(t *testing.T, outcome electionOutcomeResponse) {
+				require.Equal(t, util.OutcomeWinner, outcome.Outcome)
+				require.True(t, outcome.Tie)
+				require.Len(t, outcome.Winners, 1)
+
+				drawn := util.LotteryOrder(outcome.Election.TieBreakSeed, []int64{candidate1.ID, candidate2.ID})[0]
+				require.Equal(t, drawn, outcome.Winners[0].ID)
+			},
+		},
+		{
+			name: "TieRunoff",
+			election: func() db.Election {
+				return RandomElection()
+			},
+			rows: resultRows(5, 5),
+			checkResponse: func(t *testing.T, outcome electionOutcomeResponse) {
+				require.Equal(t, util.OutcomeRunoff, outcome.Outcome)
+				require.True(t, outcome.Tie)
+				require.Empty(t, outcome.Winners)
+				require.Len(t, outcome.Runoff, 2)
+			},
+		},
+		{
+			name: "WithdrawnWithVotes",
+			election: func() db.Election {
+				return RandomElection()
+			},
+			rows: func() []db.ListElectionCandidatesResultRow {
+				rows := resultRows(6, 4)
+				rows[0].WithdrawnAt = sql.NullTime{Time: time.Now(), Valid: true}
+				return rows
+			}(),
+			checkResponse: func(t *testing.T, outcome electionOutcomeResponse) {
+				require.Equal(t, util.OutcomeWinner, outcome.Outcome)
+				require.Len(t, outcome.Winners, 1)
+				require.Equal(t, candidate2.ID, outcome.Winners[0].ID)
+
+				require.Len(t, outcome.Candidates, 2)
+				require.Equal(t, candidate1.ID, outcome.Candidates[0].ID)
+				require.Equal(t, int32(6), outcome.Candidates[0].VoteCount)
+				require.True(t, outcome.Candidates[0].Withdrawn)
+				require.False(t, outcome.Candidates[1].Withdrawn)
+			},
+		},
+		{
+			name: "NoQuorum",
+			election: func() db.Election {
+				election := RandomElection()
+				election.QuorumPercentage = 75
+				return election
+			},
+			rows: resultRows(6, 4),
+			checkResponse: func(t *testing.T, outcome electionOutcomeResponse) {
+				require.Equal(t, util.OutcomeNoQuorum, outcome.Outcome)
+				require.False(t, outcome.QuorumMet)
+				require.Empty(t, outcome.Winners)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			election := tc.election()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetElection(gomock.Any(), gomock.Eq(election.ID)).
+				Times(1).
+				Return(election, nil)
+			store.EXPECT().
+				ListElectionCandidatesResult(gomock.Any(), gomock.Eq(db.ListElectionCandidatesResultParams{ElectionID: election.ID})).
+				Times(1).
+				Return(tc.rows, nil)
+			store.EXPECT().
+				CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+				Times(1).
+				Return(int64(10), nil)
+			store.EXPECT().
+				CountEligibleVoters(gomock.Any()).
+				Times(1).
+				Return(int64(20), nil)
+			store.EXPECT().
+				GetRunoffElection(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: election.ID, Valid: true})).
+				Times(1).
+				Return(db.Election{}, sql.ErrNoRows)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/elections/%d/outcome", election.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var outcome electionOutcomeResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &outcome)
+			require.NoError(t, err)
+			require.Equal(t, election.TieBreakSeed, outcome.Election.TieBreakSeed)
+
+			tc.checkResponse(t, outcome)
+
+		})
+
+	}
+
+}
+
+func TestElectionContestOutcomeAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	election := RandomElection()
+	contest := RandomContest()
+	contest.ElectionID = election.ID
+	otherContest := RandomContest()
+	otherContest.ElectionID = election.ID + 1
+	candidate := RandomCandidate()
+
+	testCases := []struct {
+		name          string
+		contestID     int64
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			contestID: contest.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetContest(gomock.Any(), gomock.Eq(contest.ID)).
+					Times(1).
+					Return(contest, nil)
+
+				arg := db.ListElectionCandidatesResultParams{
+					ElectionID: election.ID,
+					ContestID:  contest.ID,
+				}
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.ListElectionCandidatesResultRow{
+						{ID: candidate.ID, Name: candidate.Name, VoteCount: 6, WeightedVoteCount: 6},
+					}, nil)
+				store.EXPECT().
+					CountActiveVotes(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(10), nil)
+				store.EXPECT().
+					CountEligibleVoters(gomock.Any()).
+					Times(1).
+					Return(int64(20), nil)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var outcome electionOutcomeResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &outcome)
+				require.NoError(t, err)
+				require.NotNil(t, outcome.Contest)
+				require.Equal(t, contest.ID, outcome.Contest.ID)
+				require.Len(t, outcome.Winners, 1)
+				require.Equal(t, candidate.ID, outcome.Winners[0].ID)
+			},
+		},
+		{
+			name:      "ContestNotInElection",
+			contestID: otherContest.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetContest(gomock.Any(), gomock.Eq(otherContest.ID)).
+					Times(1).
+					Return(otherContest, nil)
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "ContestNotFound",
+			contestID: contest.ID,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetContest(gomock.Any(), gomock.Eq(contest.ID)).
+					Times(1).
+					Return(db.Contest{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetElection(gomock.Any(), gomock.Eq(election.ID)).
+				Times(1).
+				Return(election, nil)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/elections/%d/outcome?contest_id=%d", election.ID, tc.contestID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestCreateRunoffAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	election := RandomElection()
+	closedElectionProperty := CreateClosedElectionProperty()
+	closedElectionProperty.Value = true
+	openElectionProperty := CreateClosedElectionProperty()
+
+	rows := []db.ListElectionCandidatesResultRow{
+		{ID: 1, Name: util.RandomName(), WeightedVoteCount: 7},
+		{ID: 2, Name: util.RandomName(), WeightedVoteCount: 4},
+		{ID: 3, Name: util.RandomName(), WeightedVoteCount: 4},
+		{ID: 4, Name: util.RandomName(), WeightedVoteCount: 1},
+	}
+
+	runoff := RandomElection()
+	runoff.RunoffOfElectionID = sql.NullInt64{Int64: election.ID, Valid: true}
+	runoffLookup := sql.NullInt64{Int64: election.ID, Valid: true}
+
+	buildClosedElection := func(store *mockdb.MockStore) {
+		store.EXPECT().
+			GetElection(gomock.Any(), gomock.Eq(election.ID)).
+			Times(1).
+			Return(election, nil)
+		store.EXPECT().
+			GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+			Times(1).
+			Return(closedElectionProperty, nil)
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				buildClosedElection(store)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Eq(runoffLookup)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Eq(db.ListElectionCandidatesResultParams{ElectionID: election.ID})).
+					Times(1).
+					Return(rows, nil)
+
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateRunoffTxParams) (db.CreateRunoffTxResult, error) {
+						require.Equal(t, election, arg.Election)
+						require.Equal(t, []int64{1, 2, 3}, arg.CandidateIDs)
+						require.NotEmpty(t, arg.TieBreakSeed)
+						require.NotEqual(t, election.TieBreakSeed, arg.TieBreakSeed)
+						return db.CreateRunoffTxResult{Election: election, Runoff: runoff}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var result db.CreateRunoffTxResult
+				err := json.Unmarshal(recorder.Body.Bytes(), &result)
+				require.NoError(t, err)
+				require.Equal(t, runoff.ID, result.Runoff.ID)
+				require.Equal(t, election.ID, result.Runoff.RunoffOfElectionID.Int64)
+			},
+		},
+		{
+			name: "TopN",
+			body: gin.H{
+				"top_n": 4,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				buildClosedElection(store)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Eq(runoffLookup)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Eq(db.ListElectionCandidatesResultParams{ElectionID: election.ID})).
+					Times(1).
+					Return(rows, nil)
+
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateRunoffTxParams) (db.CreateRunoffTxResult, error) {
+						require.Equal(t, election, arg.Election)
+						require.Equal(t, []int64{1, 2, 3, 4}, arg.CandidateIDs)
+						require.NotEmpty(t, arg.TieBreakSeed)
+						require.NotEqual(t, election.TieBreakSeed, arg.TieBreakSeed)
+						return db.CreateRunoffTxResult{Election: election, Runoff: runoff}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "WithdrawnCandidate",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				buildClosedElection(store)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Eq(runoffLookup)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+
+				withdrawnRows := append([]db.ListElectionCandidatesResultRow{}, rows...)
+				withdrawnRows[0].WithdrawnAt = sql.NullTime{Time: time.Now(), Valid: true}
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Eq(db.ListElectionCandidatesResultParams{ElectionID: election.ID})).
+					Times(1).
+					Return(withdrawnRows, nil)
+
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateRunoffTxParams) (db.CreateRunoffTxResult, error) {
+						require.Equal(t, []int64{2, 3}, arg.CandidateIDs)
+						return db.CreateRunoffTxResult{Election: election, Runoff: runoff}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidTopN",
+			body: gin.H{
+				"top_n": 1,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionNotFound",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionNotClosed",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(openElectionProperty, nil)
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "RunoffExists",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				buildClosedElection(store)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Eq(runoffLookup)).
+					Times(1).
+					Return(runoff, nil)
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnoughCandidates",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				buildClosedElection(store)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Eq(runoffLookup)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Eq(db.ListElectionCandidatesResultParams{ElectionID: election.ID})).
+					Times(1).
+					Return(rows[:1], nil)
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{},
+			buildStub: func(store *mockdb.MockStore) {
+				buildClosedElection(store)
+				store.EXPECT().
+					GetRunoffElection(gomock.Any(), gomock.Eq(runoffLookup)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+				store.EXPECT().
+					ListElectionCandidatesResult(gomock.Any(), gomock.Eq(db.ListElectionCandidatesResultParams{ElectionID: election.ID})).
+					Times(1).
+					Return(rows, nil)
+				store.EXPECT().
+					CreateRunoffTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateRunoffTxResult{}, sql.ErrTxDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/elections/%d/runoff", election.ID)
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func requireBodyMatchElectionResult(t *testing.T, body *bytes.Buffer, electionResult []db.ListCandidatesResultRow) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotElectionResult []db.ListCandidatesResultRow
+	err = json.Unmarshal(data, &gotElectionResult)
+	require.NoError(t, err)
+	require.Equal(t, electionResult, gotElectionResult)
+}
+
+func requireBodyMatchToggle(t *testing.T, body *bytes.Buffer, enable bool) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotToggle ToggleElectionResponse
+	err = json.Unmarshal(data, &gotToggle)
+	require.NoError(t, err)
+	require.Equal(t, "ok", gotToggle.Status)
+	require.Equal(t, enable, gotToggle.Enable)
+}
+
+func RandomElection() db.Election {
+	return db.Election{
+		ID:           util.RandomInt(1, 1000),
+		Name:         util.RandomName(),
+		WinnerRule:   util.WinnerRulePlurality,
+		TieBreak:     util.TieBreakRunoff,
+		TieBreakSeed: util.RandomString(32),
+		BallotType:   util.BallotTypeCandidate,
+		SeatMethod:   util.SeatMethodDHondt,
+	}
 }
@@ -0,0 +1,194 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+
+	"election/blob"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrImageTooBig     = errors.New("Image exceeds the upload size limit")
+	ErrInvalidImageKey = errors.New("Invalid image name")
+)
+
+const (
+	imageSize     = 800
+	thumbnailSize = 200
+)
+
+// imageName matches the names generated for uploaded images, e.g. candidate-1-0a1b2c3d4e5f6a7b.jpg
+var imageName = regexp.MustCompile(`^candidate-[0-9]+-[0-9a-f]{16}(-thumb)?\.jpg$`)
+
+// newImageStore stores images in an S3 compatible bucket when configured, on the local filesystem otherwise
+func newImageStore(config util.Config) (blob.Store, error) {
+	if config.ImageS3Endpoint != "" {
+		return blob.NewS3Store(
+			config.ImageS3Endpoint,
+			config.ImageS3Region,
+			config.ImageS3Bucket,
+			config.ImageS3AccessKey,
+			config.ImageS3SecretKey,
+		)
+	}
+	return blob.NewLocalStore(config.ImageStorageDir)
+}
+
+type uploadCandidateImageRequest struct {
+	Id int64 `uri:"id" binding:"required,min=1"`
+}
+
+type candidateImageResponse struct {
+	ImageUrl     string `json:"image_url"`
+	ThumbnailUrl string `json:"thumbnail_url"`
+}
+
+// uploadCandidateImage stores a resized image and a thumbnail of the uploaded multipart "image" file
+// and makes it the candidate's image. The stored names contain a hash of the upload, so a new
+// upload gets a new url and served images can be cached forever.
+func (server Server) uploadCandidateImage(ctx *gin.Context) {
+	var req uploadCandidateImageRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// leave room for the multipart headers around the file
+	maxBodyBytes := server.config.ImageMaxBytes + 4096
+	if ctx.Request.ContentLength > maxBodyBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(ctx, ErrImageTooBig))
+		return
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBodyBytes)
+
+	fileHeader, err := ctx.FormFile("image")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if fileHeader.Size > server.config.ImageMaxBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(ctx, ErrImageTooBig))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+	defer file.Close()
+
+	var upload bytes.Buffer
+	if _, err := io.Copy(&upload, file); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	img, err := util.DecodeImage(upload.Bytes())
+	if err != nil {
+		if err == util.ErrUnsupportedImage {
+			ctx.JSON(http.StatusUnsupportedMediaType, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	candidate, valid := server.editableCandidate(ctx, req.Id)
+	if !valid {
+		return
+	}
+
+	sum := sha256.Sum256(upload.Bytes())
+	name := fmt.Sprintf("candidate-%d-%s", candidate.ID, hex.EncodeToString(sum[:8]))
+	rsp := candidateImageResponse{
+		ImageUrl:     "/images/" + name + ".jpg",
+		ThumbnailUrl: "/images/" + name + "-thumb.jpg",
+	}
+
+	for key, size := range map[string]int{
+		name + ".jpg":       imageSize,
+		name + "-thumb.jpg": thumbnailSize,
+	} {
+		data, err := util.EncodeJPEG(util.FitImage(img, size))
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		if err := server.images.Put(ctx, key, "image/jpeg", data); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	_, err = server.store.UpdateCandidateImage(ctx, db.UpdateCandidateImageParams{
+		ID:       candidate.ID,
+		ImageUrl: rsp.ImageUrl,
+		EditedBy: sql.NullString{String: authPayload.NationalID, Valid: true},
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrProfileLocked))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type getImageRequest struct {
+	Name string `uri:"name" binding:"required"`
+}
+
+// getImage serves an uploaded image, the content of a name never changes so it is cached forever
+func (server Server) getImage(ctx *gin.Context) {
+	var req getImageRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if !imageName.MatchString(req.Name) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrInvalidImageKey))
+		return
+	}
+
+	etag := `"` + req.Name + `"`
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Header("ETag", etag)
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	object, err := server.images.Get(ctx, req.Name)
+	if err != nil {
+		if err == blob.ErrNotFound {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	ctx.Header("ETag", etag)
+	ctx.Data(http.StatusOK, object.ContentType, object.Data)
+}
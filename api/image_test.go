@@ -0,0 +1,306 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUploadCandidateImageAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	candidateRow := db.GetCandidateRow{
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		ElectionID: candidate.ElectionID,
+	}
+	lockedCandidateRow := candidateRow
+	lockedCandidateRow.ElectionVotingStarted = true
+
+	testCases := []struct {
+		name          string
+		candidateID   int64
+		field         string
+		data          []byte
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			candidateID: candidate.ID,
+			field:       "image",
+			data:        randomImagePNG(t, 1200, 600),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					UpdateCandidateImage(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UpdateCandidateImageParams) (db.Candidate, error) {
+						require.Equal(t, candidate.ID, arg.ID)
+						require.Equal(t, user.NationalID, arg.EditedBy.String)
+						require.True(t, strings.HasPrefix(arg.ImageUrl, fmt.Sprintf("/images/candidate-%d-", candidate.ID)))
+						return candidate, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp candidateImageResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+				require.NoError(t, err)
+				require.True(t, strings.HasSuffix(rsp.ThumbnailUrl, "-thumb.jpg"))
+
+				requireServedImage(t, server, rsp.ImageUrl, imageSize, imageSize/2)
+				requireServedImage(t, server, rsp.ThumbnailUrl, thumbnailSize, thumbnailSize/2)
+			},
+		},
+		{
+			name:        "UnsupportedType",
+			candidateID: candidate.ID,
+			field:       "image",
+			data:        []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name:        "TooLarge",
+			candidateID: candidate.ID,
+			field:       "image",
+			data:        bytes.Repeat([]byte{0xff}, 2<<20),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:        "MissingFile",
+			candidateID: candidate.ID,
+			field:       "photo",
+			data:        randomImagePNG(t, 10, 10),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "CandidateNotFound",
+			candidateID: candidate.ID,
+			field:       "image",
+			data:        randomImagePNG(t, 10, 10),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(db.GetCandidateRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					UpdateCandidateImage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "ProfileLocked",
+			candidateID: candidate.ID,
+			field:       "image",
+			data:        randomImagePNG(t, 10, 10),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(lockedCandidateRow, nil)
+				store.EXPECT().
+					UpdateCandidateImage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "InvalidID",
+			candidateID: 0,
+			field:       "image",
+			data:        randomImagePNG(t, 10, 10),
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			body := &bytes.Buffer{}
+			writer := multipart.NewWriter(body)
+			part, err := writer.CreateFormFile(tc.field, "photo.png")
+			require.NoError(t, err)
+			_, err = part.Write(tc.data)
+			require.NoError(t, err)
+			require.NoError(t, writer.Close())
+
+			url := fmt.Sprintf("/api/candidates/%d/image", tc.candidateID)
+			request, err := http.NewRequest(http.MethodPost, url, body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", writer.FormDataContentType())
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, server, recorder)
+
+		})
+
+	}
+
+}
+
+func TestGetImageAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	name := "candidate-1-0123456789abcdef.jpg"
+	require.NoError(t, server.images.Put(context.Background(), name, "image/jpeg", []byte("jpeg")))
+
+	testCases := []struct {
+		name          string
+		imageName     string
+		ifNoneMatch   string
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			imageName: name,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+				require.Contains(t, recorder.Header().Get("Cache-Control"), "immutable")
+				require.Equal(t, []byte("jpeg"), recorder.Body.Bytes())
+			},
+		},
+		{
+			name:        "NotModified",
+			imageName:   name,
+			ifNoneMatch: `"` + name + `"`,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Empty(t, recorder.Body.Bytes())
+			},
+		},
+		{
+			name:      "NotFound",
+			imageName: "candidate-2-0123456789abcdef.jpg",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Empty(t, recorder.Header().Get("Cache-Control"))
+			},
+		},
+		{
+			name:      "InvalidName",
+			imageName: "app.env",
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/images/"+tc.imageName, nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				request.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireServedImage(t *testing.T, server *Server, url string, width, height int) {
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "image/jpeg", recorder.Header().Get("Content-Type"))
+
+	img, err := jpeg.Decode(recorder.Body)
+	require.NoError(t, err)
+	require.Equal(t, width, img.Bounds().Dx())
+	require.Equal(t, height, img.Bounds().Dy())
+}
+
+func randomImagePNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 64, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+// readCSVUpload reads the csv file uploaded in the "file" form field
+// and returns its records without the header row
+func readCSVUpload(ctx *gin.Context) ([][]string, error) {
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("file has no rows")
+	}
+
+	return records[1:], nil
+}
+
+// importErrorStatus maps the error of an import transaction to its http status
+func importErrorStatus(err error) int {
+	if errors.Is(err, sql.ErrNoRows) {
+		return http.StatusNotFound
+	}
+
+	var pqError *pq.Error
+	if errors.As(err, &pqError) {
+		switch pqError.Code.Name() {
+		case "foreign_key_violation", "check_violation":
+			return http.StatusBadRequest
+		}
+	}
+
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"election/logging"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	requestIDHeader = "X-Request-ID"
+	requestIDKey    = "request_id"
+)
+
+var ErrInternalServer = errors.New("Internal server error")
+
+// newLogger returns the JSON logger writing to standard output at the configured level
+func newLogger(config util.Config) (logging.Logger, error) {
+	level, err := logging.ParseLevel(config.LogLevel)
+	if err != nil {
+		return nil, err
+	}
+	return logging.NewJSONLogger(os.Stdout, level), nil
+}
+
+// requestID identifies the request by the ID the client sent, or a new one when it sent none or an
+// invalid one, the ID is returned in the response header and carried by the request context
+func (server *Server) requestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		requestID := ctx.GetHeader(requestIDHeader)
+		if !logging.IsValidRequestID(requestID) {
+			requestID = uuid.NewString()
+		}
+
+		ctx.Set(requestIDKey, requestID)
+		ctx.Request = ctx.Request.WithContext(logging.WithRequestID(ctx.Request.Context(), requestID))
+		ctx.Header(requestIDHeader, requestID)
+
+		ctx.Next()
+	}
+}
+
+// logRequest writes an entry for every request with the errors recorded by errorResponse, only the
+// route template is logged as the path and query may hold a receipt code or a token
+func (server *Server) logRequest() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		status := ctx.Writer.Status()
+		fields := []logging.Field{
+			logging.String(requestIDKey, ctx.GetString(requestIDKey)),
+			logging.String("method", ctx.Request.Method),
+			logging.String("route", ctx.FullPath()),
+			logging.Int("status", status),
+			logging.Duration("latency", time.Since(start)),
+			logging.String("client_ip", ctx.ClientIP()),
+		}
+		if len(ctx.Errors) > 0 {
+			fields = append(fields, logging.String("error", strings.Join(ctx.Errors.Errors(), "; ")))
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			server.logger.Error("request failed", fields...)
+		case status >= http.StatusBadRequest || len(ctx.Errors) > 0:
+			server.logger.Warn("request rejected", fields...)
+		default:
+			server.logger.Info("request served", fields...)
+		}
+	}
+}
+
+// recoverPanic answers a request whose handler panicked, the panic is logged with the request
+func (server *Server) recoverPanic(ctx *gin.Context, recovered interface{}) {
+	ctx.Error(fmt.Errorf("panic: %v", recovered))
+	ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, ErrInternalServer))
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/logging"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func newLoginRequest(t *testing.T, nationalID, password string) *http.Request {
+	data, err := json.Marshal(gin.H{
+		"national_id": nationalID,
+		"password":    password,
+	})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/users/login", bytes.NewReader(data))
+	require.NoError(t, err)
+	return request
+}
+
+func TestRequestIDAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+
+	testCases := []struct {
+		name           string
+		header         string
+		checkRequestID func(t *testing.T, requestID string)
+	}{
+		{
+			name:   "Generated",
+			header: "",
+			checkRequestID: func(t *testing.T, requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name:   "FromClient",
+			header: "frontend-42",
+			checkRequestID: func(t *testing.T, requestID string) {
+				require.Equal(t, "frontend-42", requestID)
+			},
+		},
+		{
+			name:   "InvalidFromClient",
+			header: "*/ DROP TABLE users; /*",
+			checkRequestID: func(t *testing.T, requestID string) {
+				_, err := uuid.Parse(requestID)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			var storeRequestID string
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+				Times(1).
+				DoAndReturn(func(ctx context.Context, nationalID string) (db.User, error) {
+					storeRequestID = logging.RequestID(ctx)
+					return db.User{}, sql.ErrNoRows
+				})
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := newLoginRequest(t, user.NationalID, util.RandomString(8))
+			if tc.header != "" {
+				request.Header.Set(requestIDHeader, tc.header)
+			}
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusNotFound, recorder.Code)
+
+			requestID := recorder.Header().Get(requestIDHeader)
+			tc.checkRequestID(t, requestID)
+			require.Equal(t, requestID, storeRequestID)
+
+			var rsp map[string]interface{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+			require.Equal(t, "error", rsp["status"])
+			require.Equal(t, requestID, rsp["request_id"])
+		})
+	}
+}
+
+func TestLogRequestAPI(t *testing.T) {
+	user, password := CreateRandomUser(t)
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		request    func(t *testing.T) *http.Request
+		checkEntry func(t *testing.T, recorder *httptest.ResponseRecorder, entry map[string]interface{})
+	}{
+		{
+			name: "Served",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+			},
+			request: func(t *testing.T) *http.Request {
+				return newLoginRequest(t, user.NationalID, password)
+			},
+			checkEntry: func(t *testing.T, recorder *httptest.ResponseRecorder, entry map[string]interface{}) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "info", entry["level"])
+				require.Equal(t, "request served", entry["msg"])
+				require.Equal(t, "/users/login", entry["route"])
+				require.Equal(t, float64(http.StatusOK), entry["status"])
+				require.NotContains(t, entry, "error")
+			},
+		},
+		{
+			name: "Rejected",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+			},
+			request: func(t *testing.T) *http.Request {
+				return newLoginRequest(t, user.NationalID, password+"wrong")
+			},
+			checkEntry: func(t *testing.T, recorder *httptest.ResponseRecorder, entry map[string]interface{}) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+				require.Equal(t, "warn", entry["level"])
+				require.Equal(t, float64(http.StatusUnauthorized), entry["status"])
+				require.NotEmpty(t, entry["error"])
+			},
+		},
+		{
+			name: "NationalIDInPath",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUserRoles(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			request: func(t *testing.T) *http.Request {
+				request, err := http.NewRequest(http.MethodGet, "/api/admin/users/"+user.NationalID+"/roles", nil)
+				require.NoError(t, err)
+				return request
+			},
+			checkEntry: func(t *testing.T, recorder *httptest.ResponseRecorder, entry map[string]interface{}) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, "error", entry["level"])
+				require.Equal(t, "/api/admin/users/:national_id/roles", entry["route"])
+				require.Equal(t, sql.ErrConnDone.Error(), entry["error"])
+			},
+		},
+		{
+			name: "ReceiptCodeInPath",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Eq(util.HashReceiptCode(receiptCode))).
+					Times(1).
+					Return(db.Vote{}, sql.ErrNoRows)
+			},
+			request: func(t *testing.T) *http.Request {
+				request, err := http.NewRequest(http.MethodGet, "/vote/receipt/"+receiptCode, nil)
+				require.NoError(t, err)
+				return request
+			},
+			checkEntry: func(t *testing.T, recorder *httptest.ResponseRecorder, entry map[string]interface{}) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, "/vote/receipt/:code", entry["route"])
+			},
+		},
+		{
+			name: "Panic",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					DoAndReturn(func(ctx context.Context, nationalID string) (db.User, error) {
+						panic("store is broken")
+					})
+			},
+			request: func(t *testing.T) *http.Request {
+				return newLoginRequest(t, user.NationalID, password)
+			},
+			checkEntry: func(t *testing.T, recorder *httptest.ResponseRecorder, entry map[string]interface{}) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Contains(t, recorder.Body.String(), ErrInternalServer.Error())
+				require.NotContains(t, recorder.Body.String(), "store is broken")
+
+				require.Equal(t, "error", entry["level"])
+				require.Contains(t, entry["error"], "panic: store is broken")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			var logs bytes.Buffer
+			server.logger = logging.NewJSONLogger(&logs, logging.DebugLevel)
+			recorder := httptest.NewRecorder()
+
+			request := tc.request(t)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			require.NotContains(t, logs.String(), user.NationalID)
+			require.NotContains(t, logs.String(), password)
+			require.NotContains(t, logs.String(), receiptCode)
+
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+			require.Equal(t, recorder.Header().Get(requestIDHeader), entry[requestIDKey])
+			tc.checkEntry(t, recorder, entry)
+		})
+	}
+}
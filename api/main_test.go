@@ -5,21 +5,60 @@ import (
 	"testing"
 	"time"
 
+	mockdb "election/db/mock"
 	db "election/db/sqlc"
+	"election/logging"
 	"election/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 )
 
 func newTestServer(t *testing.T, store db.Store) *Server {
-	config := util.Config{
-		TokenSymmetricKey:   util.RandomString(32),
-		AccessTokenDuration: time.Minute,
+	return newTestServerWithConfig(t, store, newTestConfig(t))
+}
+
+func newTestConfig(t *testing.T) util.Config {
+	return util.Config{
+		TokenSymmetricKey:         util.RandomString(32),
+		AccessTokenDuration:       time.Minute,
+		MaxProxiesPerHolder:       2,
+		ImageMaxBytes:             1 << 20,
+		ImageStorageDir:           t.TempDir(),
+		AppBaseURL:                "http://localhost:4200",
+		PasswordResetDuration:     time.Hour,
+		EmailVerificationDuration: 24 * time.Hour,
+		EmailVerificationInterval: 5 * time.Minute,
+	}
+}
+
+func newTestServerWithConfig(t *testing.T, store db.Store, config util.Config) *Server {
+	// tokens are valid and belong to an election manager without a two-factor requirement by default,
+	// a test expecting a revoked token or a missing permission sets up the lookups first
+	if mockStore, ok := store.(*mockdb.MockStore); ok {
+		mockStore.EXPECT().
+			GetUserPasswordChangedAt(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return(time.Time{}, nil)
+		mockStore.EXPECT().
+			GetUserPermissions(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]string{util.ManageElection}, nil)
+		mockStore.EXPECT().
+			ListUserRoles(gomock.Any(), gomock.Any()).
+			AnyTimes().
+			Return([]db.UserRole{}, nil)
+		mockStore.EXPECT().
+			GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireManagerTwoFactor)).
+			AnyTimes().
+			Return(db.ElectionProperty{Name: util.RequireManagerTwoFactor, Value: false}, nil)
 	}
 
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
+	// a test checking the request log replaces the logger
+	server.logger = logging.NewNopLogger()
 
 	return server
 }
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrOptionNotInMeasure = errors.New("Option does not belong to the ballot measure")
+)
+
+// defaultMeasureOptions are used for a plain yes/no proposition
+var defaultMeasureOptions = []string{"Yes", "No"}
+
+type createBallotMeasureRequest struct {
+	Question            string   `json:"question" binding:"required"`
+	ThresholdPercentage int32    `json:"threshold_percentage" binding:"omitempty,min=50,max=99"`
+	QuorumPercentage    int32    `json:"quorum_percentage" binding:"omitempty,min=0,max=100"`
+	Options             []string `json:"options" binding:"omitempty,min=2,dive,required"`
+}
+
+type measureOptionResponse struct {
+	ID       int64  `json:"id"`
+	Label    string `json:"label"`
+	Position int32  `json:"position"`
+}
+
+// ballotMeasureResponse is a ballot measure with its options, the first option
+// is the proposition which has to exceed the threshold for the measure to pass
+type ballotMeasureResponse struct {
+	ID                  int64                   `json:"id"`
+	Question            string                  `json:"question"`
+	ThresholdPercentage int32                   `json:"threshold_percentage"`
+	QuorumPercentage    int32                   `json:"quorum_percentage"`
+	Options             []measureOptionResponse `json:"options"`
+}
+
+func newBallotMeasureResponse(measure db.BallotMeasure, options []db.BallotMeasureOption) ballotMeasureResponse {
+	rsp := ballotMeasureResponse{
+		ID:                  measure.ID,
+		Question:            measure.Question,
+		ThresholdPercentage: measure.ThresholdPercentage,
+		QuorumPercentage:    measure.QuorumPercentage,
+		Options:             make([]measureOptionResponse, 0, len(options)),
+	}
+	for _, option := range options {
+		rsp.Options = append(rsp.Options, measureOptionResponse{
+			ID:       option.ID,
+			Label:    option.Label,
+			Position: option.Position,
+		})
+	}
+	return rsp
+}
+
+func (server Server) createBallotMeasure(ctx *gin.Context) {
+	var req createBallotMeasureRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	threshold := req.ThresholdPercentage
+	if threshold == 0 {
+		threshold = 50
+	}
+
+	options := req.Options
+	if len(options) == 0 {
+		options = defaultMeasureOptions
+	}
+
+	arg := db.CreateBallotMeasureTxParams{
+		CreateBallotMeasureParams: db.CreateBallotMeasureParams{
+			Question:            req.Question,
+			ThresholdPercentage: threshold,
+			QuorumPercentage:    req.QuorumPercentage,
+		},
+		Options: options,
+	}
+
+	result, err := server.store.CreateBallotMeasureTx(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newBallotMeasureResponse(result.Measure, result.Options))
+}
+
+func (server Server) listBallotMeasures(ctx *gin.Context) {
+	measures, err := server.store.ListBallotMeasures(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	options, err := server.store.ListBallotMeasureOptions(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	measureOptions := make(map[int64][]db.BallotMeasureOption)
+	for _, option := range options {
+		measureOptions[option.MeasureID] = append(measureOptions[option.MeasureID], option)
+	}
+
+	rsp := make([]ballotMeasureResponse, 0, len(measures))
+	for _, measure := range measures {
+		rsp = append(rsp, newBallotMeasureResponse(measure, measureOptions[measure.ID]))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type measureResultResponse struct {
+	ID                  int64                            `json:"id"`
+	Question            string                           `json:"question"`
+	ThresholdPercentage int32                            `json:"threshold_percentage"`
+	QuorumPercentage    int32                            `json:"quorum_percentage"`
+	EligibleVoters      int64                            `json:"eligible_voters"`
+	Turnout             int64                            `json:"turnout"`
+	QuorumMet           bool                             `json:"quorum_met"`
+	Passed              bool                             `json:"passed"`
+	Options             []db.ListMeasureOptionsResultRow `json:"options"`
+}
+
+// newMeasureResultResponse evaluates a measure, it passes when the turnout meets the quorum
+// and the weighted votes of its first option exceed the threshold of all weighted votes
+func newMeasureResultResponse(measure db.BallotMeasure, options []db.ListMeasureOptionsResultRow, eligibleVoters int64) measureResultResponse {
+	rsp := measureResultResponse{
+		ID:                  measure.ID,
+		Question:            measure.Question,
+		ThresholdPercentage: measure.ThresholdPercentage,
+		QuorumPercentage:    measure.QuorumPercentage,
+		EligibleVoters:      eligibleVoters,
+		Options:             options,
+	}
+	if rsp.Options == nil {
+		rsp.Options = []db.ListMeasureOptionsResultRow{}
+	}
+
+	var weightedTotal int64
+	for _, option := range options {
+		rsp.Turnout += option.VoteCount
+		weightedTotal += option.WeightedVoteCount
+	}
+
+	rsp.QuorumMet = util.MeetsQuorum(rsp.Turnout, eligibleVoters, measure.QuorumPercentage)
+	rsp.Passed = rsp.QuorumMet && len(options) > 0 &&
+		util.ExceedsThreshold(options[0].WeightedVoteCount, weightedTotal, measure.ThresholdPercentage)
+
+	return rsp
+}
+
+func (server Server) measuresResult(ctx *gin.Context) {
+	measures, err := server.store.ListBallotMeasures(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	options, err := server.store.ListMeasureOptionsResult(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	eligibleVoters, err := server.store.CountEligibleVoters(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	measureOptions := make(map[int64][]db.ListMeasureOptionsResultRow)
+	for _, option := range options {
+		measureOptions[option.MeasureID] = append(measureOptions[option.MeasureID], option)
+	}
+
+	rsp := make([]measureResultResponse, 0, len(measures))
+	for _, measure := range measures {
+		rsp = append(rsp, newMeasureResultResponse(measure, measureOptions[measure.ID], eligibleVoters))
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
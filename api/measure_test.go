@@ -0,0 +1,294 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBallotMeasureAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	measure := RandomBallotMeasure()
+	options := RandomBallotMeasureOptions(measure.ID, defaultMeasureOptions)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"question": measure.Question,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateBallotMeasureTxParams{
+					CreateBallotMeasureParams: db.CreateBallotMeasureParams{
+						Question:            measure.Question,
+						ThresholdPercentage: 50,
+					},
+					Options: defaultMeasureOptions,
+				}
+				store.EXPECT().
+					CreateBallotMeasureTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CreateBallotMeasureTxResult{Measure: measure, Options: options}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchBallotMeasure(t, recorder.Body, newBallotMeasureResponse(measure, options))
+			},
+		},
+		{
+			name: "Supermajority",
+			body: gin.H{
+				"question":             measure.Question,
+				"threshold_percentage": 66,
+				"quorum_percentage":    25,
+				"options":              []string{"Approve", "Reject"},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreateBallotMeasureTxParams{
+					CreateBallotMeasureParams: db.CreateBallotMeasureParams{
+						Question:            measure.Question,
+						ThresholdPercentage: 66,
+						QuorumPercentage:    25,
+					},
+					Options: []string{"Approve", "Reject"},
+				}
+				store.EXPECT().
+					CreateBallotMeasureTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.CreateBallotMeasureTxResult{Measure: measure, Options: options}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidThreshold",
+			body: gin.H{
+				"question":             measure.Question,
+				"threshold_percentage": 40,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBallotMeasureTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SingleOption",
+			body: gin.H{
+				"question": measure.Question,
+				"options":  []string{"Yes"},
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBallotMeasureTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"question": measure.Question,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBallotMeasureTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateBallotMeasureTxResult{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := "/api/measures"
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestMeasuresResultAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	measure := RandomBallotMeasure()
+	measure.ThresholdPercentage = 66
+	measure.QuorumPercentage = 50
+
+	resultRows := func(yes, no int64) []db.ListMeasureOptionsResultRow {
+		options := RandomBallotMeasureOptions(measure.ID, defaultMeasureOptions)
+		return []db.ListMeasureOptionsResultRow{
+			{ID: options[0].ID, MeasureID: measure.ID, Label: options[0].Label, Position: 1, VoteCount: yes, WeightedVoteCount: yes},
+			{ID: options[1].ID, MeasureID: measure.ID, Label: options[1].Label, Position: 2, VoteCount: no, WeightedVoteCount: no},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		rows          []db.ListMeasureOptionsResultRow
+		eligible      int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Passed",
+			rows:     resultRows(7, 3),
+			eligible: 20,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMeasureOutcome(t, recorder.Body, 10, true, true)
+			},
+		},
+		{
+			name:     "BelowThreshold",
+			rows:     resultRows(6, 4),
+			eligible: 20,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMeasureOutcome(t, recorder.Body, 10, true, false)
+			},
+		},
+		{
+			name:     "QuorumNotMet",
+			rows:     resultRows(9, 0),
+			eligible: 20,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMeasureOutcome(t, recorder.Body, 9, false, false)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				ListBallotMeasures(gomock.Any()).
+				Times(1).
+				Return([]db.BallotMeasure{measure}, nil)
+			store.EXPECT().
+				ListMeasureOptionsResult(gomock.Any()).
+				Times(1).
+				Return(tc.rows, nil)
+			store.EXPECT().
+				CountEligibleVoters(gomock.Any()).
+				Times(1).
+				Return(tc.eligible, nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/election/result/measures", nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func requireBodyMatchBallotMeasure(t *testing.T, body *bytes.Buffer, measure ballotMeasureResponse) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotMeasure ballotMeasureResponse
+	err = json.Unmarshal(data, &gotMeasure)
+	require.NoError(t, err)
+	require.Equal(t, measure, gotMeasure)
+}
+
+func requireBodyMatchMeasureOutcome(t *testing.T, body *bytes.Buffer, turnout int64, quorumMet bool, passed bool) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotResults []measureResultResponse
+	err = json.Unmarshal(data, &gotResults)
+	require.NoError(t, err)
+	require.Len(t, gotResults, 1)
+	require.Equal(t, turnout, gotResults[0].Turnout)
+	require.Equal(t, quorumMet, gotResults[0].QuorumMet)
+	require.Equal(t, passed, gotResults[0].Passed)
+}
+
+func RandomBallotMeasure() db.BallotMeasure {
+	return db.BallotMeasure{
+		ID:                  util.RandomInt(1, 1000),
+		Question:            util.RandomString(20),
+		ThresholdPercentage: 50,
+		QuorumPercentage:    0,
+		ElectionID:          1,
+	}
+}
+
+func RandomBallotMeasureOptions(measureID int64, labels []string) []db.BallotMeasureOption {
+	options := make([]db.BallotMeasureOption, len(labels))
+	for i, label := range labels {
+		options[i] = db.BallotMeasureOption{
+			ID:        util.RandomInt(1, 1000),
+			MeasureID: measureID,
+			Label:     label,
+			Position:  int32(i + 1),
+		}
+	}
+	return options
+}
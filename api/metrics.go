@@ -0,0 +1,211 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	db "election/db/sqlc"
+	"election/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	candidateVote = "candidate"
+	partyVote     = "party"
+	ballotVote    = "ballot"
+
+	passwordLogin  = "password"
+	twoFactorLogin = "two_factor"
+	oidcLogin      = "oidc"
+)
+
+var ErrInvalidMetricsToken = errors.New("Invalid metrics token")
+
+// serverMetrics are the metrics exported on /metrics
+type serverMetrics struct {
+	registry        *metrics.Registry
+	requests        *metrics.Counter
+	requestDuration *metrics.Histogram
+	votesCast       *metrics.Counter
+	loginFailures   *metrics.Counter
+}
+
+// newServerMetrics registers the request, vote and login metrics kept by the server, and the
+// database pool and election state read from the store on every scrape
+func newServerMetrics(store db.Store) *serverMetrics {
+	registry := metrics.NewRegistry()
+
+	m := &serverMetrics{
+		registry: registry,
+		requests: registry.NewCounter(
+			"election_http_requests_total",
+			"HTTP requests served by route and status.",
+			"method", "route", "status",
+		),
+		requestDuration: registry.NewHistogram(
+			"election_http_request_duration_seconds",
+			"HTTP request latency by route.",
+			metrics.DefaultBuckets,
+			"method", "route",
+		),
+		votesCast: registry.NewCounter(
+			"election_votes_cast_total",
+			"Votes cast since the server started by kind of vote.",
+			"kind",
+		),
+		loginFailures: registry.NewCounter(
+			"election_login_failures_total",
+			"Failed sign-ins by method.",
+			"method",
+		),
+	}
+
+	registerDBStats(registry, store)
+	registerElectionState(registry, store)
+
+	return m
+}
+
+func registerDBStats(registry *metrics.Registry, store db.Store) {
+	stat := func(value func(stats sql.DBStats) float64) metrics.CollectFunc {
+		return func(ctx context.Context) ([]metrics.Sample, error) {
+			return []metrics.Sample{{Value: value(store.DBStats())}}, nil
+		}
+	}
+
+	registry.NewGaugeFunc("election_db_max_open_connections", "Maximum number of open database connections.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.MaxOpenConnections) }))
+	registry.NewGaugeFunc("election_db_open_connections", "Open database connections.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.OpenConnections) }))
+	registry.NewGaugeFunc("election_db_in_use_connections", "Database connections in use.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.InUse) }))
+	registry.NewGaugeFunc("election_db_idle_connections", "Idle database connections.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.Idle) }))
+	registry.NewCounterFunc("election_db_wait_count_total", "Connections waited for.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.WaitCount) }))
+	registry.NewCounterFunc("election_db_wait_duration_seconds_total", "Time blocked waiting for a connection.", nil,
+		stat(func(stats sql.DBStats) float64 { return stats.WaitDuration.Seconds() }))
+	registry.NewCounterFunc("election_db_max_idle_closed_total", "Connections closed due to the idle limit.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.MaxIdleClosed) }))
+	registry.NewCounterFunc("election_db_max_lifetime_closed_total", "Connections closed due to the lifetime limit.", nil,
+		stat(func(stats sql.DBStats) float64 { return float64(stats.MaxLifetimeClosed) }))
+}
+
+func registerElectionState(registry *metrics.Registry, store db.Store) {
+	registry.NewGaugeFunc("election_property", "Election properties, 1 when enabled.", []string{"name"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			properties, err := store.ListElectionProperties(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			samples := make([]metrics.Sample, 0, len(properties))
+			for _, property := range properties {
+				samples = append(samples, metrics.Sample{
+					LabelValues: []string{property.Name},
+					Value:       boolValue(property.Value),
+				})
+			}
+			return samples, nil
+		})
+
+	registry.NewGaugeFunc("election_closed", "Whether the election is closed.", []string{"election_id"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			states, err := store.ListElectionStates(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			samples := make([]metrics.Sample, 0, len(states))
+			for _, state := range states {
+				samples = append(samples, metrics.Sample{
+					LabelValues: []string{strconv.FormatInt(state.ID, 10)},
+					Value:       boolValue(state.Closed),
+				})
+			}
+			return samples, nil
+		})
+
+	registry.NewGaugeFunc("election_active_votes", "Votes counted in the election, superseded votes excluded.", []string{"election_id"},
+		func(ctx context.Context) ([]metrics.Sample, error) {
+			states, err := store.ListElectionStates(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			samples := make([]metrics.Sample, 0, len(states))
+			for _, state := range states {
+				samples = append(samples, metrics.Sample{
+					LabelValues: []string{strconv.FormatInt(state.ID, 10)},
+					Value:       float64(state.ActiveVotes),
+				})
+			}
+			return samples, nil
+		})
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// middleware counts requests and their latency by the route pattern, so path parameters do not
+// create a series per ID
+func (m *serverMetrics) middleware() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.requests.Inc(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status()))
+		m.requestDuration.Observe(time.Since(start).Seconds(), ctx.Request.Method, route)
+	}
+}
+
+// countLoginFailures counts the sign-ins of the method refused for an unknown user, wrong
+// credentials or a disabled account, invalid requests are not counted
+func (m *serverMetrics) countLoginFailures(method string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Next()
+
+		switch ctx.Writer.Status() {
+		case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+			m.loginFailures.Inc(method)
+		}
+	}
+}
+
+// serveMetrics writes the metrics in the Prometheus text format, a bearer token is required
+// when one is configured
+func (server *Server) serveMetrics(ctx *gin.Context) {
+	if server.config.MetricsToken != "" {
+		fields := strings.Fields(ctx.GetHeader(authorizationHeaderKey))
+		if len(fields) != 2 || strings.ToLower(fields[0]) != authorizationTypeBearer ||
+			subtle.ConstantTimeCompare([]byte(fields[1]), []byte(server.config.MetricsToken)) != 1 {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidMetricsToken))
+			return
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := server.metrics.registry.Write(ctx, &buf); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/metrics"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func buildMetricsStubs(store *mockdb.MockStore) {
+	store.EXPECT().
+		DBStats().
+		AnyTimes().
+		Return(sql.DBStats{MaxOpenConnections: 10, OpenConnections: 3, InUse: 1, Idle: 2, WaitDuration: 1500 * time.Millisecond})
+	store.EXPECT().
+		ListElectionProperties(gomock.Any()).
+		Times(1).
+		Return([]db.ElectionProperty{
+			{Name: util.ElectionClosed, Value: true},
+			{Name: util.RequireManagerTwoFactor, Value: false},
+		}, nil)
+	store.EXPECT().
+		ListElectionStates(gomock.Any()).
+		Times(2).
+		Return([]db.ListElectionStatesRow{
+			{ID: 1, Closed: true, ActiveVotes: 42},
+			{ID: 2, Closed: false, ActiveVotes: 0},
+		}, nil)
+}
+
+func TestMetricsAPI(t *testing.T) {
+	metricsToken := util.RandomString(32)
+
+	testCases := []struct {
+		name          string
+		metricsToken  string
+		setupAuth     func(request *http.Request)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			setupAuth:  func(request *http.Request) {},
+			buildStubs: buildMetricsStubs,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, metrics.ContentType, recorder.Header().Get("Content-Type"))
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "election_db_max_open_connections 10\n")
+				require.Contains(t, body, "election_db_in_use_connections 1\n")
+				require.Contains(t, body, "election_db_wait_duration_seconds_total 1.5\n")
+				require.Contains(t, body, `election_property{name="`+util.ElectionClosed+`"} 1`)
+				require.Contains(t, body, `election_property{name="`+util.RequireManagerTwoFactor+`"} 0`)
+				require.Contains(t, body, `election_closed{election_id="1"} 1`)
+				require.Contains(t, body, `election_closed{election_id="2"} 0`)
+				require.Contains(t, body, `election_active_votes{election_id="1"} 42`)
+				require.Contains(t, body, "# TYPE election_votes_cast_total counter\n")
+			},
+		},
+		{
+			name:         "ValidToken",
+			metricsToken: metricsToken,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+metricsToken)
+			},
+			buildStubs: buildMetricsStubs,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:         "MissingToken",
+			metricsToken: metricsToken,
+			setupAuth:    func(request *http.Request) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListElectionStates(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:         "InvalidToken",
+			metricsToken: metricsToken,
+			setupAuth: func(request *http.Request) {
+				request.Header.Set(authorizationHeaderKey, "Bearer "+util.RandomString(32))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListElectionStates(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "InternalError",
+			setupAuth: func(request *http.Request) {},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DBStats().
+					AnyTimes().
+					Return(sql.DBStats{})
+				store.EXPECT().
+					ListElectionProperties(gomock.Any()).
+					AnyTimes().
+					Return([]db.ElectionProperty{}, nil)
+				store.EXPECT().
+					ListElectionStates(gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			config := newTestConfig(t)
+			config.MetricsToken = tc.metricsToken
+			server := newTestServerWithConfig(t, store, config)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(request)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestMetricsCountersAPI(t *testing.T) {
+	user, password := CreateRandomUser(t)
+	party := RandomParty()
+	election := RandomElection()
+	election.BallotType = util.BallotTypePartyList
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	buildMetricsStubs(store)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+		Times(2).
+		Return(user, nil)
+	store.EXPECT().
+		GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+		Times(1).
+		Return(CreateClosedElectionProperty(), nil)
+	store.EXPECT().
+		GetElection(gomock.Any(), gomock.Eq(election.ID)).
+		Times(1).
+		Return(election, nil)
+	store.EXPECT().
+		CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+		Times(1).
+		Return(int64(0), nil)
+	store.EXPECT().
+		CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+		Times(1).
+		Return(int64(0), nil)
+	store.EXPECT().
+		GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.Delegation{}, sql.ErrNoRows)
+	store.EXPECT().
+		GetParty(gomock.Any(), gomock.Eq(party.ID)).
+		Times(1).
+		Return(party, nil)
+	store.EXPECT().
+		CreatePartyVote(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.PartyVote{}, nil)
+
+	server := newTestServer(t, store)
+
+	serve := func(request *http.Request) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+	post := func(url string, body gin.H) *http.Request {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+		require.NoError(t, err)
+		return request
+	}
+
+	recorder := serve(post("/users/login", gin.H{
+		"national_id": user.NationalID,
+		"password":    password + "wrong",
+	}))
+	require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// an invalid request is not a failed sign-in
+	recorder = serve(post("/users/login", gin.H{}))
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	request := post("/api/vote/party", gin.H{
+		"nationalId": user.NationalID,
+		"electionId": election.ID,
+		"partyId":    party.ID,
+	})
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+	recorder = serve(request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	request, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	recorder = serve(request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	body := recorder.Body.String()
+	require.Contains(t, body, `election_login_failures_total{method="password"} 1`+"\n")
+	require.Contains(t, body, `election_votes_cast_total{kind="party"} 1`+"\n")
+	require.Contains(t, body, `election_http_requests_total{method="POST",route="/users/login",status="401"} 1`+"\n")
+	require.Contains(t, body, `election_http_requests_total{method="POST",route="/users/login",status="400"} 1`+"\n")
+	require.Contains(t, body, `election_http_requests_total{method="POST",route="/api/vote/party",status="200"} 1`+"\n")
+	require.Contains(t, body, `election_http_request_duration_seconds_count{method="POST",route="/api/vote/party"} 1`+"\n")
+}
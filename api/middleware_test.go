@@ -21,7 +21,7 @@ func addAuthorization(
 	nationalID string,
 	duration time.Duration,
 ) {
-	token, payload, err := tokenMaker.CreateToken(nationalID, duration)
+	token, payload, err := tokenMaker.CreateToken(nationalID, false, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, payload)
 
@@ -29,6 +29,20 @@ func addAuthorization(
 	request.Header.Set(authorizationHeaderKey, authorizationHeader)
 }
 
+// addTwoFactorAuthorization signs the request in with a session which passed a second factor
+func addTwoFactorAuthorization(
+	t *testing.T,
+	request *http.Request,
+	tokenMaker token.Maker,
+	nationalID string,
+	duration time.Duration,
+) {
+	token, _, err := tokenMaker.CreateToken(nationalID, true, duration)
+	require.NoError(t, err)
+
+	request.Header.Set(authorizationHeaderKey, fmt.Sprintf("%s %s", authorizationTypeBearer, token))
+}
+
 func TestAuthMiddleware(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -0,0 +1,242 @@
+package api
+
+import (
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	db "election/db/sqlc"
+	"election/oidc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+const (
+	oidcLoginDuration = 10 * time.Minute
+
+	defaultNationalIDClaim = "national_id"
+
+	oidcStateCookie     = "oidc_state"
+	oidcStateCookiePath = "/users/login/oidc"
+)
+
+var (
+	ErrInvalidOIDCState  = errors.New("Sign-in is unknown, used or expired")
+	ErrInvalidIdentity   = errors.New("Identity provider did not return a valid national ID")
+	ErrMissingEmailClaim = errors.New("Identity provider did not return an email")
+	ErrOIDCStateMismatch = errors.New("Sign-in was not started in this browser")
+)
+
+// newIdentityProvider returns the OpenID Connect provider users may sign in with, or nil when
+// no issuer is configured and users only sign in with a password
+func newIdentityProvider(config util.Config) (*oidc.Provider, error) {
+	if config.OIDCIssuerURL == "" {
+		return nil, nil
+	}
+	return oidc.NewProvider(
+		config.OIDCIssuerURL,
+		config.OIDCClientID,
+		config.OIDCClientSecret,
+		config.OIDCRedirectURL,
+	)
+}
+
+type startOIDCLoginResponse struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	ExpiredAt        time.Time `json:"expired_at"`
+}
+
+// startOIDCLogin begins a sign-in through the identity provider, the client sends the user to the
+// authorization URL and posts the code and state the user returns with to the callback. The state
+// is also kept in a cookie so the callback only completes in the browser that started the sign-in.
+func (server *Server) startOIDCLogin(ctx *gin.Context) {
+	state, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	nonce, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	codeVerifier, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	authorizationURL, err := server.identityProvider.AuthCodeURL(ctx, state, nonce, codeVerifier)
+	if err != nil {
+		ctx.JSON(http.StatusBadGateway, errorResponse(ctx, err))
+		return
+	}
+
+	login, err := server.store.CreateOIDCLogin(ctx, db.CreateOIDCLoginParams{
+		StateHash:    util.HashSecretToken(state),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiredAt:    time.Now().Add(oidcLoginDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.setOIDCStateCookie(ctx, state, int(oidcLoginDuration.Seconds()))
+	ctx.JSON(http.StatusOK, startOIDCLoginResponse{
+		AuthorizationURL: authorizationURL,
+		ExpiredAt:        login.ExpiredAt,
+	})
+}
+
+type loginOIDCRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// loginOIDC completes a sign-in through the identity provider, the user is identified by the
+// national ID claim of the id token and provisioned on the first sign-in
+func (server *Server) loginOIDC(ctx *gin.Context) {
+	var req loginOIDCRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	// the state cookie is cleared whatever the outcome, a failed sign-in starts over
+	cookieState, err := ctx.Cookie(oidcStateCookie)
+	server.setOIDCStateCookie(ctx, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookieState), []byte(req.State)) != 1 {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrOIDCStateMismatch))
+		return
+	}
+
+	// the state is used once so a code cannot be replayed against the same sign-in
+	login, err := server.store.UseOIDCLogin(ctx, util.HashSecretToken(req.State))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidOIDCState))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rawIDToken, err := server.identityProvider.Exchange(ctx, req.Code, login.CodeVerifier)
+	if err != nil {
+		if errors.Is(err, oidc.ErrCodeRejected) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(ctx, err))
+		return
+	}
+
+	claims, err := server.identityProvider.Verify(ctx, rawIDToken, login.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidToken) || errors.Is(err, oidc.ErrExpiredToken) || errors.Is(err, oidc.ErrUnknownKey) {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusBadGateway, errorResponse(ctx, err))
+		return
+	}
+
+	nationalID := claims.String(server.nationalIDClaim())
+	if !util.IsNationalID(nationalID) {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrInvalidIdentity))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, nationalID)
+	if err != nil {
+		if err != sql.ErrNoRows {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+
+		var ok bool
+		user, ok = server.provisionUser(ctx, nationalID, claims)
+		if !ok {
+			return
+		}
+	}
+
+	server.completeLogin(ctx, user)
+}
+
+// setOIDCStateCookie keeps the state of a sign-in in the browser until the callback,
+// a negative max age clears it
+func (server *Server) setOIDCStateCookie(ctx *gin.Context, state string, maxAge int) {
+	http.SetCookie(ctx.Writer, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     oidcStateCookiePath,
+		MaxAge:   maxAge,
+		Secure:   strings.HasPrefix(server.config.AppBaseURL, "https://"),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// provisionUser creates the voter signing in for the first time from the claims of the id token,
+// the account has no usable password until the user resets it
+func (server *Server) provisionUser(ctx *gin.Context, nationalID string, claims oidc.Claims) (db.User, bool) {
+	email := claims.String("email")
+	if email == "" {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrMissingEmailClaim))
+		return db.User{}, false
+	}
+
+	password, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.User{}, false
+	}
+	hashedPassword, err := util.HashPassword(password)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.User{}, false
+	}
+
+	result, err := server.store.ProvisionUserTx(ctx, db.ProvisionUserTxParams{
+		CreateUserParams: db.CreateUserParams{
+			NationalID:     nationalID,
+			HashedPassword: hashedPassword,
+			FullName:       claims.String("name"),
+			Email:          email,
+			Permission:     []string{util.Vote},
+		},
+		EmailVerified: claims.Bool("email_verified"),
+	})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+			return db.User{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.User{}, false
+	}
+
+	if !result.User.VerifiedAt.Valid {
+		// the user can ask for a resend when the verification email cannot be sent
+		if err := server.sendVerificationEmail(ctx, result.User); err != nil {
+			ctx.Error(err)
+		}
+	}
+
+	return result.User, true
+}
+
+func (server *Server) nationalIDClaim() string {
+	if server.config.OIDCNationalIDClaim == "" {
+		return defaultNationalIDClaim
+	}
+	return server.config.OIDCNationalIDClaim
+}
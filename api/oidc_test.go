@@ -0,0 +1,486 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/mail"
+	"election/oidc/oidctest"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOIDCTestServer(t *testing.T, store *mockdb.MockStore) (*Server, *oidctest.IdP) {
+	idp, err := oidctest.NewIdP("election", util.RandomString(32))
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	config := newTestConfig(t)
+	config.OIDCIssuerURL = idp.URL
+	config.OIDCClientID = idp.ClientID
+	config.OIDCClientSecret = idp.ClientSecret
+	config.OIDCRedirectURL = config.AppBaseURL + "/login/oidc"
+
+	return newTestServerWithConfig(t, store, config), idp
+}
+
+// startOIDCLogin begins a sign-in and returns the authorization URL with the login the server stored
+// and the state cookie it set
+func startOIDCLogin(t *testing.T, server *Server, store *mockdb.MockStore) (string, db.OidcLogin, *http.Cookie) {
+	var login db.OidcLogin
+	store.EXPECT().
+		CreateOIDCLogin(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ interface{}, arg db.CreateOIDCLoginParams) (db.OidcLogin, error) {
+			login = db.OidcLogin{
+				ID:           util.RandomInt(1, 1000),
+				StateHash:    arg.StateHash,
+				Nonce:        arg.Nonce,
+				CodeVerifier: arg.CodeVerifier,
+				ExpiredAt:    arg.ExpiredAt,
+				CreateAt:     time.Now(),
+			}
+			return login, nil
+		})
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login/oidc", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp startOIDCLoginResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.WithinDuration(t, time.Now().Add(oidcLoginDuration), rsp.ExpiredAt, time.Second)
+
+	cookie := findCookie(recorder, oidcStateCookie)
+	require.NotNil(t, cookie)
+	require.True(t, cookie.HttpOnly)
+	require.Equal(t, oidcStateCookiePath, cookie.Path)
+	require.Equal(t, login.StateHash, util.HashSecretToken(cookie.Value))
+	return rsp.AuthorizationURL, login, cookie
+}
+
+func findCookie(recorder *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestLoginOIDCAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+
+	disabled := user
+	disabled.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	unknownState := util.RandomString(43)
+
+	identity := func(verified bool) map[string]interface{} {
+		return map[string]interface{}{
+			"sub":            util.RandomString(16),
+			"national_id":    user.NationalID,
+			"name":           user.FullName,
+			"email":          user.Email,
+			"email_verified": verified,
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		claims        map[string]interface{}
+		body          func(code, state string) gin.H
+		cookie        func(cookie *http.Cookie) *http.Cookie
+		buildStubs    func(store *mockdb.MockStore, login db.OidcLogin)
+		checkResponse func(recorder *httptest.ResponseRecorder, messages []mail.Message)
+	}{
+		{
+			name:   "ExistingUser",
+			claims: identity(true),
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					ProvisionUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				require.Equal(t, user.NationalID, rsp.User.NationalID)
+
+				cookie := findCookie(recorder, oidcStateCookie)
+				require.NotNil(t, cookie)
+				require.Empty(t, cookie.Value)
+				require.Negative(t, cookie.MaxAge)
+			},
+		},
+		{
+			name:   "ProvisionVerifiedUser",
+			claims: identity(true),
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					ProvisionUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.ProvisionUserTxParams) (db.ProvisionUserTxResult, error) {
+						require.Equal(t, user.NationalID, arg.NationalID)
+						require.Equal(t, user.FullName, arg.FullName)
+						require.Equal(t, user.Email, arg.Email)
+						require.Equal(t, []string{util.Vote}, arg.Permission)
+						require.NotEmpty(t, arg.HashedPassword)
+						require.True(t, arg.EmailVerified)
+						return db.ProvisionUserTxResult{User: user}, nil
+					})
+				store.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Empty(t, messages)
+			},
+		},
+		{
+			name:   "ProvisionUnverifiedUser",
+			claims: identity(false),
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				unverified := user
+				unverified.VerifiedAt = sql.NullTime{}
+
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					ProvisionUserTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ProvisionUserTxResult{User: unverified}, nil)
+				store.EXPECT().
+					CreateEmailVerification(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.EmailVerification{}, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Len(t, messages, 1)
+				require.Equal(t, user.Email, messages[0].To)
+			},
+		},
+		{
+			name:   "TwoFactorRequired",
+			claims: identity(true),
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{
+						NationalID: user.NationalID,
+						EnabledAt:  sql.NullTime{Time: time.Now(), Valid: true},
+					}, nil)
+				store.EXPECT().
+					CreateTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactorChallenge{ExpiredAt: time.Now().Add(time.Minute)}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp gin.H
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, true, rsp["two_factor_required"])
+				require.Nil(t, rsp["access_token"])
+			},
+		},
+		{
+			name:   "DisabledUser",
+			claims: identity(true),
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(disabled, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "UnknownState",
+			claims: identity(true),
+			body: func(code, state string) gin.H {
+				return gin.H{"code": code, "state": unknownState}
+			},
+			cookie: func(cookie *http.Cookie) *http.Cookie {
+				return &http.Cookie{Name: cookie.Name, Value: unknownState}
+			},
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.OidcLogin{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingStateCookie",
+			claims: identity(true),
+			cookie: func(cookie *http.Cookie) *http.Cookie {
+				return nil
+			},
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:   "StateCookieMismatch",
+			claims: identity(true),
+			cookie: func(cookie *http.Cookie) *http.Cookie {
+				return &http.Cookie{Name: cookie.Name, Value: util.RandomString(43)}
+			},
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+				cookie := findCookie(recorder, oidcStateCookie)
+				require.NotNil(t, cookie)
+				require.Negative(t, cookie.MaxAge)
+			},
+		},
+		{
+			name:   "CodeRejected",
+			claims: identity(true),
+			body: func(code, state string) gin.H {
+				return gin.H{"code": util.RandomString(43), "state": state}
+			},
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidNationalID",
+			claims: map[string]interface{}{
+				"sub":         util.RandomString(16),
+				"national_id": "12345",
+				"email":       user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "MissingEmail",
+			claims: map[string]interface{}{
+				"sub":         util.RandomString(16),
+				"national_id": user.NationalID,
+			},
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					ProvisionUserTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalError",
+			claims: identity(true),
+			buildStubs: func(store *mockdb.MockStore, login db.OidcLogin) {
+				store.EXPECT().
+					UseOIDCLogin(gomock.Any(), gomock.Eq(login.StateHash)).
+					Times(1).
+					Return(login, nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, messages []mail.Message) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			server, idp := newOIDCTestServer(t, store)
+
+			authURL, login, cookie := startOIDCLogin(t, server, store)
+
+			code, state, err := idp.Authorize(authURL, tc.claims)
+			require.NoError(t, err)
+			require.Equal(t, login.StateHash, util.HashSecretToken(state))
+
+			tc.buildStubs(store, login)
+
+			body := gin.H{"code": code, "state": state}
+			if tc.body != nil {
+				body = tc.body(code, state)
+			}
+			data, err := json.Marshal(body)
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodPost, "/users/login/oidc/callback", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			if tc.cookie != nil {
+				cookie = tc.cookie(cookie)
+			}
+			if cookie != nil {
+				request.AddCookie(&http.Cookie{Name: cookie.Name, Value: cookie.Value})
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder, server.mailer.(*mail.MemoryMailer).Messages())
+		})
+	}
+}
+
+func TestStartOIDCLoginUnavailableAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		CreateOIDCLogin(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server, idp := newOIDCTestServer(t, store)
+	idp.Close()
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login/oidc", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusBadGateway, recorder.Code)
+}
+
+func TestOIDCLoginDisabledAPI(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodPost, "/users/login/oidc", nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+var (
+	ErrPartyExists          = errors.New("Party name already exists")
+	ErrNotPartyListElection = errors.New("Election is not a party-list election")
+	ErrPartyListElection    = errors.New("Party-list election is voted by party")
+)
+
+func nullPartyID(partyID int64) sql.NullInt64 {
+	return sql.NullInt64{
+		Int64: partyID,
+		Valid: partyID > 0,
+	}
+}
+
+type createPartyRequest struct {
+	Name    string `json:"name" binding:"required"`
+	LogoUrl string `json:"logo_url" binding:"omitempty,url"`
+	Color   string `json:"color" binding:"omitempty,hexcolor"`
+}
+
+func (server Server) createParty(ctx *gin.Context) {
+	var req createPartyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.CreatePartyParams{
+		Name:    req.Name,
+		LogoUrl: req.LogoUrl,
+		Color:   req.Color,
+	}
+
+	party, err := server.store.CreateParty(ctx, arg)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyExists))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, party)
+}
+
+func (server Server) listParties(ctx *gin.Context) {
+	parties, err := server.store.ListParties(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, parties)
+}
+
+type partyRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+func (server Server) updateParty(ctx *gin.Context) {
+	var uri partyRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req createPartyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.UpdatePartyParams{
+		ID:      uri.ID,
+		Name:    req.Name,
+		LogoUrl: req.LogoUrl,
+		Color:   req.Color,
+	}
+
+	party, err := server.store.UpdateParty(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyExists))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, party)
+}
+
+type votePartyRequest struct {
+	NationalId string `json:"nationalId" binding:"required,number,len=13"`
+	ElectionId int64  `json:"electionId" binding:"required,min=1"`
+	PartyId    int64  `json:"partyId" binding:"required,min=1"`
+}
+
+// voteParty casts a party-list ballot, the voter picks the slate of a party
+// instead of a single candidate
+func (server Server) voteParty(ctx *gin.Context) {
+	var req votePartyRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.NationalId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	isClosedElection, err := server.store.GetElectionProperty(ctx, util.ElectionClosed)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if isClosedElection.Value {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, req.ElectionId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return
+	}
+
+	if election.BallotType != util.BallotTypePartyList {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrNotPartyListElection))
+		return
+	}
+
+	if !server.validSingleContestVote(ctx, election.ID) {
+		return
+	}
+
+	castBy, valid := server.validBallotCaster(ctx, user, election.ID)
+	if !valid {
+		return
+	}
+
+	party, err := server.store.GetParty(ctx, req.PartyId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.CreatePartyVoteParams{
+		VoteNationalID:   user.NationalID,
+		ElectionID:       election.ID,
+		PartyID:          party.ID,
+		CastByNationalID: castBy,
+	}
+
+	_, err = server.store.CreatePartyVote(ctx, arg)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrAlreadyVoted))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.metrics.votesCast.Inc(partyVote)
+
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+type partyResult struct {
+	ID                int64  `json:"id"`
+	Name              string `json:"name"`
+	LogoUrl           string `json:"logo_url"`
+	Color             string `json:"color"`
+	CandidateCount    int64  `json:"candidate_count"`
+	VoteCount         int64  `json:"vote_count"`
+	WeightedVoteCount int64  `json:"weighted_vote_count"`
+	Seats             int    `json:"seats"`
+}
+
+// partyResultsResponse aggregates an election by party, the votes are the party-list
+// ballots of a list election and the sum of the candidate votes otherwise
+type partyResultsResponse struct {
+	Election db.Election   `json:"election"`
+	Turnout  int64         `json:"turnout"`
+	Parties  []partyResult `json:"parties"`
+}
+
+func newPartyResultsResponse(election db.Election, parties []db.ListElectionPartiesResultRow, turnout int64) partyResultsResponse {
+	rsp := partyResultsResponse{
+		Election: election,
+		Turnout:  turnout,
+		Parties:  make([]partyResult, 0, len(parties)),
+	}
+
+	tallies := make([]util.PartyTally, 0, len(parties))
+	for _, party := range parties {
+		result := partyResult{
+			ID:                party.ID,
+			Name:              party.Name,
+			LogoUrl:           party.LogoUrl,
+			Color:             party.Color,
+			CandidateCount:    party.CandidateCount,
+			VoteCount:         party.CandidateVoteCount,
+			WeightedVoteCount: party.WeightedCandidateVoteCount,
+		}
+		if election.BallotType == util.BallotTypePartyList {
+			result.VoteCount = party.ListVoteCount
+			result.WeightedVoteCount = party.WeightedListVoteCount
+		}
+
+		// parties that neither run in the election nor received votes are left out
+		if result.CandidateCount == 0 && result.VoteCount == 0 {
+			continue
+		}
+
+		rsp.Parties = append(rsp.Parties, result)
+		tallies = append(tallies, util.PartyTally{
+			PartyID: result.ID,
+			Votes:   result.WeightedVoteCount,
+		})
+	}
+
+	if election.Seats > 0 {
+		seats := util.AllocateSeats(tallies, int(election.Seats), election.SeatMethod, election.TieBreakSeed)
+		for i := range rsp.Parties {
+			rsp.Parties[i].Seats = seats[rsp.Parties[i].ID]
+		}
+	}
+
+	return rsp
+}
+
+func (server Server) electionPartiesResult(ctx *gin.Context) {
+	var req electionRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, err := server.store.GetElection(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	parties, err := server.store.ListElectionPartiesResult(ctx, election.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	turnout, err := server.store.CountActiveVotes(ctx, election.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newPartyResultsResponse(election, parties, turnout))
+}
@@ -0,0 +1,560 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePartyAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	party := RandomParty()
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":     party.Name,
+				"logo_url": party.LogoUrl,
+				"color":    party.Color,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				arg := db.CreatePartyParams{
+					Name:    party.Name,
+					LogoUrl: party.LogoUrl,
+					Color:   party.Color,
+				}
+				store.EXPECT().
+					CreateParty(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(party, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got db.Party
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, party.ID, got.ID)
+				require.Equal(t, party.Name, got.Name)
+				require.Equal(t, party.Color, got.Color)
+			},
+		},
+		{
+			name: "DuplicateName",
+			body: gin.H{
+				"name": party.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateParty(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Party{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidColor",
+			body: gin.H{
+				"name":  party.Name,
+				"color": "blue",
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateParty(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{
+				"name": party.Name,
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateParty(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Party{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/parties", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVotePartyAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	party := RandomParty()
+	closedElectionProperty := CreateClosedElectionProperty()
+
+	election := RandomElection()
+	election.BallotType = util.BallotTypePartyList
+	candidateElection := RandomElection()
+	closedElection := election
+	closedElection.Closed = true
+
+	body := gin.H{
+		"nationalId": user.NationalID,
+		"electionId": election.ID,
+		"partyId":    party.ID,
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					GetParty(gomock.Any(), gomock.Eq(party.ID)).
+					Times(1).
+					Return(party, nil)
+
+				arg := db.CreatePartyVoteParams{
+					VoteNationalID: user.NationalID,
+					ElectionID:     election.ID,
+					PartyID:        party.ID,
+				}
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.PartyVote{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotPartyListElection",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(candidateElection, nil)
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionHasMeasures",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ElectionClosed",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(closedElection, nil)
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PartyNotFound",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					GetParty(gomock.Any(), gomock.Eq(party.ID)).
+					Times(1).
+					Return(db.Party{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyVoted",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					GetParty(gomock.Any(), gomock.Eq(party.ID)).
+					Times(1).
+					Return(party, nil)
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PartyVote{}, &pq.Error{Code: "23505"})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoPermissionNationalID",
+			body: body,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, "1234567890124", time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(election, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				delegationArg := db.GetApprovedDelegationParams{
+					GrantorNationalID: user.NationalID,
+					ProxyNationalID:   "1234567890124",
+					ElectionID:        election.ID,
+				}
+				store.EXPECT().
+					GetApprovedDelegation(gomock.Any(), gomock.Eq(delegationArg)).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreatePartyVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/api/vote/party", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestElectionPartiesResultAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	listElection := RandomElection()
+	listElection.BallotType = util.BallotTypePartyList
+	listElection.Seats = 8
+	listElection.SeatMethod = util.SeatMethodDHondt
+
+	candidateElection := RandomElection()
+
+	rows := []db.ListElectionPartiesResultRow{
+		{ID: 1, Name: util.RandomName(), CandidateCount: 3, ListVoteCount: 100000, WeightedListVoteCount: 100000, CandidateVoteCount: 5, WeightedCandidateVoteCount: 5},
+		{ID: 2, Name: util.RandomName(), CandidateCount: 3, ListVoteCount: 80000, WeightedListVoteCount: 80000, CandidateVoteCount: 7, WeightedCandidateVoteCount: 9},
+		{ID: 3, Name: util.RandomName(), CandidateCount: 1, ListVoteCount: 30000, WeightedListVoteCount: 30000},
+		{ID: 4, Name: util.RandomName(), CandidateCount: 1, ListVoteCount: 20000, WeightedListVoteCount: 20000},
+		{ID: 5, Name: util.RandomName()},
+	}
+
+	testCases := []struct {
+		name          string
+		election      db.Election
+		checkResponse func(t *testing.T, rsp partyResultsResponse)
+	}{
+		{
+			name:     "PartyList",
+			election: listElection,
+			checkResponse: func(t *testing.T, rsp partyResultsResponse) {
+				require.Len(t, rsp.Parties, 4)
+
+				seats := make(map[int64]int)
+				for _, party := range rsp.Parties {
+					seats[party.ID] = party.Seats
+				}
+				require.Equal(t, map[int64]int{1: 4, 2: 3, 3: 1, 4: 0}, seats)
+				require.Equal(t, int64(100000), rsp.Parties[0].VoteCount)
+			},
+		},
+		{
+			name:     "CandidateVotes",
+			election: candidateElection,
+			checkResponse: func(t *testing.T, rsp partyResultsResponse) {
+				require.Len(t, rsp.Parties, 4)
+				require.Equal(t, int64(7), rsp.Parties[1].VoteCount)
+				require.Equal(t, int64(9), rsp.Parties[1].WeightedVoteCount)
+				for _, party := range rsp.Parties {
+					require.Zero(t, party.Seats)
+				}
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetElection(gomock.Any(), gomock.Eq(tc.election.ID)).
+				Times(1).
+				Return(tc.election, nil)
+			store.EXPECT().
+				ListElectionPartiesResult(gomock.Any(), gomock.Eq(tc.election.ID)).
+				Times(1).
+				Return(rows, nil)
+			store.EXPECT().
+				CountActiveVotes(gomock.Any(), gomock.Eq(tc.election.ID)).
+				Times(1).
+				Return(int64(230000), nil)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/elections/%d/parties", tc.election.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			require.Equal(t, http.StatusOK, recorder.Code)
+
+			var rsp partyResultsResponse
+			err = json.Unmarshal(recorder.Body.Bytes(), &rsp)
+			require.NoError(t, err)
+			require.Equal(t, int64(230000), rsp.Turnout)
+
+			tc.checkResponse(t, rsp)
+		})
+	}
+}
+
+func RandomParty() db.Party {
+	return db.Party{
+		ID:      util.RandomInt(1, 1000),
+		Name:    util.RandomName(),
+		LogoUrl: util.RandomImageLink(),
+		Color:   "#336699",
+	}
+}
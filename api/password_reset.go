@@ -0,0 +1,138 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "election/db/sqlc"
+	"election/mail"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ErrInvalidResetToken = errors.New("Password reset token is invalid or expired")
+
+// newMailer picks the mailer of the config, SMTP when a host is set, .eml files for local development
+// when a mail directory is set and otherwise messages are only kept in memory
+func newMailer(config util.Config) (mail.Mailer, error) {
+	if config.SMTPHost != "" {
+		return mail.NewSMTPMailer(
+			config.SMTPHost,
+			config.SMTPPort,
+			config.SMTPUsername,
+			config.SMTPPassword,
+			config.MailSender,
+		)
+	}
+	if config.MailDir != "" {
+		return mail.NewFileMailer(config.MailDir, config.MailSender)
+	}
+	return mail.NewMemoryMailer(), nil
+}
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type passwordResetEmail struct {
+	Name      string
+	Link      string
+	ExpiresIn time.Duration
+}
+
+// forgotPassword emails a single use reset link to the account of the email, the response is the same
+// whether or not an account exists so the endpoint cannot be used to find registered emails
+func (server *Server) forgotPassword(ctx *gin.Context) {
+	var req forgotPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, err := server.store.GetUserByEmail(ctx, req.Email)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusOK, successResponse())
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	resetToken, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	_, err = server.store.CreatePasswordReset(ctx, db.CreatePasswordResetParams{
+		NationalID: user.NationalID,
+		TokenHash:  util.HashSecretToken(resetToken),
+		ExpiredAt:  time.Now().Add(server.config.PasswordResetDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// a failure to send is only logged, the response must be the same as for an unknown email
+	if err := server.sendPasswordResetEmail(ctx, user, resetToken); err != nil {
+		ctx.Error(err)
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+func (server *Server) sendPasswordResetEmail(ctx *gin.Context, user db.User, resetToken string) error {
+	msg, err := mail.Render(mail.PasswordResetTemplate, user.Email, passwordResetEmail{
+		Name:      user.FullName,
+		Link:      server.config.AppBaseURL + "/reset-password?token=" + url.QueryEscape(resetToken),
+		ExpiresIn: server.config.PasswordResetDuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	return server.mailer.Send(ctx, msg)
+}
+
+type resetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
+}
+
+// resetPassword sets a new password with a token of forgotPassword, the tokens issued
+// before are rejected from then on like after a password change
+func (server *Server) resetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	_, err = server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		TokenHash:         util.HashSecretToken(req.Token),
+		HashedPassword:    hashedPassword,
+		PasswordChangedAt: time.Now(),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrInvalidResetToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+// hasPermission reports whether the authenticated user holds the given permission for all elections
+func (server *Server) hasPermission(ctx *gin.Context, permission string) (bool, error) {
+	return server.hasElectionPermission(ctx, permission, 0)
+}
+
+// hasElectionPermission reports whether the authenticated user holds the given permission, either
+// directly, through MANAGE_ELECTION or through a role assigned for all elections or for electionID.
+// A permission which changes the election also needs two-factor authentication while it is
+// required for managers.
+func (server *Server) hasElectionPermission(ctx *gin.Context, permission string, electionID int64) (bool, error) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	permissions, err := server.store.GetUserPermissions(ctx, authPayload.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+
+	if contains(permissions, permission) && permission != util.ManageElection {
+		return true, nil
+	}
+
+	granted := contains(permissions, util.ManageElection)
+	if !granted {
+		roles, err := server.store.ListUserRoles(ctx, authPayload.NationalID)
+		if err != nil {
+			return false, err
+		}
+		granted = rolesGrant(roles, permission, electionID)
+	}
+
+	if !granted {
+		return false, nil
+	}
+	if util.IsReadOnlyPermission(permission) {
+		return true, nil
+	}
+	return server.twoFactorSatisfied(ctx, authPayload)
+}
+
+// rolesGrant reports whether one of the roles grants permission, a role assigned for a single
+// election only counts for that election
+func rolesGrant(roles []db.UserRole, permission string, electionID int64) bool {
+	for _, role := range roles {
+		if role.ElectionID.Valid && role.ElectionID.Int64 != electionID {
+			continue
+		}
+		if util.RoleGrants(role.Role, permission) {
+			return true
+		}
+	}
+	return false
+}
+
+func userHasPermission(user db.User, permission string) bool {
+	return contains(user.Permission, permission)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// requirePermission is a route-level requirement of a permission for all elections,
+// it must run after sessionMiddleware
+func (server *Server) requirePermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		allowed, err := server.hasPermission(ctx, permission)
+		authorizeRoute(ctx, allowed, err)
+	}
+}
+
+// requireAnyPermission is a route-level requirement of one of the permissions for all elections,
+// it must run after sessionMiddleware
+func (server *Server) requireAnyPermission(permissions ...string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		for _, permission := range permissions {
+			allowed, err := server.hasPermission(ctx, permission)
+			if err != nil || allowed {
+				authorizeRoute(ctx, allowed, err)
+				return
+			}
+		}
+		authorizeRoute(ctx, false, nil)
+	}
+}
+
+// requireElectionPermission is a route-level requirement of a permission for the election of the
+// id route parameter, a role assigned for that election is enough
+func (server *Server) requireElectionPermission(permission string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		electionID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+		if err != nil || electionID < 1 {
+			// the handler reports the invalid ID, only a permission for all elections applies
+			electionID = 0
+		}
+
+		allowed, err := server.hasElectionPermission(ctx, permission, electionID)
+		authorizeRoute(ctx, allowed, err)
+	}
+}
+
+func authorizeRoute(ctx *gin.Context, allowed bool, err error) {
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if !allowed {
+		ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ctx, ErrNoPermission))
+		return
+	}
+
+	ctx.Next()
+}
@@ -0,0 +1,487 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequireElectionPermission(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	election := RandomElection()
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OfficerForElection",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.ElectionOfficerRole, election.ID))
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				// the requirement is met, the handler reports the missing election
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "OfficerForAllElections",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.ElectionOfficerRole, 0))
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Eq(election.ID)).
+					Times(1).
+					Return(db.Election{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "OfficerForOtherElection",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.ElectionOfficerRole, election.ID+1))
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "RoleWithoutPermission",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.ObserverRole, election.ID))
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "OfficerWithoutTwoFactorSession",
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.ElectionOfficerRole, election.ID))
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireManagerTwoFactor)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.RequireManagerTwoFactor, Value: true}, nil)
+				// the token was issued without a second factor, enrolling afterwards does not upgrade it
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					AnyTimes().
+					Return(randomTwoFactor(t, user.NationalID, true), nil)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserPermissions(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user.Permission, nil)
+				store.EXPECT().
+					ListUserRoles(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+				store.EXPECT().
+					GetElection(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(gin.H{
+				"winner_rule": util.WinnerRulePlurality,
+				"tie_break":   util.TieBreakLottery,
+			})
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/api/elections/%d/rules", election.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestReadOnlyPermissionAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.AuditorRole, 0))
+
+	// viewing the audit log needs no second factor even while it is required for managers
+	store.EXPECT().
+		GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireManagerTwoFactor)).
+		AnyTimes().
+		Return(db.ElectionProperty{Name: util.RequireManagerTwoFactor, Value: true}, nil)
+	store.EXPECT().
+		GetTwoFactor(gomock.Any(), gomock.Any()).
+		AnyTimes().
+		Return(db.TwoFactor{}, sql.ErrNoRows)
+	store.EXPECT().
+		ListUserAudits(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return([]db.UserAudit{}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/api/admin/audits?page_size=5", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
+
+func TestCandidateRevisionsPermissionAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	revisions := []db.CandidateRevision{randomCandidateRevision(t, candidate, 1)}
+
+	testCases := []struct {
+		name          string
+		role          string
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Auditor",
+			role: util.AuditorRole,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "CandidateManager",
+			role: util.CandidateManagerRole,
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NoRole",
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			if tc.role != "" {
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, tc.role, 0))
+				store.EXPECT().
+					ListCandidateRevisions(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(revisions, nil)
+			} else {
+				stubUserRoles(store, user.NationalID)
+				store.EXPECT().
+					ListCandidateRevisions(gomock.Any(), gomock.Any()).
+					Times(0)
+			}
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/candidates/%d/revisions", candidate.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+// protectedRoutes lists every route behind a permission with the permissions that let a user in,
+// the test server lets its users in by default so each route needs its own forbidden case
+var protectedRoutes = []struct {
+	method      string
+	path        string
+	permissions []string
+}{
+	{http.MethodHead, "/election/export", []string{util.ViewAudit}},
+	{http.MethodPost, "/api/candidates", []string{util.ManageCandidates}},
+	{http.MethodPut, "/api/candidates", []string{util.ManageCandidates}},
+	{http.MethodDelete, "/api/candidates/:id", []string{util.ManageCandidates}},
+	{http.MethodPost, "/api/candidates/:id/restore", []string{util.ManageCandidates}},
+	{http.MethodPost, "/api/candidates/:id/image", []string{util.ManageCandidates}},
+	{http.MethodGet, "/api/candidates/:id/revisions", []string{util.ViewAudit, util.ManageCandidates}},
+	{http.MethodGet, "/api/candidates/:id/revisions/diff", []string{util.ViewAudit, util.ManageCandidates}},
+	{http.MethodPost, "/api/candidates/:id/revisions/:version/rollback", []string{util.ManageCandidates}},
+	{http.MethodPost, "/api/districts", []string{util.ManageVoters}},
+	{http.MethodPost, "/api/districts/roll", []string{util.ManageVoters}},
+	{http.MethodPut, "/api/users/weight", []string{util.ManageVoters}},
+	{http.MethodPost, "/api/users/weights", []string{util.ManageVoters}},
+	{http.MethodPost, "/api/parties", []string{util.ConductElection}},
+	{http.MethodPut, "/api/parties/:id", []string{util.ConductElection}},
+	{http.MethodPost, "/api/measures", []string{util.ConductElection}},
+	{http.MethodGet, "/api/delegations", []string{util.ManageVoters}},
+	{http.MethodPost, "/api/delegations/:id/approve", []string{util.ManageVoters}},
+	{http.MethodPost, "/api/elections/:id/contests", []string{util.ConductElection}},
+	{http.MethodPut, "/api/elections/:id/rules", []string{util.ConductElection}},
+	{http.MethodPost, "/api/elections/:id/runoff", []string{util.ConductElection}},
+	{http.MethodPost, "/api/election/toggle", []string{util.ConductElection}},
+	{http.MethodPost, "/api/election/revote", []string{util.ConductElection}},
+	{http.MethodPost, "/api/election/withdrawal", []string{util.ConductElection}},
+	{http.MethodPost, "/api/election/verification", []string{util.ConductElection}},
+	{http.MethodPost, "/api/election/manager-2fa", []string{util.ManageUsers}},
+	{http.MethodGet, "/api/admin/users", []string{util.ViewUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/permissions/grant", []string{util.ManageUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/permissions/revoke", []string{util.ManageUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/disable", []string{util.ManageUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/enable", []string{util.ManageUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/reset-vote", []string{util.ManageUsers}},
+	{http.MethodGet, "/api/admin/users/:national_id/roles", []string{util.ViewUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/roles/assign", []string{util.ManageUsers}},
+	{http.MethodPost, "/api/admin/users/:national_id/roles/unassign", []string{util.ManageUsers}},
+	{http.MethodGet, "/api/admin/audits", []string{util.ViewAudit}},
+}
+
+func TestProtectedRoutesForbiddenAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	params := strings.NewReplacer(":national_id", user.NationalID, ":id", "1", ":version", "1")
+
+	for _, route := range protectedRoutes {
+		route := route
+
+		// a voter holds no role, the other user holds the first role granting none of the permissions
+		roles := map[string][]db.UserRole{"Voter": nil}
+		for _, role := range util.Roles() {
+			granted := false
+			for _, permission := range route.permissions {
+				granted = granted || util.RoleGrants(role, permission)
+			}
+			if !granted {
+				roles[role] = []db.UserRole{randomUserRole(user.NationalID, role, 0)}
+				break
+			}
+		}
+
+		for name, userRoles := range roles {
+			userRoles := userRoles
+
+			t.Run(fmt.Sprintf("%s %s %s", route.method, route.path, name), func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+
+				// the handler never runs, any call besides the permission lookup fails the test
+				store := mockdb.NewMockStore(ctrl)
+				stubUserRoles(store, user.NationalID, userRoles...)
+
+				server := newTestServer(t, store)
+				recorder := httptest.NewRecorder()
+
+				request, err := http.NewRequest(route.method, params.Replace(route.path), nil)
+				require.NoError(t, err)
+
+				addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+				server.router.ServeHTTP(recorder, request)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			})
+		}
+	}
+}
+
+func TestProtectedRoutesListed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+
+	registered := make(map[string]bool)
+	for _, route := range server.router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+
+	for _, route := range protectedRoutes {
+		require.True(t, registered[route.method+" "+route.path], "%s %s is not a route", route.method, route.path)
+	}
+}
+
+func TestResultsPermissionAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Voter",
+			method: http.MethodGet,
+			url:    "/election/result",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				stubUserRoles(store, user.NationalID)
+				store.EXPECT().
+					ListCandidatesResult(gomock.Any()).
+					Times(1).
+					Return([]db.ListCandidatesResultRow{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "NoAuthorization",
+			method: http.MethodGet,
+			url:    "/election/result",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the results are public, the dashboard shows them before login
+				store.EXPECT().
+					ListCandidatesResult(gomock.Any()).
+					Times(1).
+					Return([]db.ListCandidatesResultRow{}, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "OfficerExport",
+			method: http.MethodHead,
+			url:    "/election/export",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// the export of who voted is an audit record, officers conduct the election without it
+				stubUserRoles(store, user.NationalID, randomUserRole(user.NationalID, util.ElectionOfficerRole, 0))
+				store.EXPECT().
+					ListVoteOrderByCandidate(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NoAuthorizationExport",
+			method: http.MethodHead,
+			url:    "/election/export",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListVoteOrderByCandidate(gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func stubUserRoles(store *mockdb.MockStore, nationalID string, roles ...db.UserRole) {
+	store.EXPECT().
+		GetUserPermissions(gomock.Any(), gomock.Eq(nationalID)).
+		AnyTimes().
+		Return([]string{util.Vote}, nil)
+	store.EXPECT().
+		ListUserRoles(gomock.Any(), gomock.Eq(nationalID)).
+		AnyTimes().
+		Return(roles, nil)
+}
+
+func randomUserRole(nationalID, role string, electionID int64) db.UserRole {
+	return db.UserRole{
+		ID:         util.RandomInt(1, 1000),
+		NationalID: nationalID,
+		Role:       role,
+		ElectionID: sql.NullInt64{Int64: electionID, Valid: electionID != 0},
+		GrantedBy:  util.RandomString(13),
+		CreateAt:   time.Now(),
+	}
+}
@@ -2,8 +2,13 @@ package api
 
 import (
 	"fmt"
+	"io"
 
+	"election/blob"
 	db "election/db/sqlc"
+	"election/logging"
+	"election/mail"
+	"election/oidc"
 	"election/token"
 	"election/util"
 
@@ -14,10 +19,15 @@ import (
 )
 
 type Server struct {
-	store      db.Store
-	router     *gin.Engine
-	tokenMaker token.Maker
-	config     util.Config
+	store            db.Store
+	router           *gin.Engine
+	tokenMaker       token.Maker
+	images           blob.Store
+	mailer           mail.Mailer
+	identityProvider *oidc.Provider
+	metrics          *serverMetrics
+	logger           logging.Logger
+	config           util.Config
 }
 
 func NewServer(config util.Config, store db.Store) (*Server, error) {
@@ -27,10 +37,35 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	images, err := newImageStore(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create image store: %w", err)
+	}
+
+	mailer, err := newMailer(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mailer: %w", err)
+	}
+
+	identityProvider, err := newIdentityProvider(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create identity provider: %w", err)
+	}
+
+	logger, err := newLogger(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create logger: %w", err)
+	}
+
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:           config,
+		store:            store,
+		tokenMaker:       tokenMaker,
+		images:           images,
+		mailer:           mailer,
+		identityProvider: identityProvider,
+		metrics:          newServerMetrics(store),
+		logger:           logger,
 	}
 
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -43,26 +78,110 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 }
 
 func (server *Server) setupRouter() {
-	router := gin.Default()
-	router.Use(cors.AllowAll())
-
+	router := gin.New()
+	// store calls receive the gin context, the fallback lets them read the request ID
+	router.ContextWithFallback = true
+	router.Use(
+		server.requestID(),
+		server.logRequest(),
+		server.metrics.middleware(),
+		gin.CustomRecoveryWithWriter(io.Discard, server.recoverPanic),
+		cors.AllowAll(),
+	)
+
+	router.GET("/metrics", server.serveMetrics)
 	router.POST("/users", server.createUser)
-	router.POST("/users/login", server.loginUser)
+	router.POST("/users/login", server.metrics.countLoginFailures(passwordLogin), server.loginUser)
+	router.POST("/users/login/2fa", server.metrics.countLoginFailures(twoFactorLogin), server.loginTwoFactor)
+	if server.identityProvider != nil {
+		router.POST("/users/login/oidc", server.startOIDCLogin)
+		router.POST("/users/login/oidc/callback", server.metrics.countLoginFailures(oidcLogin), server.loginOIDC)
+	}
+	router.POST("/users/password/forgot", server.forgotPassword)
+	router.POST("/users/password/reset", server.resetPassword)
+	router.POST("/users/verify", server.verifyEmail)
 	router.GET("/election/result", server.electionResult)
-	router.HEAD("/election/export", server.exportCSVElectionResult)
-
-	authRoutes := router.Group("/api").Use(authMiddleware(server.tokenMaker))
-
-	authRoutes.POST("/candidates", server.createCandidate)
+	router.GET("/election/result/districts", server.districtsResult)
+	router.GET("/election/result/districts/:id", server.districtResult)
+	router.GET("/election/result/measures", server.measuresResult)
+	router.GET("/elections/:id/outcome", server.electionOutcome)
+	router.GET("/elections/:id/parties", server.electionPartiesResult)
+	router.GET("/elections/:id/votes", server.listElectionVotes)
+	router.GET("/vote/receipt/:code", server.getVoteReceipt)
+	router.GET("/images/:name", server.getImage)
+
+	// the export lists who voted, it is an audit record rather than a public result
+	auditRoutes := router.Group("/").Use(authMiddleware(server.tokenMaker), sessionMiddleware(server.store))
+	auditRoutes.HEAD("/election/export", server.requirePermission(util.ViewAudit), server.exportCSVElectionResult)
+
+	authRoutes := router.Group("/api").Use(authMiddleware(server.tokenMaker), sessionMiddleware(server.store))
+
+	authRoutes.POST("/candidates", server.requirePermission(util.ManageCandidates), server.createCandidate)
 	authRoutes.GET("/candidates/:id", server.getCandidate)
 	authRoutes.GET("/candidates", server.listCandidates)
-	authRoutes.PUT("/candidates", server.updateCandidate)
-	authRoutes.DELETE("/candidates/:id", server.deleteCandidate)
-
+	authRoutes.PUT("/candidates", server.requirePermission(util.ManageCandidates), server.updateCandidate)
+	authRoutes.DELETE("/candidates/:id", server.requirePermission(util.ManageCandidates), server.withdrawCandidate)
+	authRoutes.POST("/candidates/:id/restore", server.requirePermission(util.ManageCandidates), server.restoreCandidate)
+	authRoutes.POST("/candidates/:id/image", server.requirePermission(util.ManageCandidates), server.uploadCandidateImage)
+	// candidate managers read the revisions they may roll back to
+	authRoutes.GET("/candidates/:id/revisions", server.requireAnyPermission(util.ViewAudit, util.ManageCandidates), server.listCandidateRevisions)
+	authRoutes.GET("/candidates/:id/revisions/diff", server.requireAnyPermission(util.ViewAudit, util.ManageCandidates), server.diffCandidateRevisions)
+	authRoutes.POST("/candidates/:id/revisions/:version/rollback", server.requirePermission(util.ManageCandidates), server.rollbackCandidate)
+
+	authRoutes.POST("/districts", server.requirePermission(util.ManageVoters), server.createDistrict)
+	authRoutes.GET("/districts", server.listDistricts)
+	authRoutes.POST("/districts/roll", server.requirePermission(util.ManageVoters), server.importVoterRoll)
+
+	authRoutes.GET("/users/me", server.getCurrentUser)
+	authRoutes.PATCH("/users/me", server.updateCurrentUser)
+	authRoutes.PUT("/users/me/password", server.changePassword)
+	authRoutes.POST("/users/me/verification", server.resendVerificationEmail)
+	authRoutes.POST("/users/me/2fa/setup", server.setupTwoFactor)
+	authRoutes.POST("/users/me/2fa/enable", server.enableTwoFactor)
+	authRoutes.POST("/users/me/2fa/disable", server.disableTwoFactor)
+	authRoutes.DELETE("/users/me", server.deleteCurrentUser)
+	authRoutes.PUT("/users/weight", server.requirePermission(util.ManageVoters), server.updateVoterWeight)
+	authRoutes.POST("/users/weights", server.requirePermission(util.ManageVoters), server.importVoterWeights)
+
+	authRoutes.POST("/parties", server.requirePermission(util.ConductElection), server.createParty)
+	authRoutes.GET("/parties", server.listParties)
+	authRoutes.PUT("/parties/:id", server.requirePermission(util.ConductElection), server.updateParty)
+
+	authRoutes.POST("/measures", server.requirePermission(util.ConductElection), server.createBallotMeasure)
+	authRoutes.GET("/measures", server.listBallotMeasures)
+
+	authRoutes.POST("/delegations", server.createDelegation)
+	authRoutes.GET("/delegations", server.requirePermission(util.ManageVoters), server.listDelegations)
+	authRoutes.POST("/delegations/:id/approve", server.requirePermission(util.ManageVoters), server.approveDelegation)
+	authRoutes.POST("/delegations/:id/revoke", server.revokeDelegation)
+
+	authRoutes.POST("/ballot", server.submitBallot)
 	authRoutes.POST("/vote", server.voteCandidate)
+	authRoutes.POST("/vote/party", server.voteParty)
 	authRoutes.POST("/vote/status", server.checkVoteStatus)
 
-	authRoutes.POST("/election/toggle", server.toggleElection)
+	authRoutes.GET("/elections/:id", server.getElection)
+	authRoutes.GET("/elections/:id/contests", server.listContests)
+	authRoutes.POST("/elections/:id/contests", server.requireElectionPermission(util.ConductElection), server.createContest)
+	authRoutes.PUT("/elections/:id/rules", server.requireElectionPermission(util.ConductElection), server.updateElectionRules)
+	authRoutes.POST("/elections/:id/runoff", server.requireElectionPermission(util.ConductElection), server.createRunoff)
+
+	authRoutes.POST("/election/toggle", server.requirePermission(util.ConductElection), server.toggleElection)
+	authRoutes.POST("/election/revote", server.requirePermission(util.ConductElection), server.toggleRevote)
+	authRoutes.POST("/election/withdrawal", server.requirePermission(util.ConductElection), server.toggleWithdrawAfterVotes)
+	authRoutes.POST("/election/verification", server.requirePermission(util.ConductElection), server.toggleVerifiedVoting)
+	authRoutes.POST("/election/manager-2fa", server.requirePermission(util.ManageUsers), server.toggleManagerTwoFactor)
+
+	authRoutes.GET("/admin/users", server.requirePermission(util.ViewUsers), server.listUsers)
+	authRoutes.POST("/admin/users/:national_id/permissions/grant", server.requirePermission(util.ManageUsers), server.grantPermission)
+	authRoutes.POST("/admin/users/:national_id/permissions/revoke", server.requirePermission(util.ManageUsers), server.revokePermission)
+	authRoutes.POST("/admin/users/:national_id/disable", server.requirePermission(util.ManageUsers), server.disableUser)
+	authRoutes.POST("/admin/users/:national_id/enable", server.requirePermission(util.ManageUsers), server.enableUser)
+	authRoutes.POST("/admin/users/:national_id/reset-vote", server.requirePermission(util.ManageUsers), server.resetVoterStatus)
+	authRoutes.GET("/admin/users/:national_id/roles", server.requirePermission(util.ViewUsers), server.listUserRoles)
+	authRoutes.POST("/admin/users/:national_id/roles/assign", server.requirePermission(util.ManageUsers), server.assignRole)
+	authRoutes.POST("/admin/users/:national_id/roles/unassign", server.requirePermission(util.ManageUsers), server.unassignRole)
+	authRoutes.GET("/admin/audits", server.requirePermission(util.ViewAudit), server.listUserAudits)
 
 	server.router = router
 }
@@ -77,9 +196,13 @@ func successResponse() gin.H {
 	}
 }
 
-func errorResponse(err error) gin.H {
+// errorResponse records the error for the request log and returns the response body, which holds
+// the request ID to quote when reporting the error
+func errorResponse(ctx *gin.Context, err error) gin.H {
+	ctx.Error(err)
 	return gin.H{
-		"status": "error",
-		"error":  err.Error(),
+		"status":     "error",
+		"error":      err.Error(),
+		"request_id": ctx.GetString(requestIDKey),
 	}
 }
@@ -0,0 +1,364 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	twoFactorIssuer            = "Election"
+	twoFactorChallengeDuration = 5 * time.Minute
+	maxTwoFactorAttempts       = 5
+	recoveryCodeCount          = 10
+)
+
+var (
+	ErrTwoFactorEnabled     = errors.New("Two-factor authentication is already enabled")
+	ErrTwoFactorNotSetUp    = errors.New("Two-factor authentication is not set up")
+	ErrTwoFactorNotEnabled  = errors.New("Two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode = errors.New("Invalid two-factor code")
+	ErrInvalidChallenge     = errors.New("Login challenge is invalid or expired")
+)
+
+// verifyTwoFactorCode checks an authenticator code or, once two-factor authentication is enabled,
+// a recovery code. A code is accepted only once, a recovery code is used up and an authenticator
+// code is refused when its time step is not after the last accepted one.
+func (server *Server) verifyTwoFactorCode(ctx *gin.Context, twoFactor db.TwoFactor, code string) (bool, error) {
+	if step, ok := util.ValidateTOTP(twoFactor.Secret, code, time.Now()); ok {
+		used, err := server.store.UseTwoFactorStep(ctx, db.UseTwoFactorStepParams{
+			NationalID:   twoFactor.NationalID,
+			LastUsedStep: step,
+		})
+		return used == 1, err
+	}
+
+	if !twoFactor.EnabledAt.Valid {
+		return false, nil
+	}
+
+	_, err := server.store.UseRecoveryCode(ctx, db.UseRecoveryCodeParams{
+		NationalID: twoFactor.NationalID,
+		CodeHash:   util.HashRecoveryCode(code),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// twoFactorSatisfied reports whether the session may use the election management permission,
+// while the election requires it for managers the session must have been signed in with a second
+// factor of an authenticator which is still enabled
+func (server *Server) twoFactorSatisfied(ctx *gin.Context, authPayload *token.Payload) (bool, error) {
+	required, err := server.store.GetElectionProperty(ctx, util.RequireManagerTwoFactor)
+	if err != nil {
+		return false, err
+	}
+	if !required.Value {
+		return true, nil
+	}
+
+	// enrolling does not upgrade a session signed in with the password alone
+	if !authPayload.TwoFactorVerified {
+		return false, nil
+	}
+
+	twoFactor, err := server.store.GetTwoFactor(ctx, authPayload.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, nil
+		}
+		return false, err
+	}
+	return twoFactor.EnabledAt.Valid, nil
+}
+
+type setupTwoFactorRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+type setupTwoFactorResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// setupTwoFactor generates a new authenticator secret, it stays pending until enableTwoFactor
+// confirms a code of the authenticator the secret was enrolled in
+func (server *Server) setupTwoFactor(ctx *gin.Context) {
+	var req setupTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+		return
+	}
+
+	secret, err := util.NewTOTPSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	twoFactor, err := server.store.SetupTwoFactor(ctx, db.SetupTwoFactorParams{
+		NationalID: user.NationalID,
+		Secret:     secret,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrTwoFactorEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, setupTwoFactorResponse{
+		Secret:          twoFactor.Secret,
+		ProvisioningURI: util.TOTPProvisioningURI(twoFactorIssuer, user.Email, twoFactor.Secret),
+	})
+}
+
+type enableTwoFactorRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type enableTwoFactorResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// enableTwoFactor turns on the pending authenticator, the recovery codes are only shown in this response
+func (server *Server) enableTwoFactor(ctx *gin.Context) {
+	var req enableTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	twoFactor, err := server.store.GetTwoFactor(ctx, user.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrTwoFactorNotSetUp))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if twoFactor.EnabledAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrTwoFactorEnabled))
+		return
+	}
+
+	valid, err := server.verifyTwoFactorCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if !valid {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidTwoFactorCode))
+		return
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		codes[i], err = util.NewRecoveryCode()
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		hashes[i] = util.HashRecoveryCode(codes[i])
+	}
+
+	_, err = server.store.EnableTwoFactorTx(ctx, db.EnableTwoFactorTxParams{
+		NationalID:         user.NationalID,
+		RecoveryCodeHashes: hashes,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrTwoFactorEnabled))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, enableTwoFactorResponse{RecoveryCodes: codes})
+}
+
+type disableTwoFactorRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	Code            string `json:"code" binding:"required"`
+}
+
+func (server *Server) disableTwoFactor(ctx *gin.Context) {
+	var req disableTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+		return
+	}
+
+	twoFactor, err := server.store.GetTwoFactor(ctx, user.NationalID)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err == sql.ErrNoRows || !twoFactor.EnabledAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrTwoFactorNotEnabled))
+		return
+	}
+
+	valid, err := server.verifyTwoFactorCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if !valid {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidTwoFactorCode))
+		return
+	}
+
+	if err := server.store.DisableTwoFactorTx(ctx, user.NationalID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
+
+type twoFactorChallengeResponse struct {
+	TwoFactorRequired bool      `json:"two_factor_required"`
+	ChallengeToken    string    `json:"challenge_token"`
+	ExpiredAt         time.Time `json:"expired_at"`
+}
+
+// startTwoFactorLogin answers a correct password of an account with two-factor authentication with
+// a short lived challenge, the access token is only issued by loginTwoFactor
+func (server *Server) startTwoFactorLogin(ctx *gin.Context, user db.User) {
+	challengeToken, err := util.NewSecretToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	challenge, err := server.store.CreateTwoFactorChallenge(ctx, db.CreateTwoFactorChallengeParams{
+		NationalID: user.NationalID,
+		TokenHash:  util.HashSecretToken(challengeToken),
+		ExpiredAt:  time.Now().Add(twoFactorChallengeDuration),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, twoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+		ExpiredAt:         challenge.ExpiredAt,
+	})
+}
+
+type loginTwoFactorRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required"`
+}
+
+// loginTwoFactor is the second login step, a challenge allows a few attempts before it is refused
+func (server *Server) loginTwoFactor(ctx *gin.Context) {
+	var req loginTwoFactorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	challenge, err := server.store.AttemptTwoFactorChallenge(ctx, util.HashSecretToken(req.ChallengeToken))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidChallenge))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if challenge.Attempts > maxTwoFactorAttempts {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidChallenge))
+		return
+	}
+
+	twoFactor, err := server.store.GetTwoFactor(ctx, challenge.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidChallenge))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	valid, err := server.verifyTwoFactorCode(ctx, twoFactor, req.Code)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if !valid {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, ErrInvalidTwoFactorCode))
+		return
+	}
+
+	if err := server.store.UseTwoFactorChallenge(ctx, challenge.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, challenge.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if user.DisabledAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrAccountDisabled))
+		return
+	}
+
+	server.writeAccessToken(ctx, user, true)
+}
@@ -0,0 +1,684 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	mockdb "election/db/mock"
+	db "election/db/sqlc"
+	"election/token"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func randomTwoFactor(t *testing.T, nationalID string, enabled bool) db.TwoFactor {
+	secret, err := util.NewTOTPSecret()
+	require.NoError(t, err)
+
+	twoFactor := db.TwoFactor{
+		NationalID: nationalID,
+		Secret:     secret,
+		CreateAt:   time.Now(),
+	}
+	if enabled {
+		twoFactor.EnabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+	}
+	return twoFactor
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	code, err := util.TOTPCode(secret, util.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func serveTwoFactorRequest(t *testing.T, store *mockdb.MockStore, path, nationalID string, body gin.H) *httptest.ResponseRecorder {
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+	require.NoError(t, err)
+
+	if nationalID != "" {
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, nationalID, time.Minute)
+	}
+	server.router.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestSetupTwoFactorAPI(t *testing.T) {
+	user, password := CreateRandomUser(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"current_password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					SetupTwoFactor(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.SetupTwoFactorParams) (db.TwoFactor, error) {
+						require.Equal(t, user.NationalID, arg.NationalID)
+						require.NotEmpty(t, arg.Secret)
+						return db.TwoFactor{NationalID: arg.NationalID, Secret: arg.Secret}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp setupTwoFactorResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.Secret)
+
+				uri, err := url.Parse(rsp.ProvisioningURI)
+				require.NoError(t, err)
+				require.Equal(t, "otpauth", uri.Scheme)
+				require.Equal(t, "totp", uri.Host)
+				require.Equal(t, rsp.Secret, uri.Query().Get("secret"))
+				require.Equal(t, twoFactorIssuer, uri.Query().Get("issuer"))
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			body: gin.H{"current_password": "incorrect"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					SetupTwoFactor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			body: gin.H{"current_password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					SetupTwoFactor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"current_password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					SetupTwoFactor(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "MissingPassword",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetupTwoFactor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			recorder := serveTwoFactorRequest(t, store, "/api/users/me/2fa/setup", user.NationalID, tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestEnableTwoFactorAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	pending := randomTwoFactor(t, user.NationalID, false)
+	enabled := randomTwoFactor(t, user.NationalID, true)
+
+	testCases := []struct {
+		name          string
+		code          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: currentTOTPCode(t, pending.Secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(pending, nil)
+				store.EXPECT().
+					UseTwoFactorStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					EnableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.EnableTwoFactorTxParams) (db.EnableTwoFactorTxResult, error) {
+						require.Equal(t, user.NationalID, arg.NationalID)
+						require.Len(t, arg.RecoveryCodeHashes, recoveryCodeCount)
+						return db.EnableTwoFactorTxResult{TwoFactor: enabled}, nil
+					})
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp enableTwoFactorResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Len(t, rsp.RecoveryCodes, recoveryCodeCount)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: "abcdef",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(pending, nil)
+				store.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					EnableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedCode",
+			code: currentTOTPCode(t, pending.Secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(pending, nil)
+				store.EXPECT().
+					UseTwoFactorStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					EnableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotSetUp",
+			code: "123456",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrNoRows)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "AlreadyEnabled",
+			code: currentTOTPCode(t, enabled.Secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().
+					EnableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			code: currentTOTPCode(t, pending.Secret),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.TwoFactor{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			recorder := serveTwoFactorRequest(t, store, "/api/users/me/2fa/enable", user.NationalID, gin.H{"code": tc.code})
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestDisableTwoFactorAPI(t *testing.T) {
+	user, password := CreateRandomUser(t)
+	enabled := randomTwoFactor(t, user.NationalID, true)
+	recoveryCode, err := util.NewRecoveryCode()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"current_password": password, "code": currentTOTPCode(t, enabled.Secret)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().
+					UseTwoFactorStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					DisableTwoFactorTx(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "RecoveryCode",
+			body: gin.H{"current_password": password, "code": recoveryCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				arg := db.UseRecoveryCodeParams{
+					NationalID: user.NationalID,
+					CodeHash:   util.HashRecoveryCode(recoveryCode),
+				}
+				store.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.RecoveryCode{NationalID: user.NationalID, CodeHash: arg.CodeHash}, nil)
+				store.EXPECT().
+					DisableTwoFactorTx(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UsedRecoveryCode",
+			body: gin.H{"current_password": password, "code": recoveryCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RecoveryCode{}, sql.ErrNoRows)
+				store.EXPECT().
+					DisableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "IncorrectPassword",
+			body: gin.H{"current_password": "incorrect", "code": recoveryCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotEnabled",
+			body: gin.H{"current_password": password, "code": recoveryCode},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(randomTwoFactor(t, user.NationalID, false), nil)
+				store.EXPECT().
+					DisableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"current_password": password, "code": currentTOTPCode(t, enabled.Secret)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().
+					UseTwoFactorStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+				store.EXPECT().
+					DisableTwoFactorTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			recorder := serveTwoFactorRequest(t, store, "/api/users/me/2fa/disable", user.NationalID, tc.body)
+			tc.checkResponse(recorder)
+		})
+	}
+}
+
+func TestLoginTwoFactorAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	enabled := randomTwoFactor(t, user.NationalID, true)
+	challengeToken, err := util.NewSecretToken()
+	require.NoError(t, err)
+
+	challenge := db.TwoFactorChallenge{
+		ID:         util.RandomInt(1, 1000),
+		NationalID: user.NationalID,
+		TokenHash:  util.HashSecretToken(challengeToken),
+		Attempts:   1,
+		ExpiredAt:  time.Now().Add(twoFactorChallengeDuration),
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, enabled.Secret)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AttemptTwoFactorChallenge(gomock.Any(), gomock.Eq(challenge.TokenHash)).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().
+					UseTwoFactorStep(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.UseTwoFactorStepParams) (int64, error) {
+						require.Equal(t, user.NationalID, arg.NationalID)
+						require.InDelta(t, util.TOTPStep(time.Now()), arg.LastUsedStep, 1)
+						return 1, nil
+					})
+				store.EXPECT().
+					UseTwoFactorChallenge(gomock.Any(), gomock.Eq(challenge.ID)).
+					Times(1).
+					Return(nil)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp loginUserResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.NotEmpty(t, rsp.AccessToken)
+				require.Equal(t, user.NationalID, rsp.User.NationalID)
+
+				payload, err := tokenMaker.VerifyToken(rsp.AccessToken)
+				require.NoError(t, err)
+				require.True(t, payload.TwoFactorVerified)
+			},
+		},
+		{
+			name: "InvalidChallenge",
+			body: gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, enabled.Secret)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AttemptTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactorChallenge{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "TooManyAttempts",
+			body: gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, enabled.Secret)},
+			buildStubs: func(store *mockdb.MockStore) {
+				exhausted := challenge
+				exhausted.Attempts = maxTwoFactorAttempts + 1
+				store.EXPECT().
+					AttemptTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(exhausted, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			body: gin.H{"challenge_token": challengeToken, "code": "ABCDE-FGHJK"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AttemptTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(challenge, nil)
+				store.EXPECT().
+					GetTwoFactor(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(enabled, nil)
+				store.EXPECT().
+					UseRecoveryCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RecoveryCode{}, sql.ErrNoRows)
+				store.EXPECT().
+					UseTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingCode",
+			body: gin.H{"challenge_token": challengeToken},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AttemptTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"challenge_token": challengeToken, "code": currentTOTPCode(t, enabled.Secret)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AttemptTwoFactorChallenge(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.TwoFactorChallenge{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder, tokenMaker token.Maker) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/login/2fa", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(recorder, server.tokenMaker)
+		})
+	}
+}
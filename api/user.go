@@ -2,21 +2,30 @@ package api
 
 import (
 	"database/sql"
+	"errors"
 	"net/http"
 	"time"
 
 	db "election/db/sqlc"
+	"election/token"
 	"election/util"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lib/pq"
 )
 
+var (
+	ErrNoProfileChange = errors.New("Nothing to update")
+	ErrUserHasVoted    = errors.New("An account that has voted cannot be deleted")
+	ErrAccountInUse    = errors.New("Account is referenced by election records and cannot be deleted")
+)
+
 type createUserRequest struct {
 	NationalID string `json:"national_id" binding:"required,number,len=13"`
 	Password   string `json:"password" binding:"required,min=6"`
 	Fullname   string `json:"full_name" binding:"required"`
 	Email      string `json:"email" binding:"required,email"`
+	DistrictID int64  `json:"district_id" binding:"omitempty,min=1"`
 }
 
 type createUserResponse struct {
@@ -24,9 +33,10 @@ type createUserResponse struct {
 	FullName          string    `json:"full_name"`
 	Email             string    `json:"email"`
 	Permission        []string  `json:"permission"`
-	HasVoted          bool      `json:"has_voted"`
+	DistrictID        int64     `json:"district_id,omitempty"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreateAt          time.Time `json:"create_at"`
+	VerificationSent  bool      `json:"verification_sent"`
 }
 
 type userResponse struct {
@@ -50,13 +60,13 @@ func newUserResponse(user db.User) userResponse {
 func (server Server) createUser(ctx *gin.Context) {
 	var req createUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	hashedPassword, err := util.HashPassword(req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -69,7 +79,7 @@ func (server Server) createUser(ctx *gin.Context) {
 		FullName:       req.Fullname,
 		Email:          req.Email,
 		Permission:     permission,
-		HasVoted:       false,
+		DistrictID:     nullDistrictID(req.DistrictID),
 	}
 
 	user, err := server.store.CreateUser(ctx, arg)
@@ -77,22 +87,33 @@ func (server Server) createUser(ctx *gin.Context) {
 		if pqError, ok := err.(*pq.Error); ok {
 			switch pqError.Code.Name() {
 			case "unique_violation":
-				ctx.JSON(http.StatusForbidden, errorResponse(err))
+				ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+				return
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 				return
 			}
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	// the account exists even when the verification email cannot be sent, the user can ask for a resend
+	verificationSent := true
+	if err := server.sendVerificationEmail(ctx, user); err != nil {
+		ctx.Error(err)
+		verificationSent = false
+	}
+
 	response := createUserResponse{
 		NationalID:        user.NationalID,
 		FullName:          user.FullName,
 		Email:             user.Email,
 		Permission:        user.Permission,
-		HasVoted:          user.HasVoted,
+		DistrictID:        user.DistrictID.Int64,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreateAt:          user.CreateAt,
+		VerificationSent:  verificationSent,
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -112,33 +133,61 @@ type loginUserResponse struct {
 func (server *Server) loginUser(ctx *gin.Context) {
 	var req loginUserRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.NationalID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
 	err = util.CheckPassword(req.Password, user.HashedPassword)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+		return
+	}
+
+	server.completeLogin(ctx, user)
+}
+
+// completeLogin signs in an authenticated user, a disabled account is refused and a user with an
+// authenticator is asked for the second factor before an access token is issued
+func (server *Server) completeLogin(ctx *gin.Context, user db.User) {
+	if user.DisabledAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrAccountDisabled))
+		return
+	}
+
+	twoFactor, err := server.store.GetTwoFactor(ctx, user.NationalID)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err == nil && twoFactor.EnabledAt.Valid {
+		server.startTwoFactorLogin(ctx, user)
 		return
 	}
 
+	server.writeAccessToken(ctx, user, false)
+}
+
+// writeAccessToken issues a new access token of the user as the response, twoFactorVerified marks a
+// session which passed a second factor
+func (server *Server) writeAccessToken(ctx *gin.Context, user db.User, twoFactorVerified bool) {
 	accessToken, accessPayload, err := server.tokenMaker.CreateToken(
 		user.NationalID,
+		twoFactorVerified,
 		server.config.AccessTokenDuration,
 	)
 
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
@@ -149,3 +198,213 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
+
+type userProfileResponse struct {
+	NationalID        string     `json:"national_id"`
+	FullName          string     `json:"full_name"`
+	Email             string     `json:"email"`
+	Verified          bool       `json:"verified"`
+	VerifiedAt        *time.Time `json:"verified_at,omitempty"`
+	Permission        []string   `json:"permission"`
+	DistrictID        int64      `json:"district_id,omitempty"`
+	Disabled          bool       `json:"disabled"`
+	DisabledAt        *time.Time `json:"disabled_at,omitempty"`
+	PasswordChangedAt time.Time  `json:"password_changed_at"`
+	CreateAt          time.Time  `json:"create_at"`
+}
+
+func newUserProfileResponse(user db.User) userProfileResponse {
+	return userProfileResponse{
+		NationalID:        user.NationalID,
+		FullName:          user.FullName,
+		Email:             user.Email,
+		Verified:          user.VerifiedAt.Valid,
+		VerifiedAt:        nullTimePtr(user.VerifiedAt),
+		Permission:        user.Permission,
+		DistrictID:        user.DistrictID.Int64,
+		Disabled:          user.DisabledAt.Valid,
+		DisabledAt:        nullTimePtr(user.DisabledAt),
+		PasswordChangedAt: user.PasswordChangedAt,
+		CreateAt:          user.CreateAt,
+	}
+}
+
+// currentUser loads the user of the access token, the response is written when it fails
+func (server *Server) currentUser(ctx *gin.Context) (db.User, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.GetUser(ctx, authPayload.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return user, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return user, false
+	}
+
+	return user, true
+}
+
+func (server *Server) getCurrentUser(ctx *gin.Context) {
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserProfileResponse(user))
+}
+
+type updateCurrentUserRequest struct {
+	FullName        string `json:"full_name"`
+	Email           string `json:"email" binding:"omitempty,email"`
+	CurrentPassword string `json:"current_password" binding:"required_with=Email"`
+}
+
+// updateCurrentUser changes the name or email of the account, changing the email requires the
+// current password and the new email is unverified until the emailed link is confirmed
+func (server *Server) updateCurrentUser(ctx *gin.Context) {
+	var req updateCurrentUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	if req.FullName == "" && req.Email == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrNoProfileChange))
+		return
+	}
+
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	arg := db.UpdateUserProfileParams{
+		NationalID: user.NationalID,
+		FullName:   user.FullName,
+		Email:      user.Email,
+	}
+	if req.FullName != "" {
+		arg.FullName = req.FullName
+	}
+	if req.Email != "" && req.Email != user.Email {
+		if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+			return
+		}
+		arg.Email = req.Email
+	}
+
+	emailChanged := arg.Email != user.Email
+
+	user, err := server.store.UpdateUserProfile(ctx, arg)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(ctx, err))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	if emailChanged {
+		// the new email is saved even when the verification email cannot be sent, the user can ask for a resend
+		if err := server.sendVerificationEmail(ctx, user); err != nil {
+			ctx.Error(err)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, newUserProfileResponse(user))
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// changePassword replaces the password of the account, every token issued before the change
+// is rejected from then on so a new access token is returned
+func (server *Server) changePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	user, err = server.store.UpdateUserPassword(ctx, db.UpdateUserPasswordParams{
+		NationalID:        user.NationalID,
+		HashedPassword:    hashedPassword,
+		PasswordChangedAt: time.Now(),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	// the new token keeps the second factor of the session the password was changed in
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	server.writeAccessToken(ctx, user, authPayload.TwoFactorVerified)
+}
+
+type deleteCurrentUserRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+// deleteCurrentUser deletes the account and its delegations, an account that voted, directly or
+// as a proxy, is kept because its ballots are part of the election record
+func (server *Server) deleteCurrentUser(ctx *gin.Context) {
+	var req deleteCurrentUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusUnauthorized, errorResponse(ctx, err))
+		return
+	}
+
+	err := server.store.DeleteUserTx(ctx, user.NationalID)
+	if err != nil {
+		if err == db.ErrUserHasVoted {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrUserHasVoted))
+			return
+		}
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "foreign_key_violation":
+				ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrAccountInUse))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
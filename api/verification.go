@@ -0,0 +1,122 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"time"
+
+	db "election/db/sqlc"
+	"election/mail"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("Verification token is invalid or expired")
+	ErrEmailAlreadyVerified     = errors.New("Email is already verified")
+	ErrVerificationThrottled    = errors.New("A verification email was sent recently, try again later")
+	ErrUnverifiedEmail          = errors.New("Email must be verified before voting")
+)
+
+type emailVerificationEmail struct {
+	Name      string
+	Email     string
+	Link      string
+	ExpiresIn time.Duration
+}
+
+// sendVerificationEmail emails a link confirming the current email of the user
+func (server *Server) sendVerificationEmail(ctx *gin.Context, user db.User) error {
+	verificationToken, err := util.NewSecretToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = server.store.CreateEmailVerification(ctx, db.CreateEmailVerificationParams{
+		NationalID: user.NationalID,
+		Email:      user.Email,
+		TokenHash:  util.HashSecretToken(verificationToken),
+		ExpiredAt:  time.Now().Add(server.config.EmailVerificationDuration),
+	})
+	if err != nil {
+		return err
+	}
+
+	msg, err := mail.Render(mail.EmailVerificationTemplate, user.Email, emailVerificationEmail{
+		Name:      user.FullName,
+		Email:     user.Email,
+		Link:      server.config.AppBaseURL + "/verify-email?token=" + url.QueryEscape(verificationToken),
+		ExpiresIn: server.config.EmailVerificationDuration,
+	})
+	if err != nil {
+		return err
+	}
+
+	return server.mailer.Send(ctx, msg)
+}
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+func (server *Server) verifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	result, err := server.store.VerifyEmailTx(ctx, db.VerifyEmailTxParams{
+		TokenHash: util.HashSecretToken(req.Token),
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrInvalidVerificationToken))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newUserProfileResponse(result.User))
+}
+
+// resendVerificationEmail sends a new verification link to the current email of the account,
+// at most one email is sent per configured interval
+func (server *Server) resendVerificationEmail(ctx *gin.Context) {
+	user, ok := server.currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if user.VerifiedAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrEmailAlreadyVerified))
+		return
+	}
+
+	latest, err := server.store.GetLatestEmailVerification(ctx, user.NationalID)
+	if err != nil && err != sql.ErrNoRows {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+	if err == nil {
+		wait := time.Until(latest.CreateAt.Add(server.config.EmailVerificationInterval))
+		if wait > 0 {
+			ctx.Header("Retry-After", fmt.Sprint(int64(math.Ceil(wait.Seconds()))))
+			ctx.JSON(http.StatusTooManyRequests, errorResponse(ctx, ErrVerificationThrottled))
+			return
+		}
+	}
+
+	if err := server.sendVerificationEmail(ctx, user); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse())
+}
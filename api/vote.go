@@ -4,42 +4,68 @@ import (
 	"database/sql"
 	"errors"
 	"net/http"
+	"time"
 
 	db "election/db/sqlc"
 	"election/token"
 	"election/util"
 
 	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
 )
 
 var (
 	ErrAlreadyVoted           = errors.New("Already voted")
 	ErrClosedElection         = errors.New("Election is closed")
 	ErrNoPermissionNationalID = errors.New("Cannot vote by another natinal ID")
+	ErrReceiptNotFound        = errors.New("Receipt not found")
+	ErrCandidateNotInDistrict = errors.New("Candidate is not running in voter's district")
 )
 
+// generalElectionID is the election created with the schema, records made before there were
+// several elections belong to it
+const generalElectionID = 1
+
 type checkVoteStatusRequest struct {
 	NationalId string `json:"nationalId" binding:"required,number,len=13"`
+	ElectionId int64  `json:"electionId" binding:"omitempty,min=1"`
 }
 
+// checkVoteStatus reports whether the voter has voted in the election, the general
+// election when none is given
+
 func (server Server) checkVoteStatus(ctx *gin.Context) {
 	var req checkVoteStatusRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.NationalId)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	electionID := req.ElectionId
+	if electionID == 0 {
+		electionID = generalElectionID
+	}
+
+	voted, err := server.store.HasElectionVoterVoted(ctx, db.HasElectionVoterVotedParams{
+		ElectionID: electionID,
+		NationalID: user.NationalID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"status": user.HasVoted})
+	ctx.JSON(http.StatusOK, gin.H{"status": voted})
 }
 
 type voteCandidateRequest struct {
@@ -50,53 +76,305 @@ type voteCandidateRequest struct {
 func (server Server) voteCandidate(ctx *gin.Context) {
 	var req voteCandidateRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
 	user, err := server.store.GetUser(ctx, req.NationalId)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
 			return
 		}
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	candidate, valid := server.validCandidateVote(ctx, user, req.CandidateId)
+	if !valid {
+		return
+	}
 
-	if user.NationalID != authPayload.NationalID {
-		ctx.JSON(http.StatusBadRequest, errorResponse(ErrNoPermissionNationalID))
+	if !server.validSingleContestVote(ctx, candidate.ElectionID) {
 		return
 	}
 
-	if user.HasVoted {
-		ctx.JSON(http.StatusBadRequest, errorResponse(ErrAlreadyVoted))
+	castBy, valid := server.validBallotCaster(ctx, user, candidate.ElectionID)
+	if !valid {
 		return
 	}
 
-	isClosedElection, err := server.store.GetElectionProperty(ctx, util.ElectionClosed)
+	receiptCode, err := util.NewReceiptCode()
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
+	arg := db.CreateVoteParams{
+		VoteNationalID:   req.NationalId,
+		CandidateID:      req.CandidateId,
+		ReceiptHash:      util.HashReceiptCode(receiptCode),
+		CastByNationalID: castBy,
+	}
+
+	_, err = server.store.CreateVote(ctx, arg)
+	if err != nil {
+		if pqError, ok := err.(*pq.Error); ok {
+			switch pqError.Code.Name() {
+			case "unique_violation":
+				ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrAlreadyVoted))
+				return
+			}
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	server.metrics.votesCast.Inc(candidateVote)
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":  "ok",
+		"receipt": receiptCode,
+	})
+}
+
+// validCandidateVote checks that the voter may vote for the candidate: the election is open
+// and the candidate runs in the voter's district of an open candidate election. Whether the
+// voter has voted in that election already is checked by the database with the ballot.
+func (server Server) validCandidateVote(ctx *gin.Context, user db.User, candidateID int64) (db.GetCandidateRow, bool) {
+	isClosedElection, err := server.store.GetElectionProperty(ctx, util.ElectionClosed)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.GetCandidateRow{}, false
+	}
+
 	if isClosedElection.Value {
-		ctx.JSON(http.StatusBadRequest, errorResponse(ErrClosedElection))
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return db.GetCandidateRow{}, false
+	}
+
+	candidate, err := server.store.GetCandidate(ctx, candidateID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.GetCandidateRow{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.GetCandidateRow{}, false
+	}
+
+	if candidate.ElectionClosed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return db.GetCandidateRow{}, false
+	}
+
+	if candidate.WithdrawnAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateWithdrawn))
+		return db.GetCandidateRow{}, false
+	}
+
+	if candidate.ElectionBallotType == util.BallotTypePartyList {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrPartyListElection))
+		return db.GetCandidateRow{}, false
+	}
+
+	if candidate.DistrictID.Valid && candidate.DistrictID != user.DistrictID {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrCandidateNotInDistrict))
+		return db.GetCandidateRow{}, false
+	}
+
+	return candidate, true
+}
+
+// validBallotCaster checks that the authenticated user may cast the ballot of the voter in
+// the election. A ballot of another voter may only be cast by the proxy of a delegation approved
+// for that election, the proxy is then returned to be recorded alongside the voter, while such a
+// delegation is approved the voter may not cast the ballot themselves. A disabled voter may not
+// vote, neither may a voter without a verified email while the election requires verified emails.
+func (server Server) validBallotCaster(ctx *gin.Context, voter db.User, electionID int64) (sql.NullString, bool) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if voter.DisabledAt.Valid {
+		ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrAccountDisabled))
+		return sql.NullString{}, false
+	}
+
+	if !voter.VerifiedAt.Valid {
+		requireVerified, err := server.store.GetElectionProperty(ctx, util.RequireVerifiedEmail)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return sql.NullString{}, false
+		}
+
+		if requireVerified.Value {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrUnverifiedEmail))
+			return sql.NullString{}, false
+		}
+	}
+
+	if voter.NationalID == authPayload.NationalID {
+		_, err := server.store.GetGrantorApprovedDelegation(ctx, db.GetGrantorApprovedDelegationParams{
+			GrantorNationalID: voter.NationalID,
+			ElectionID:        electionID,
+		})
+		if err == nil {
+			ctx.JSON(http.StatusForbidden, errorResponse(ctx, ErrBallotDelegated))
+			return sql.NullString{}, false
+		}
+		if err != sql.ErrNoRows {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return sql.NullString{}, false
+		}
+		return sql.NullString{}, true
+	}
+
+	_, err := server.store.GetApprovedDelegation(ctx, db.GetApprovedDelegationParams{
+		GrantorNationalID: voter.NationalID,
+		ProxyNationalID:   authPayload.NationalID,
+		ElectionID:        electionID,
+	})
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrNoPermissionNationalID))
+			return sql.NullString{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return sql.NullString{}, false
+	}
+
+	return sql.NullString{String: authPayload.NationalID, Valid: true}, true
+}
+
+type getVoteReceiptRequest struct {
+	Code string `uri:"code" binding:"required,alphanum"`
+}
+
+type voteReceiptResponse struct {
+	Recorded   bool      `json:"recorded"`
+	Counted    bool      `json:"counted"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+func (server Server) getVoteReceipt(ctx *gin.Context) {
+	var req getVoteReceiptRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
 		return
 	}
 
-	arg := db.CreateVoteParams{
-		VoteNationalID: req.NationalId,
-		CandidateID:    req.CandidateId,
+	vote, err := server.store.GetVoteByReceipt(ctx, util.HashReceiptCode(req.Code))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, ErrReceiptNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
 	}
 
-	_, err = server.store.CreateVote(ctx, arg)
+	counted := !vote.SupersededAt.Valid
+	if !counted {
+		// While revoting is allowed a superseded receipt must look the same as
+		// a counted one, otherwise whoever coerced the voter could check it.
+		revoteEnabled, err := server.store.GetElectionProperty(ctx, util.RevoteEnabled)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+		counted = revoteEnabled.Value
+	}
+
+	rsp := voteReceiptResponse{
+		Recorded:   true,
+		Counted:    counted,
+		RecordedAt: vote.CreateAt,
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type listElectionVotesRequest struct {
+	PageSize int32  `form:"page_size" binding:"required,min=5,max=100"`
+	Cursor   string `form:"cursor"`
+}
+
+// voteCursor is the position after the last receipt hash of a page
+type voteCursor struct {
+	ElectionID  int64  `json:"election_id"`
+	ReceiptHash string `json:"receipt_hash"`
+}
+
+type listElectionVotesResponse struct {
+	Data       []string `json:"data"`
+	PageSize   int32    `json:"page_size"`
+	HasNext    bool     `json:"has_next"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// listElectionVotes publishes the receipt hashes of an election so every voter can
+// find their ballot on the list. Superseded ballots stay on the list, like the
+// receipt lookup it must not tell a revote apart. The hashes are listed without a
+// time and in hash order, so the list cannot be matched against who voted when.
+func (server Server) listElectionVotes(ctx *gin.Context) {
+	var uri electionRequest
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var req listElectionVotesRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	var after voteCursor
+	if req.Cursor != "" {
+		if err := util.VerifyCursor(server.config.TokenSymmetricKey, req.Cursor, &after); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+			return
+		}
+		if after.ElectionID != uri.ID {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, util.ErrInvalidCursor))
+			return
+		}
+	}
+
+	election, err := server.store.GetElection(ctx, uri.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	votes, err := server.store.ListElectionVotes(ctx, db.ListElectionVotesParams{
+		ElectionID:  election.ID,
+		ReceiptHash: after.ReceiptHash,
+		Limit:       req.PageSize + 1,
+	})
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse())
+	rsp := listElectionVotesResponse{
+		Data:     votes,
+		PageSize: req.PageSize,
+		HasNext:  len(votes) > int(req.PageSize),
+	}
+	if rsp.HasNext {
+		rsp.Data = votes[:req.PageSize]
+
+		next := voteCursor{ElectionID: election.ID, ReceiptHash: rsp.Data[len(rsp.Data)-1]}
+		rsp.NextCursor, err = util.SignCursor(server.config.TokenSymmetricKey, next)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
 }
@@ -8,6 +8,9 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
 	"testing"
 	"time"
 
@@ -18,6 +21,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/require"
 )
 
@@ -27,6 +31,7 @@ type VoteStatusResponse struct {
 
 func TestCheckVoteStatusAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
+	electionID := util.RandomInt(2, 1000)
 
 	testCases := []struct {
 		name          string
@@ -48,11 +53,51 @@ func TestCheckVoteStatusAPI(t *testing.T) {
 					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
 					Times(1).
 					Return(user, nil)
+
+				arg := db.HasElectionVoterVotedParams{
+					ElectionID: generalElectionID,
+					NationalID: user.NationalID,
+				}
+				store.EXPECT().
+					HasElectionVoterVoted(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(false, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVoteStatus(t, recorder.Body, VoteStatusResponse{
+					Status: false,
+				})
+			},
+		},
+		{
+			name: "VotedInElection",
+			body: gin.H{
+				"nationalId": user.NationalID,
+				"electionId": electionID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+
+				arg := db.HasElectionVoterVotedParams{
+					ElectionID: electionID,
+					NationalID: user.NationalID,
+				}
+				store.EXPECT().
+					HasElectionVoterVoted(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(true, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchVoteStatus(t, recorder.Body, VoteStatusResponse{
-					Status: user.HasVoted,
+					Status: true,
 				})
 			},
 		},
@@ -145,20 +190,77 @@ func TestCheckVoteStatusAPI(t *testing.T) {
 }
 
 type VotedResponse struct {
-	Status string `json:"status"`
+	Status  string `json:"status"`
+	Receipt string `json:"receipt"`
+}
+
+type eqCreateVoteParamsMatcher struct {
+	arg         db.CreateVoteParams
+	receiptHash *string
+}
+
+func (e eqCreateVoteParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateVoteParams)
+	if !ok {
+		return false
+	}
+
+	if len(arg.ReceiptHash) == 0 {
+		return false
+	}
+
+	*e.receiptHash = arg.ReceiptHash
+	e.arg.ReceiptHash = arg.ReceiptHash
+
+	return reflect.DeepEqual(e.arg, arg)
+}
+
+func (e eqCreateVoteParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v with any receipt hash", e.arg)
+}
+
+func eqCreateVoteParams(arg db.CreateVoteParams, receiptHash *string) gomock.Matcher {
+	return eqCreateVoteParamsMatcher{
+		arg:         arg,
+		receiptHash: receiptHash,
+	}
 }
 
 func TestVoteCandidateAPI(t *testing.T) {
 	user, _ := CreateRandomUser(t)
-	user2, _ := CreateRandomUser(t)
-	user2.HasVoted = true
 
 	candidate := RandomCandidate()
 	closedElectionProperty := CreateClosedElectionProperty()
 	closedElectionProperty2 := CreateClosedElectionProperty()
 	closedElectionProperty2.Value = true
 
+	candidateRow := db.GetCandidateRow{
+		ID:         candidate.ID,
+		Name:       candidate.Name,
+		ElectionID: util.RandomInt(1, 1000),
+	}
+	districtID := util.RandomInt(1, 1000)
+	districtCandidateRow := candidateRow
+	districtCandidateRow.DistrictID = sql.NullInt64{Int64: districtID, Valid: true}
+	districtUser := user
+	districtUser.DistrictID = sql.NullInt64{Int64: districtID, Valid: true}
+	closedCandidateRow := candidateRow
+	closedCandidateRow.ElectionClosed = true
+	partyListCandidateRow := candidateRow
+	partyListCandidateRow.ElectionBallotType = util.BallotTypePartyList
+	withdrawnCandidateRow := candidateRow
+	withdrawnCandidateRow.WithdrawnAt = sql.NullTime{Time: time.Now(), Valid: true}
+	unverifiedUser := user
+	unverifiedUser.VerifiedAt = sql.NullTime{}
+	disabledUser := user
+	disabledUser.DisabledAt = sql.NullTime{Time: time.Now(), Valid: true}
+
 	voted := CreateVoted(user.NationalID, candidate.ID)
+	var receiptHash string
+
+	proxy := CreateRandomProxy(t)
+	delegation := RandomDelegation(user.NationalID, proxy.NationalID, candidateRow.ElectionID)
+	delegation.Status = util.DelegationApproved
 
 	testCases := []struct {
 		name          string
@@ -177,6 +279,10 @@ func TestVoteCandidateAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
 				store.EXPECT().
 					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
 					Times(1).
@@ -185,23 +291,35 @@ func TestVoteCandidateAPI(t *testing.T) {
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
 					Times(1).
 					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
 
 				arg := db.CreateVoteParams{
 					VoteNationalID: user.NationalID,
 					CandidateID:    candidate.ID,
 				}
 				store.EXPECT().
-					CreateVote(gomock.Any(), gomock.Eq(arg)).
+					CreateVote(gomock.Any(), eqCreateVoteParams(arg, &receiptHash)).
 					Times(1).
 					Return(voted, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
-				requireBodyMatchResponseOK(t, recorder.Body)
+				requireBodyMatchVoteReceipt(t, recorder.Body, receiptHash)
 			},
 		},
 		{
-			name: "NationalIDNotFound",
+			name: "UnverifiedEmail",
 			body: gin.H{
 				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
@@ -213,20 +331,37 @@ func TestVoteCandidateAPI(t *testing.T) {
 				store.EXPECT().
 					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
 					Times(1).
-					Return(db.User{}, sql.ErrNoRows)
+					Return(unverifiedUser, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireVerifiedEmail)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.RequireVerifiedEmail, Value: true}, nil)
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
-					Times(0)
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
 				store.EXPECT().
 					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 		},
 		{
-			name: "NationalIDInternalError",
+			name: "UnverifiedEmailAllowed",
 			body: gin.H{
 				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
@@ -235,25 +370,47 @@ func TestVoteCandidateAPI(t *testing.T) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
 				store.EXPECT().
 					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
 					Times(1).
-					Return(db.User{}, sql.ErrConnDone)
+					Return(unverifiedUser, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RequireVerifiedEmail)).
+					Times(1).
+					Return(db.ElectionProperty{Name: util.RequireVerifiedEmail, Value: false}, nil)
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
-					Times(0)
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
 				store.EXPECT().
 					CreateVote(gomock.Any(), gomock.Any()).
-					Times(0)
+					Times(1).
+					Return(voted, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
 		{
-			name: "AlreadyVoted",
+			name: "CandidateElectionClosed",
 			body: gin.H{
-				"nationalId":  user2.NationalID,
+				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
@@ -261,12 +418,47 @@ func TestVoteCandidateAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUser(gomock.Any(), gomock.Eq(user2.NationalID)).
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
 					Times(1).
-					Return(user2, nil)
+					Return(user, nil)
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(closedCandidateRow, nil)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PartyListElection",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(partyListCandidateRow, nil)
 				store.EXPECT().
 					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -276,7 +468,7 @@ func TestVoteCandidateAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "GetClosedElectionInternalError",
+			name: "ElectionHasMeasures",
 			body: gin.H{
 				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
@@ -292,17 +484,29 @@ func TestVoteCandidateAPI(t *testing.T) {
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
 					Times(1).
-					Return(db.ElectionProperty{}, sql.ErrConnDone)
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(2), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
 				store.EXPECT().
 					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "ClosedElection",
+			name: "ElectionHasContests",
 			body: gin.H{
 				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
@@ -318,7 +522,19 @@ func TestVoteCandidateAPI(t *testing.T) {
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
 					Times(1).
-					Return(closedElectionProperty2, nil)
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(1), nil)
 				store.EXPECT().
 					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -328,7 +544,7 @@ func TestVoteCandidateAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "CreateVoteInternalError",
+			name: "CountMeasuresInternalError",
 			body: gin.H{
 				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
@@ -345,23 +561,26 @@ func TestVoteCandidateAPI(t *testing.T) {
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
 					Times(1).
 					Return(closedElectionProperty, nil)
-				arg := db.CreateVoteParams{
-					VoteNationalID: user.NationalID,
-					CandidateID:    candidate.ID,
-				}
 				store.EXPECT().
-					CreateVote(gomock.Any(), gomock.Eq(arg)).
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
 					Times(1).
-					Return(db.Vote{}, sql.ErrConnDone)
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), sql.ErrConnDone)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 		{
-			name: "InvalidNationalID",
+			name: "WithdrawnCandidate",
 			body: gin.H{
-				"nationalId":  "invalid#1",
+				"nationalId":  user.NationalID,
 				"candidateId": candidate.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
@@ -369,11 +588,17 @@ func TestVoteCandidateAPI(t *testing.T) {
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUser(gomock.Any(), gomock.Any()).
-					Times(0)
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
-					Times(0)
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(withdrawnCandidateRow, nil)
 				store.EXPECT().
 					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
@@ -383,18 +608,44 @@ func TestVoteCandidateAPI(t *testing.T) {
 			},
 		},
 		{
-			name: "InvalidCandidateID",
+			name: "NationalIDNotFound",
 			body: gin.H{
 				"nationalId":  user.NationalID,
-				"candidateId": -1,
+				"candidateId": candidate.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
 			},
 			buildStub: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUser(gomock.Any(), gomock.Any()).
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(0)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NationalIDInternalError",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
 				store.EXPECT().
 					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
 					Times(0)
@@ -402,38 +653,670 @@ func TestVoteCandidateAPI(t *testing.T) {
 					CreateVote(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "CreateVoteAlreadyVoted",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Vote{}, &pq.Error{Code: "23505"})
+			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-	}
-
-	for i := range testCases {
-		tc := testCases[i]
-
-		t.Run(tc.name, func(t *testing.T) {
-
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-
-			tc.buildStub(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			url := fmt.Sprintf("/api/vote")
-			values, err := json.Marshal(tc.body)
-			require.NoError(t, err)
-
-			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
-			require.NoError(t, err)
-
-			tc.setupAuth(t, request, server.tokenMaker)
-			server.router.ServeHTTP(recorder, request)
-
-			tc.checkResponse(t, recorder)
+		{
+			name: "GetClosedElectionInternalError",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(db.ElectionProperty{}, sql.ErrConnDone)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "ClosedElection",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty2, nil)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CreateVoteInternalError",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				arg := db.CreateVoteParams{
+					VoteNationalID: user.NationalID,
+					CandidateID:    candidate.ID,
+				}
+				store.EXPECT().
+					CreateVote(gomock.Any(), eqCreateVoteParams(arg, &receiptHash)).
+					Times(1).
+					Return(db.Vote{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "CandidateNotFound",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(db.GetCandidateRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "CandidateNotInDistrict",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(districtCandidateRow, nil)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CandidateInDistrict",
+			body: gin.H{
+				"nationalId":  districtUser.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, districtUser.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(districtUser.NationalID)).
+					Times(1).
+					Return(districtUser, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(districtCandidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				arg := db.CreateVoteParams{
+					VoteNationalID: districtUser.NationalID,
+					CandidateID:    candidate.ID,
+				}
+				store.EXPECT().
+					CreateVote(gomock.Any(), eqCreateVoteParams(arg, &receiptHash)).
+					Times(1).
+					Return(voted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVoteReceipt(t, recorder.Body, receiptHash)
+			},
+		},
+		{
+			name: "InvalidNationalID",
+			body: gin.H{
+				"nationalId":  "invalid#1",
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(0)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCandidateID",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": -1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(0)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OKByProxy",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, proxy.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+
+				delegationArg := db.GetApprovedDelegationParams{
+					GrantorNationalID: user.NationalID,
+					ProxyNationalID:   proxy.NationalID,
+					ElectionID:        candidateRow.ElectionID,
+				}
+				store.EXPECT().
+					GetApprovedDelegation(gomock.Any(), gomock.Eq(delegationArg)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				arg := db.CreateVoteParams{
+					VoteNationalID:   user.NationalID,
+					CandidateID:      candidate.ID,
+					CastByNationalID: sql.NullString{String: proxy.NationalID, Valid: true},
+				}
+				store.EXPECT().
+					CreateVote(gomock.Any(), eqCreateVoteParams(arg, &receiptHash)).
+					Times(1).
+					Return(voted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVoteReceipt(t, recorder.Body, receiptHash)
+			},
+		},
+		{
+			name: "NoApprovedDelegation",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, proxy.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				// a delegation approved for another election does not count
+				delegationArg := db.GetApprovedDelegationParams{
+					GrantorNationalID: user.NationalID,
+					ProxyNationalID:   proxy.NationalID,
+					ElectionID:        candidateRow.ElectionID,
+				}
+				store.EXPECT().
+					GetApprovedDelegation(gomock.Any(), gomock.Eq(delegationArg)).
+					Times(1).
+					Return(db.Delegation{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DisabledGrantor",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, proxy.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(disabledUser, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					GetApprovedDelegation(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "BallotDelegated",
+			body: gin.H{
+				"nationalId":  user.NationalID,
+				"candidateId": candidate.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.NationalID, time.Minute)
+			},
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.NationalID)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.ElectionClosed)).
+					Times(1).
+					Return(closedElectionProperty, nil)
+				store.EXPECT().
+					GetCandidate(gomock.Any(), gomock.Eq(candidate.ID)).
+					Times(1).
+					Return(candidateRow, nil)
+				store.EXPECT().
+					CountElectionBallotMeasures(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+				store.EXPECT().
+					CountElectionContests(gomock.Any(), gomock.Eq(candidateRow.ElectionID)).
+					Times(1).
+					Return(int64(0), nil)
+
+				grantorArg := db.GetGrantorApprovedDelegationParams{
+					GrantorNationalID: user.NationalID,
+					ElectionID:        candidateRow.ElectionID,
+				}
+				store.EXPECT().
+					GetGrantorApprovedDelegation(gomock.Any(), gomock.Eq(grantorArg)).
+					Times(1).
+					Return(delegation, nil)
+				store.EXPECT().
+					CreateVote(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/api/vote")
+			values, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(values))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
+
+		})
+
+	}
+
+}
+
+func TestGetVoteReceiptAPI(t *testing.T) {
+	user, _ := CreateRandomUser(t)
+	candidate := RandomCandidate()
+	voted := CreateVoted(user.NationalID, candidate.ID)
+
+	superseded := CreateVoted(user.NationalID, candidate.ID)
+	superseded.SupersededAt = sql.NullTime{Time: time.Now(), Valid: true}
+
+	revoteProperty := CreateRevoteProperty()
+	revoteProperty2 := CreateRevoteProperty()
+	revoteProperty2.Value = true
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		code          string
+		buildStub     func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			code: receiptCode,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Eq(util.HashReceiptCode(receiptCode))).
+					Times(1).
+					Return(voted, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVoteReceiptStatus(t, recorder.Body, voted, true)
+			},
+		},
+		{
+			name: "Superseded",
+			code: receiptCode,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Eq(util.HashReceiptCode(receiptCode))).
+					Times(1).
+					Return(superseded, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RevoteEnabled)).
+					Times(1).
+					Return(revoteProperty, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVoteReceiptStatus(t, recorder.Body, superseded, false)
+			},
+		},
+		{
+			name: "SupersededWhileRevoteEnabled",
+			code: receiptCode,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Eq(util.HashReceiptCode(receiptCode))).
+					Times(1).
+					Return(superseded, nil)
+				store.EXPECT().
+					GetElectionProperty(gomock.Any(), gomock.Eq(util.RevoteEnabled)).
+					Times(1).
+					Return(revoteProperty2, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchVoteReceiptStatus(t, recorder.Body, superseded, true)
+			},
+		},
+		{
+			name: "NotFound",
+			code: receiptCode,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Vote{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			code: receiptCode,
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Vote{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidCode",
+			code: "invalid#code",
+			buildStub: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVoteByReceipt(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+
+			tc.buildStub(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/vote/receipt/%s", url.PathEscape(tc.code))
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+
+			tc.checkResponse(t, recorder)
 
 		})
 
@@ -441,6 +1324,86 @@ func TestVoteCandidateAPI(t *testing.T) {
 
 }
 
+func TestListElectionVotesAPI(t *testing.T) {
+	election := RandomElection()
+	pageSize := 5
+	votes := make([]string, pageSize+1)
+	for i := range votes {
+		votes[i] = util.HashReceiptCode(util.RandomString(24))
+	}
+	sort.Strings(votes)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := newTestServer(t, store)
+
+	listVotes := func(electionID int64, cursor string) *httptest.ResponseRecorder {
+		query := url.Values{}
+		query.Add("page_size", fmt.Sprintf("%d", pageSize))
+		if cursor != "" {
+			query.Add("cursor", cursor)
+		}
+
+		recorder := httptest.NewRecorder()
+		url := fmt.Sprintf("/elections/%d/votes?%s", electionID, query.Encode())
+		request, err := http.NewRequest(http.MethodGet, url, nil)
+		require.NoError(t, err)
+
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	store.EXPECT().
+		GetElection(gomock.Any(), gomock.Eq(election.ID)).
+		Times(2).
+		Return(election, nil)
+	store.EXPECT().
+		ListElectionVotes(gomock.Any(), gomock.Eq(db.ListElectionVotesParams{
+			ElectionID: election.ID,
+			Limit:      int32(pageSize + 1),
+		})).
+		Times(1).
+		Return(votes, nil)
+	store.EXPECT().
+		ListElectionVotes(gomock.Any(), gomock.Eq(db.ListElectionVotesParams{
+			ElectionID:  election.ID,
+			ReceiptHash: votes[pageSize-1],
+			Limit:       int32(pageSize + 1),
+		})).
+		Times(1).
+		Return(votes[pageSize:], nil)
+
+	recorder := listVotes(election.ID, "")
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp listElectionVotesResponse
+	err := json.Unmarshal(recorder.Body.Bytes(), &rsp)
+	require.NoError(t, err)
+	require.Equal(t, votes[:pageSize], rsp.Data)
+	require.True(t, rsp.HasNext)
+	require.NotContains(t, recorder.Body.String(), "create_at")
+	require.NotEmpty(t, rsp.NextCursor)
+
+	recorder = listVotes(election.ID, rsp.NextCursor)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var next listElectionVotesResponse
+	err = json.Unmarshal(recorder.Body.Bytes(), &next)
+	require.NoError(t, err)
+	require.Equal(t, votes[pageSize:], next.Data)
+	require.False(t, next.HasNext)
+	require.Empty(t, next.NextCursor)
+
+	// the cursor of one election cannot page through another
+	recorder = listVotes(election.ID+1, rsp.NextCursor)
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	recorder = listVotes(election.ID, rsp.NextCursor[1:])
+	require.Equal(t, http.StatusBadRequest, recorder.Code)
+}
+
 func requireBodyMatchVoteStatus(t *testing.T, body *bytes.Buffer, voteStatus VoteStatusResponse) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
@@ -451,7 +1414,7 @@ func requireBodyMatchVoteStatus(t *testing.T, body *bytes.Buffer, voteStatus Vot
 	require.Equal(t, voteStatus, gotVoteStatus)
 }
 
-func requireBodyMatchResponseOK(t *testing.T, body *bytes.Buffer) {
+func requireBodyMatchVoteReceipt(t *testing.T, body *bytes.Buffer, receiptHash string) {
 	data, err := ioutil.ReadAll(body)
 	require.NoError(t, err)
 
@@ -459,6 +1422,22 @@ func requireBodyMatchResponseOK(t *testing.T, body *bytes.Buffer) {
 	err = json.Unmarshal(data, &gotVotedResponse)
 	require.NoError(t, err)
 	require.Equal(t, "ok", gotVotedResponse.Status)
+	require.NotEmpty(t, gotVotedResponse.Receipt)
+	require.Equal(t, receiptHash, util.HashReceiptCode(gotVotedResponse.Receipt))
+}
+
+func requireBodyMatchVoteReceiptStatus(t *testing.T, body *bytes.Buffer, vote db.Vote, counted bool) {
+	data, err := ioutil.ReadAll(body)
+	require.NoError(t, err)
+
+	var gotReceipt voteReceiptResponse
+	err = json.Unmarshal(data, &gotReceipt)
+	require.NoError(t, err)
+	require.True(t, gotReceipt.Recorded)
+	require.Equal(t, counted, gotReceipt.Counted)
+	require.WithinDuration(t, vote.CreateAt, gotReceipt.RecordedAt, time.Second)
+	require.NotContains(t, string(data), "candidate")
+	require.NotContains(t, string(data), vote.VoteNationalID)
 }
 
 func CreateClosedElectionProperty() db.ElectionProperty {
@@ -468,10 +1447,20 @@ func CreateClosedElectionProperty() db.ElectionProperty {
 		Value: false,
 	}
 }
+func CreateRevoteProperty() db.ElectionProperty {
+	return db.ElectionProperty{
+		ID:    util.RandomInt(1, 1000),
+		Name:  util.RevoteEnabled,
+		Value: false,
+	}
+}
+
 func CreateVoted(nationalID string, candidateId int64) db.Vote {
 	return db.Vote{
 		ID:             util.RandomInt(1, 1000),
 		VoteNationalID: nationalID,
 		CandidateID:    candidateId,
+		ReceiptHash:    util.RandomString(64),
+		CreateAt:       time.Now(),
 	}
 }
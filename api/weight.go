@@ -0,0 +1,183 @@
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	db "election/db/sqlc"
+	"election/util"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrInvalidVoterWeights = errors.New("Invalid voter weights")
+	ErrWeightsLocked       = errors.New("Voter weights cannot change once voting has started")
+)
+
+type updateVoterWeightRequest struct {
+	NationalID string `json:"national_id" binding:"required,number,len=13"`
+	ElectionID int64  `json:"election_id" binding:"required,min=1"`
+	VoteWeight int64  `json:"vote_weight" binding:"required,min=1"`
+}
+
+type voterWeightResponse struct {
+	NationalID string `json:"national_id"`
+	FullName   string `json:"full_name"`
+	ElectionID int64  `json:"election_id"`
+	VoteWeight int64  `json:"vote_weight"`
+}
+
+func (server Server) updateVoterWeight(ctx *gin.Context) {
+	var req updateVoterWeightRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, ok := server.weightableElection(ctx, req.ElectionID)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUser(ctx, req.NationalID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	arg := db.SetElectionVoterWeightParams{
+		ElectionID: election.ID,
+		NationalID: user.NationalID,
+		VoteWeight: req.VoteWeight,
+	}
+
+	voter, err := server.store.SetElectionVoterWeight(ctx, arg)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// the election was found open, the first ballot came in since
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrWeightsLocked))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return
+	}
+
+	rsp := voterWeightResponse{
+		NationalID: user.NationalID,
+		FullName:   user.FullName,
+		ElectionID: voter.ElectionID,
+		VoteWeight: voter.VoteWeight,
+	}
+
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+// weightableElection returns the election when its voter weights may still change, once any
+// ballot was cast in it the weights are locked so every ballot is counted with the weights
+// the percentages are taken of. The update locks the election row and checks again.
+func (server Server) weightableElection(ctx *gin.Context, electionID int64) (db.Election, bool) {
+	election, err := server.store.GetElection(ctx, electionID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(ctx, err))
+			return db.Election{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(ctx, err))
+		return db.Election{}, false
+	}
+
+	if election.Closed {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrClosedElection))
+		return db.Election{}, false
+	}
+
+	if election.VotingStartedAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrWeightsLocked))
+		return db.Election{}, false
+	}
+
+	return election, true
+}
+
+type importVoterWeightsRequest struct {
+	ElectionID int64 `form:"election_id" binding:"required,min=1"`
+}
+
+// importVoterWeights sets the vote weights of an election from an uploaded csv file
+// with a header row then "National id,Weight" rows
+func (server Server) importVoterWeights(ctx *gin.Context) {
+	var req importVoterWeightsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, err))
+		return
+	}
+
+	election, ok := server.weightableElection(ctx, req.ElectionID)
+	if !ok {
+		return
+	}
+
+	records, err := readCSVUpload(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("%w: %v", ErrInvalidVoterWeights, err)))
+		return
+	}
+
+	entries := make([]db.VoterWeightEntry, 0, len(records))
+	for i, record := range records {
+		entry, err := parseVoterWeightRecord(record)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, fmt.Errorf("%w: row %d: %v", ErrInvalidVoterWeights, i+2, err)))
+			return
+		}
+		entries = append(entries, entry)
+	}
+
+	result, err := server.store.ImportVoterWeightsTx(ctx, db.ImportVoterWeightsTxParams{
+		ElectionID: election.ID,
+		Entries:    entries,
+	})
+	if err != nil {
+		if err == db.ErrVotingStarted {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ctx, ErrWeightsLocked))
+			return
+		}
+		ctx.JSON(importErrorStatus(err), errorResponse(ctx, err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{
+		"status":   "ok",
+		"imported": len(result.Voters),
+	})
+}
+
+func parseVoterWeightRecord(record []string) (db.VoterWeightEntry, error) {
+	if len(record) != 2 {
+		return db.VoterWeightEntry{}, fmt.Errorf("expected 2 columns, got %d", len(record))
+	}
+
+	nationalID := strings.TrimSpace(record[0])
+	if !util.IsNationalID(nationalID) {
+		return db.VoterWeightEntry{}, fmt.Errorf("invalid national id %q", nationalID)
+	}
+
+	weight, err := strconv.ParseInt(strings.TrimSpace(record[1]), 10, 64)
+	if err != nil || weight < 1 {
+		return db.VoterWeightEntry{}, fmt.Errorf("invalid weight %q", record[1])
+	}
+
+	return db.VoterWeightEntry{
+		NationalID: nationalID,
+		VoteWeight: weight,
+	}, nil
+}
@@ -0,0 +1,51 @@
+package blob
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	data := []byte("image data")
+	err = store.Put(context.Background(), "candidates/1.jpg", "image/jpeg", data)
+	require.NoError(t, err)
+
+	object, err := store.Get(context.Background(), "candidates/1.jpg")
+	require.NoError(t, err)
+	require.Equal(t, data, object.Data)
+	require.Equal(t, "image/jpeg", object.ContentType)
+
+	err = store.Put(context.Background(), "candidates/1.jpg", "image/jpeg", []byte("replaced"))
+	require.NoError(t, err)
+
+	object, err = store.Get(context.Background(), "candidates/1.jpg")
+	require.NoError(t, err)
+	require.Equal(t, []byte("replaced"), object.Data)
+}
+
+func TestLocalStoreNotFound(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	object, err := store.Get(context.Background(), "missing.jpg")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.Nil(t, object)
+}
+
+func TestLocalStoreInvalidKey(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "/etc/passwd", "../secret.jpg", "a/../../b.jpg", "a//b.jpg", "a\\b.jpg"} {
+		err = store.Put(context.Background(), key, "image/jpeg", []byte("data"))
+		require.ErrorIs(t, err, ErrInvalidKey, key)
+
+		_, err = store.Get(context.Background(), key)
+		require.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
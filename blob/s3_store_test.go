@@ -0,0 +1,84 @@
+package blob
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newFakeS3 serves a single bucket from memory and rejects unsigned requests
+func newFakeS3(t *testing.T, bucket string) *httptest.Server {
+	var mu sync.Mutex
+	objects := map[string][]byte{}
+	types := map[string]string{}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=access/") ||
+			!strings.Contains(auth, "/eu-west-1/s3/aws4_request") ||
+			r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/"+bucket+"/")
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			objects[key] = data
+			types[key] = r.Header.Get("Content-Type")
+		case http.MethodGet:
+			data, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", types[key])
+			w.Write(data)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+}
+
+func TestS3Store(t *testing.T) {
+	server := newFakeS3(t, "images")
+	defer server.Close()
+
+	store, err := NewS3Store(server.URL, "eu-west-1", "images", "access", "secret")
+	require.NoError(t, err)
+
+	data := []byte("image data")
+	err = store.Put(context.Background(), "candidates/1.png", "image/png", data)
+	require.NoError(t, err)
+
+	object, err := store.Get(context.Background(), "candidates/1.png")
+	require.NoError(t, err)
+	require.Equal(t, data, object.Data)
+	require.Equal(t, "image/png", object.ContentType)
+
+	_, err = store.Get(context.Background(), "candidates/2.png")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestS3StoreInvalidConfig(t *testing.T) {
+	_, err := NewS3Store("not a url", "eu-west-1", "images", "access", "secret")
+	require.Error(t, err)
+
+	_, err = NewS3Store("https://s3.example.com", "eu-west-1", "", "access", "secret")
+	require.Error(t, err)
+}
+
+func TestEscapePath(t *testing.T) {
+	require.Equal(t, "/images/a%20b%2Bc~d.jpg", escapePath("/images/a b+c~d.jpg"))
+}
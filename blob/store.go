@@ -0,0 +1,47 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"strings"
+)
+
+var (
+	ErrNotFound   = errors.New("blob not found")
+	ErrInvalidKey = errors.New("invalid blob key")
+)
+
+//Store is an interface for storing binary objects, e.g. images, by key
+type Store interface {
+	//Put stores the data under the key, an existing object is replaced
+	Put(ctx context.Context, key string, contentType string, data []byte) error
+
+	//Get returns the object stored under the key or ErrNotFound
+	Get(ctx context.Context, key string) (*Object, error)
+}
+
+//Object is a stored binary object
+type Object struct {
+	ContentType string
+	Data        []byte
+}
+
+// validKey rejects keys which could escape the store, keys are relative slash separated paths
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key || strings.HasPrefix(key, "..") {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return nil
+}
+
+// contentTypeOf guesses the content type of a key from its extension
+func contentTypeOf(key string) string {
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		return "application/octet-stream"
+	}
+	return contentType
+}
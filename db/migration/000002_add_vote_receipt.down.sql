@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "votes" DROP CONSTRAINT IF EXISTS "votes_receipt_hash_key";
+
+ALTER TABLE IF EXISTS "votes" DROP COLUMN IF EXISTS "receipt_hash";
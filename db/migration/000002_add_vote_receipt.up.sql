@@ -0,0 +1,7 @@
+ALTER TABLE "votes" ADD COLUMN "receipt_hash" varchar;
+
+UPDATE "votes" SET "receipt_hash" = md5(random()::text || "id"::text) WHERE "receipt_hash" IS NULL;
+
+ALTER TABLE "votes" ALTER COLUMN "receipt_hash" SET NOT NULL;
+
+ALTER TABLE "votes" ADD CONSTRAINT "votes_receipt_hash_key" UNIQUE ("receipt_hash");
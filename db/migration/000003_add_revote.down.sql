@@ -0,0 +1,32 @@
+DROP TRIGGER IF EXISTS vote_supersede_trigger on "votes";
+
+DROP FUNCTION IF EXISTS vote_supersede_trigger_fnc;
+
+DELETE FROM "votes" WHERE "superseded_at" IS NOT NULL;
+
+DROP INDEX IF EXISTS "votes_active_national_id_key";
+
+ALTER TABLE "votes" ADD CONSTRAINT "vote_national_id_key" UNIQUE ("vote_national_id");
+
+ALTER TABLE IF EXISTS "votes" DROP COLUMN IF EXISTS "superseded_at";
+
+DELETE FROM "election_properties" WHERE "name" = 'REVOTE_ENABLED';
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = vote_count + 1, percentage = (
+    	(
+    		(select COUNT(*) from votes where candidate_id = NEW."candidate_id")/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  )
+	WHERE id = NEW."candidate_id";
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
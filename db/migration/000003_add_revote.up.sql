@@ -0,0 +1,61 @@
+ALTER TABLE "votes" ADD COLUMN "superseded_at" timestamptz;
+
+ALTER TABLE "votes" DROP CONSTRAINT IF EXISTS "vote_national_id_key";
+
+CREATE UNIQUE INDEX "votes_active_national_id_key" ON "votes" ("vote_national_id") WHERE "superseded_at" IS NULL;
+
+INSERT INTO "election_properties" ("name", "value") VALUES ('REVOTE_ENABLED', 'f');
+
+
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER vote_supersede_trigger
+  BEFORE INSERT
+  ON "votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE vote_supersede_trigger_fnc();
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    	(
+    		tally.vote_count/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  )
+	FROM (
+		SELECT c.id, (
+			select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS vote_count
+		FROM candidates c
+		WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = NEW."vote_national_id")
+	) AS tally
+	WHERE candidates.id = tally.id;
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
@@ -0,0 +1,5 @@
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "district_id";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "district_id";
+
+DROP TABLE IF EXISTS districts;
@@ -0,0 +1,17 @@
+CREATE TABLE "districts" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "users" ADD COLUMN "district_id" bigint;
+
+ALTER TABLE "candidates" ADD COLUMN "district_id" bigint;
+
+ALTER TABLE "users" ADD FOREIGN KEY ("district_id") REFERENCES "districts" ("id");
+
+ALTER TABLE "candidates" ADD FOREIGN KEY ("district_id") REFERENCES "districts" ("id");
+
+CREATE INDEX ON "users" ("district_id");
+
+CREATE INDEX ON "candidates" ("district_id");
@@ -0,0 +1,54 @@
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    	(
+    		tally.vote_count/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  )
+	FROM (
+		SELECT c.id, (
+			select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS vote_count
+		FROM candidates c
+		WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = NEW."vote_national_id")
+	) AS tally
+	WHERE candidates.id = tally.id;
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "weighted_percentage";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "weighted_vote_count";
+
+ALTER TABLE IF EXISTS "votes" DROP COLUMN IF EXISTS "weight";
+
+ALTER TABLE IF EXISTS "users" DROP COLUMN IF EXISTS "vote_weight";
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS "votes" DROP COLUMN IF EXISTS "cast_by_national_id";
+
+DROP TABLE IF EXISTS delegations;
@@ -0,0 +1,24 @@
+CREATE TABLE "delegations" (
+  "id" bigserial PRIMARY KEY,
+  "grantor_national_id" varchar NOT NULL,
+  "proxy_national_id" varchar NOT NULL,
+  "status" varchar NOT NULL,
+  "reviewed_by" varchar,
+  "reviewed_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now()),
+  CHECK ("grantor_national_id" <> "proxy_national_id")
+);
+
+ALTER TABLE "delegations" ADD FOREIGN KEY ("grantor_national_id") REFERENCES "users" ("national_id");
+
+ALTER TABLE "delegations" ADD FOREIGN KEY ("proxy_national_id") REFERENCES "users" ("national_id");
+
+ALTER TABLE "delegations" ADD FOREIGN KEY ("reviewed_by") REFERENCES "users" ("national_id");
+
+CREATE UNIQUE INDEX "delegations_active_grantor_key" ON "delegations" ("grantor_national_id") WHERE "status" <> 'REVOKED';
+
+CREATE INDEX ON "delegations" ("proxy_national_id");
+
+ALTER TABLE "votes" ADD COLUMN "cast_by_national_id" varchar;
+
+ALTER TABLE "votes" ADD FOREIGN KEY ("cast_by_national_id") REFERENCES "users" ("national_id");
@@ -0,0 +1,9 @@
+DROP TRIGGER IF EXISTS measure_vote_supersede_trigger ON measure_votes;
+
+DROP FUNCTION IF EXISTS measure_vote_supersede_trigger_fnc();
+
+DROP TABLE IF EXISTS measure_votes;
+
+DROP TABLE IF EXISTS ballot_measure_options;
+
+DROP TABLE IF EXISTS ballot_measures;
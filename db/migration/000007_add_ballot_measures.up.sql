@@ -0,0 +1,72 @@
+CREATE TABLE "ballot_measures" (
+  "id" bigserial PRIMARY KEY,
+  "question" varchar NOT NULL,
+  "threshold_percentage" integer NOT NULL DEFAULT 50 CHECK ("threshold_percentage" BETWEEN 50 AND 99),
+  "quorum_percentage" integer NOT NULL DEFAULT 0 CHECK ("quorum_percentage" BETWEEN 0 AND 100),
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE TABLE "ballot_measure_options" (
+  "id" bigserial PRIMARY KEY,
+  "measure_id" bigint NOT NULL,
+  "label" varchar NOT NULL,
+  "position" integer NOT NULL,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "ballot_measure_options" ADD FOREIGN KEY ("measure_id") REFERENCES "ballot_measures" ("id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "ballot_measure_options" ("measure_id", "position");
+
+CREATE TABLE "measure_votes" (
+  "id" bigserial PRIMARY KEY,
+  "vote_national_id" varchar NOT NULL,
+  "measure_id" bigint NOT NULL,
+  "option_id" bigint NOT NULL,
+  "cast_by_national_id" varchar,
+  "weight" bigint NOT NULL DEFAULT 1,
+  "superseded_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "measure_votes" ADD FOREIGN KEY ("vote_national_id") REFERENCES "users" ("national_id");
+
+ALTER TABLE "measure_votes" ADD FOREIGN KEY ("measure_id") REFERENCES "ballot_measures" ("id");
+
+ALTER TABLE "measure_votes" ADD FOREIGN KEY ("option_id") REFERENCES "ballot_measure_options" ("id");
+
+ALTER TABLE "measure_votes" ADD FOREIGN KEY ("cast_by_national_id") REFERENCES "users" ("national_id");
+
+CREATE UNIQUE INDEX "measure_votes_active_national_id_key" ON "measure_votes" ("vote_national_id", "measure_id") WHERE "superseded_at" IS NULL;
+
+CREATE INDEX ON "measure_votes" ("option_id");
+
+
+CREATE OR REPLACE FUNCTION measure_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM measure_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted on measure %', NEW."vote_national_id", NEW."measure_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE measure_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER measure_vote_supersede_trigger
+  BEFORE INSERT
+  ON "measure_votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE measure_vote_supersede_trigger_fnc();
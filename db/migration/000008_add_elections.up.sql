@@ -0,0 +1,11 @@
+CREATE TABLE "elections" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar NOT NULL,
+  "winner_rule" varchar NOT NULL DEFAULT 'PLURALITY' CHECK ("winner_rule" IN ('PLURALITY', 'ABSOLUTE_MAJORITY')),
+  "quorum_percentage" integer NOT NULL DEFAULT 0 CHECK ("quorum_percentage" BETWEEN 0 AND 100),
+  "tie_break" varchar NOT NULL DEFAULT 'RUNOFF' CHECK ("tie_break" IN ('LOTTERY', 'RUNOFF')),
+  "tie_break_seed" varchar NOT NULL DEFAULT (md5(random()::text)),
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+INSERT INTO "elections" ("name") VALUES ('General election');
@@ -0,0 +1,36 @@
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+DROP INDEX IF EXISTS "votes_active_national_id_key";
+
+CREATE UNIQUE INDEX "votes_active_national_id_key" ON "votes" ("vote_national_id") WHERE "superseded_at" IS NULL;
+
+ALTER TABLE IF EXISTS "votes" DROP COLUMN IF EXISTS "election_id";
+
+ALTER TABLE IF EXISTS "ballot_measures" DROP COLUMN IF EXISTS "election_id";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "election_id";
+
+ALTER TABLE IF EXISTS "elections" DROP COLUMN IF EXISTS "runoff_of_election_id";
+
+ALTER TABLE IF EXISTS "elections" DROP COLUMN IF EXISTS "closed";
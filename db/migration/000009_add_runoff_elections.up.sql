@@ -0,0 +1,51 @@
+ALTER TABLE "elections" ADD COLUMN "closed" boolean NOT NULL DEFAULT false;
+
+ALTER TABLE "elections" ADD COLUMN "runoff_of_election_id" bigint;
+
+ALTER TABLE "elections" ADD FOREIGN KEY ("runoff_of_election_id") REFERENCES "elections" ("id");
+
+CREATE UNIQUE INDEX ON "elections" ("runoff_of_election_id");
+
+ALTER TABLE "candidates" ADD COLUMN "election_id" bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE "candidates" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id");
+
+CREATE INDEX ON "candidates" ("election_id");
+
+ALTER TABLE "ballot_measures" ADD COLUMN "election_id" bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE "ballot_measures" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id");
+
+ALTER TABLE "votes" ADD COLUMN "election_id" bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE "votes" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id");
+
+DROP INDEX IF EXISTS "votes_active_national_id_key";
+
+CREATE UNIQUE INDEX "votes_active_national_id_key" ON "votes" ("vote_national_id", "election_id") WHERE "superseded_at" IS NULL;
+
+
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  NEW."election_id" := (SELECT election_id FROM candidates WHERE id = NEW."candidate_id");
+
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
@@ -0,0 +1,21 @@
+DROP TRIGGER IF EXISTS candidate_revision_trigger ON "candidates";
+
+DROP FUNCTION IF EXISTS candidate_revision_trigger_fnc();
+
+DROP TRIGGER IF EXISTS candidate_version_trigger ON "candidates";
+
+DROP FUNCTION IF EXISTS candidate_version_trigger_fnc();
+
+DROP FUNCTION IF EXISTS candidate_profile(candidates);
+
+DROP TABLE IF EXISTS candidate_revisions;
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "edited_by";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "version";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "links";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "policy_items";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "party";
@@ -0,0 +1,85 @@
+ALTER TABLE "candidates" ADD COLUMN "party" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "candidates" ADD COLUMN "policy_items" jsonb NOT NULL DEFAULT '[]';
+
+ALTER TABLE "candidates" ADD COLUMN "links" jsonb NOT NULL DEFAULT '[]';
+
+ALTER TABLE "candidates" ADD COLUMN "version" integer NOT NULL DEFAULT 1;
+
+ALTER TABLE "candidates" ADD COLUMN "edited_by" varchar;
+
+ALTER TABLE "candidates" ADD FOREIGN KEY ("edited_by") REFERENCES "users" ("national_id");
+
+CREATE TABLE "candidate_revisions" (
+  "id" bigserial PRIMARY KEY,
+  "candidate_id" bigint NOT NULL,
+  "version" integer NOT NULL,
+  "profile" jsonb NOT NULL,
+  "edited_by" varchar,
+  "create_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("candidate_id", "version")
+);
+
+ALTER TABLE "candidate_revisions" ADD FOREIGN KEY ("candidate_id") REFERENCES "candidates" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "candidate_revisions" ADD FOREIGN KEY ("edited_by") REFERENCES "users" ("national_id");
+
+
+CREATE OR REPLACE FUNCTION candidate_profile(c candidates)
+  RETURNS jsonb AS
+$$
+  SELECT jsonb_build_object(
+    'name', c.name,
+    'dob', c.dob,
+    'bio_link', c.bio_link,
+    'image_url', c.image_url,
+    'policy', c.policy,
+    'policy_items', c.policy_items,
+    'links', c.links,
+    'party', c.party,
+    'district_id', c.district_id
+  );
+$$
+LANGUAGE 'sql' IMMUTABLE;
+
+INSERT INTO "candidate_revisions" ("candidate_id", "version", "profile")
+SELECT c.id, c.version, candidate_profile(c) FROM candidates c;
+
+
+CREATE OR REPLACE FUNCTION candidate_version_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF candidate_profile(NEW) IS DISTINCT FROM candidate_profile(OLD) THEN
+    NEW."version" := OLD."version" + 1;
+  END IF;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER candidate_version_trigger
+  BEFORE UPDATE
+  ON "candidates"
+  FOR EACH ROW
+  EXECUTE PROCEDURE candidate_version_trigger_fnc();
+
+
+CREATE OR REPLACE FUNCTION candidate_revision_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF TG_OP = 'INSERT' OR NEW."version" <> OLD."version" THEN
+    INSERT INTO candidate_revisions (candidate_id, version, profile, edited_by)
+    VALUES (NEW."id", NEW."version", candidate_profile(NEW), NEW."edited_by");
+  END IF;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER candidate_revision_trigger
+  AFTER INSERT OR UPDATE
+  ON "candidates"
+  FOR EACH ROW
+  EXECUTE PROCEDURE candidate_revision_trigger_fnc();
@@ -0,0 +1,38 @@
+DROP TRIGGER IF EXISTS party_vote_supersede_trigger ON "party_votes";
+
+DROP FUNCTION IF EXISTS party_vote_supersede_trigger_fnc();
+
+DROP TABLE IF EXISTS party_votes;
+
+ALTER TABLE IF EXISTS "elections" DROP COLUMN IF EXISTS "seat_method";
+
+ALTER TABLE IF EXISTS "elections" DROP COLUMN IF EXISTS "seats";
+
+ALTER TABLE IF EXISTS "elections" DROP COLUMN IF EXISTS "ballot_type";
+
+ALTER TABLE "candidates" ADD COLUMN "party" varchar NOT NULL DEFAULT '';
+
+UPDATE "candidates" SET "party" = p.name
+FROM "parties" p
+WHERE p.id = "candidates"."party_id";
+
+CREATE OR REPLACE FUNCTION candidate_profile(c candidates)
+  RETURNS jsonb AS
+$$
+  SELECT jsonb_build_object(
+    'name', c.name,
+    'dob', c.dob,
+    'bio_link', c.bio_link,
+    'image_url', c.image_url,
+    'policy', c.policy,
+    'policy_items', c.policy_items,
+    'links', c.links,
+    'party', c.party,
+    'district_id', c.district_id
+  );
+$$
+LANGUAGE 'sql' IMMUTABLE;
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "party_id";
+
+DROP TABLE IF EXISTS parties;
@@ -0,0 +1,104 @@
+CREATE TABLE "parties" (
+  "id" bigserial PRIMARY KEY,
+  "name" varchar UNIQUE NOT NULL,
+  "logo_url" varchar NOT NULL DEFAULT '',
+  "color" varchar NOT NULL DEFAULT '',
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "candidates" ADD COLUMN "party_id" bigint;
+
+ALTER TABLE "candidates" ADD FOREIGN KEY ("party_id") REFERENCES "parties" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "candidates" ("party_id");
+
+INSERT INTO "parties" ("name")
+SELECT DISTINCT "party" FROM "candidates" WHERE "party" <> '';
+
+UPDATE "candidates" SET "party_id" = p.id
+FROM "parties" p
+WHERE p.name = "candidates"."party";
+
+
+CREATE OR REPLACE FUNCTION candidate_profile(c candidates)
+  RETURNS jsonb AS
+$$
+  SELECT jsonb_build_object(
+    'name', c.name,
+    'dob', c.dob,
+    'bio_link', c.bio_link,
+    'image_url', c.image_url,
+    'policy', c.policy,
+    'policy_items', c.policy_items,
+    'links', c.links,
+    'party_id', c.party_id,
+    'district_id', c.district_id
+  );
+$$
+LANGUAGE 'sql' IMMUTABLE;
+
+ALTER TABLE "candidates" DROP COLUMN "party";
+
+
+ALTER TABLE "elections" ADD COLUMN "ballot_type" varchar NOT NULL DEFAULT 'CANDIDATE' CHECK ("ballot_type" IN ('CANDIDATE', 'PARTY_LIST'));
+
+ALTER TABLE "elections" ADD COLUMN "seats" integer NOT NULL DEFAULT 0 CHECK ("seats" >= 0);
+
+ALTER TABLE "elections" ADD COLUMN "seat_method" varchar NOT NULL DEFAULT 'DHONDT' CHECK ("seat_method" IN ('DHONDT', 'SAINTE_LAGUE'));
+
+
+CREATE TABLE "party_votes" (
+  "id" bigserial PRIMARY KEY,
+  "vote_national_id" varchar NOT NULL,
+  "election_id" bigint NOT NULL,
+  "party_id" bigint NOT NULL,
+  "cast_by_national_id" varchar,
+  "weight" bigint NOT NULL DEFAULT 1,
+  "superseded_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "party_votes" ADD FOREIGN KEY ("vote_national_id") REFERENCES "users" ("national_id");
+
+ALTER TABLE "party_votes" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id");
+
+ALTER TABLE "party_votes" ADD FOREIGN KEY ("party_id") REFERENCES "parties" ("id");
+
+ALTER TABLE "party_votes" ADD FOREIGN KEY ("cast_by_national_id") REFERENCES "users" ("national_id");
+
+CREATE UNIQUE INDEX "party_votes_active_national_id_key" ON "party_votes" ("vote_national_id", "election_id") WHERE "superseded_at" IS NULL;
+
+CREATE INDEX ON "party_votes" ("election_id", "party_id");
+
+
+CREATE OR REPLACE FUNCTION party_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM party_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE party_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER party_vote_supersede_trigger
+  BEFORE INSERT
+  ON "party_votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE party_vote_supersede_trigger_fnc();
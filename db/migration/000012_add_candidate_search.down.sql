@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "candidates_search_idx";
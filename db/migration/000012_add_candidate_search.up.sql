@@ -0,0 +1 @@
+CREATE INDEX "candidates_search_idx" ON "candidates" USING GIN (to_tsvector('english', "name" || ' ' || "policy"));
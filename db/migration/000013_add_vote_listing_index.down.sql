@@ -0,0 +1 @@
+DROP INDEX IF EXISTS "votes_election_id_idx";
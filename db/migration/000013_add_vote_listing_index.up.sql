@@ -0,0 +1 @@
+CREATE INDEX "votes_election_id_idx" ON "votes" ("election_id", "id");
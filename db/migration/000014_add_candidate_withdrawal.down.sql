@@ -0,0 +1,7 @@
+DELETE FROM "election_properties" WHERE "name" = 'WITHDRAW_AFTER_VOTES';
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "withdrawn_by";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "withdrawn_reason";
+
+ALTER TABLE IF EXISTS "candidates" DROP COLUMN IF EXISTS "withdrawn_at";
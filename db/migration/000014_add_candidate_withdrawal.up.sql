@@ -0,0 +1,9 @@
+ALTER TABLE "candidates" ADD COLUMN "withdrawn_at" timestamptz;
+
+ALTER TABLE "candidates" ADD COLUMN "withdrawn_reason" varchar NOT NULL DEFAULT '';
+
+ALTER TABLE "candidates" ADD COLUMN "withdrawn_by" varchar;
+
+ALTER TABLE "candidates" ADD FOREIGN KEY ("withdrawn_by") REFERENCES "users" ("national_id");
+
+INSERT INTO "election_properties" ("name", "value") VALUES ('WITHDRAW_AFTER_VOTES', 'f');
@@ -0,0 +1,3 @@
+ALTER TABLE "users" ADD COLUMN "verified_at" timestamptz;
+
+UPDATE "users" SET "verified_at" = "create_at";
@@ -0,0 +1,12 @@
+CREATE TABLE "password_resets" (
+  "id" bigserial PRIMARY KEY,
+  "national_id" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "expired_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "password_resets" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+CREATE INDEX ON "password_resets" ("national_id");
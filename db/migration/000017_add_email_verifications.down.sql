@@ -0,0 +1,3 @@
+DELETE FROM "election_properties" WHERE "name" = 'REQUIRE_VERIFIED_EMAIL';
+
+DROP TABLE IF EXISTS "email_verifications";
@@ -0,0 +1,15 @@
+CREATE TABLE "email_verifications" (
+  "id" bigserial PRIMARY KEY,
+  "national_id" varchar NOT NULL,
+  "email" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "expired_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "email_verifications" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+CREATE INDEX ON "email_verifications" ("national_id", "create_at");
+
+INSERT INTO "election_properties" ("name", "value") VALUES ('REQUIRE_VERIFIED_EMAIL', 'f');
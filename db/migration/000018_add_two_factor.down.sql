@@ -0,0 +1,7 @@
+DELETE FROM "election_properties" WHERE "name" = 'REQUIRE_MANAGER_TWO_FACTOR';
+
+DROP TABLE IF EXISTS "two_factor_challenges";
+
+DROP TABLE IF EXISTS "recovery_codes";
+
+DROP TABLE IF EXISTS "two_factors";
@@ -0,0 +1,35 @@
+CREATE TABLE "two_factors" (
+  "national_id" varchar PRIMARY KEY,
+  "secret" varchar NOT NULL,
+  "last_used_step" bigint NOT NULL DEFAULT 0,
+  "enabled_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "two_factors" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+CREATE TABLE "recovery_codes" (
+  "id" bigserial PRIMARY KEY,
+  "national_id" varchar NOT NULL,
+  "code_hash" varchar NOT NULL,
+  "used_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "recovery_codes" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX ON "recovery_codes" ("national_id", "code_hash");
+
+CREATE TABLE "two_factor_challenges" (
+  "id" bigserial PRIMARY KEY,
+  "national_id" varchar NOT NULL,
+  "token_hash" varchar UNIQUE NOT NULL,
+  "attempts" int NOT NULL DEFAULT 0,
+  "expired_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "two_factor_challenges" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+INSERT INTO "election_properties" ("name", "value") VALUES ('REQUIRE_MANAGER_TWO_FACTOR', 'f');
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS "user_audits";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "disabled_at";
@@ -0,0 +1,15 @@
+ALTER TABLE "users" ADD COLUMN "disabled_at" timestamptz;
+
+CREATE TABLE "user_audits" (
+  "id" bigserial PRIMARY KEY,
+  "actor_national_id" varchar NOT NULL,
+  "target_national_id" varchar NOT NULL,
+  "action" varchar NOT NULL,
+  "permission" varchar,
+  "reason" text NOT NULL,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "user_audits" ADD FOREIGN KEY ("actor_national_id") REFERENCES "users" ("national_id");
+
+CREATE INDEX ON "user_audits" ("target_national_id", "id");
@@ -0,0 +1,5 @@
+ALTER TABLE "user_audits" DROP COLUMN IF EXISTS "election_id";
+
+ALTER TABLE "user_audits" DROP COLUMN IF EXISTS "role";
+
+DROP TABLE IF EXISTS "user_roles";
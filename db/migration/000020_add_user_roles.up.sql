@@ -0,0 +1,21 @@
+CREATE TABLE "user_roles" (
+  "id" bigserial PRIMARY KEY,
+  "national_id" varchar NOT NULL,
+  "role" varchar NOT NULL CHECK ("role" IN ('OBSERVER', 'AUDITOR', 'CANDIDATE_MANAGER', 'ELECTION_OFFICER')),
+  "election_id" bigint,
+  "granted_by" varchar NOT NULL,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "user_roles" ADD FOREIGN KEY ("granted_by") REFERENCES "users" ("national_id");
+
+-- a role is assigned once for all elections and once per election
+CREATE UNIQUE INDEX ON "user_roles" ("national_id", "role", (COALESCE("election_id", 0)));
+
+ALTER TABLE "user_audits" ADD COLUMN "role" varchar;
+
+ALTER TABLE "user_audits" ADD COLUMN "election_id" bigint;
@@ -0,0 +1,9 @@
+CREATE TABLE "oidc_logins" (
+  "id" bigserial PRIMARY KEY,
+  "state_hash" varchar UNIQUE NOT NULL,
+  "nonce" varchar NOT NULL,
+  "code_verifier" varchar NOT NULL,
+  "expired_at" timestamptz NOT NULL,
+  "used_at" timestamptz,
+  "create_at" timestamptz NOT NULL DEFAULT (now())
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS "delegations_active_grantor_key";
+
+ALTER TABLE IF EXISTS "delegations" DROP COLUMN IF EXISTS "election_id";
+
+CREATE UNIQUE INDEX "delegations_active_grantor_key" ON "delegations" ("grantor_national_id") WHERE "status" <> 'REVOKED';
@@ -0,0 +1,9 @@
+ALTER TABLE "delegations" ADD COLUMN "election_id" bigint NOT NULL DEFAULT 1;
+
+ALTER TABLE "delegations" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id");
+
+DROP INDEX IF EXISTS "delegations_active_grantor_key";
+
+CREATE UNIQUE INDEX "delegations_active_grantor_key" ON "delegations" ("grantor_national_id", "election_id") WHERE "status" <> 'REVOKED';
+
+CREATE INDEX ON "delegations" ("proxy_national_id", "election_id");
@@ -0,0 +1 @@
+DROP FUNCTION IF EXISTS election_voting_started(bigint);
@@ -0,0 +1,14 @@
+CREATE OR REPLACE FUNCTION election_voting_started(e_id bigint)
+  RETURNS boolean AS
+$$
+  SELECT EXISTS (
+    SELECT 1 FROM votes WHERE election_id = e_id
+  ) OR EXISTS (
+    SELECT 1 FROM party_votes WHERE election_id = e_id
+  ) OR EXISTS (
+    SELECT 1 FROM measure_votes mv
+    JOIN ballot_measures m ON m.id = mv.measure_id
+    WHERE m.election_id = e_id
+  );
+$$
+LANGUAGE 'sql' STABLE;
@@ -0,0 +1,24 @@
+DROP TRIGGER IF EXISTS measure_vote_voting_started_trigger ON "measure_votes";
+
+DROP TRIGGER IF EXISTS party_vote_voting_started_trigger ON "party_votes";
+
+DROP TRIGGER IF EXISTS vote_voting_started_trigger ON "votes";
+
+DROP FUNCTION IF EXISTS election_voting_started_trigger_fnc();
+
+CREATE OR REPLACE FUNCTION election_voting_started(e_id bigint)
+  RETURNS boolean AS
+$$
+  SELECT EXISTS (
+    SELECT 1 FROM votes WHERE election_id = e_id
+  ) OR EXISTS (
+    SELECT 1 FROM party_votes WHERE election_id = e_id
+  ) OR EXISTS (
+    SELECT 1 FROM measure_votes mv
+    JOIN ballot_measures m ON m.id = mv.measure_id
+    WHERE m.election_id = e_id
+  );
+$$
+LANGUAGE 'sql' STABLE;
+
+ALTER TABLE IF EXISTS "elections" DROP COLUMN IF EXISTS "voting_started_at";
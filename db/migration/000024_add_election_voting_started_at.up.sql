@@ -0,0 +1,45 @@
+ALTER TABLE "elections" ADD COLUMN "voting_started_at" timestamptz;
+
+UPDATE "elections" SET "voting_started_at" = now() WHERE election_voting_started("id");
+
+DROP FUNCTION IF EXISTS election_voting_started(bigint);
+
+
+-- the first ballot of an election records on the election row that voting started, a candidate
+-- edit locks that row so it is ordered either before or after the first ballot
+CREATE OR REPLACE FUNCTION election_voting_started_trigger_fnc()
+  RETURNS trigger AS
+$$
+DECLARE
+  e_id bigint;
+BEGIN
+  IF TG_TABLE_NAME = 'measure_votes' THEN
+    e_id := (SELECT election_id FROM ballot_measures WHERE id = NEW."measure_id");
+  ELSE
+    e_id := NEW."election_id";
+  END IF;
+
+  UPDATE elections SET voting_started_at = now()
+  WHERE id = e_id AND voting_started_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER vote_voting_started_trigger
+  AFTER INSERT
+  ON "votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE election_voting_started_trigger_fnc();
+
+CREATE TRIGGER party_vote_voting_started_trigger
+  AFTER INSERT
+  ON "party_votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE election_voting_started_trigger_fnc();
+
+CREATE TRIGGER measure_vote_voting_started_trigger
+  AFTER INSERT
+  ON "measure_votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE election_voting_started_trigger_fnc();
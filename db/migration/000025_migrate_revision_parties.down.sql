@@ -0,0 +1 @@
+-- the migrated snapshots keep the party id, the party names are still stored in parties
@@ -0,0 +1,11 @@
+-- snapshots taken before parties were added hold the party name, the name is replaced
+-- by the id of that party so rolling back to such a snapshot keeps the party
+INSERT INTO "parties" ("name")
+SELECT DISTINCT "profile"->>'party' FROM "candidate_revisions"
+WHERE "profile" ? 'party' AND "profile"->>'party' <> ''
+ON CONFLICT ("name") DO NOTHING;
+
+UPDATE "candidate_revisions" SET "profile" = ("profile" - 'party') || jsonb_build_object(
+  'party_id', (SELECT p.id FROM "parties" p WHERE p.name = "profile"->>'party')
+)
+WHERE "profile" ? 'party';
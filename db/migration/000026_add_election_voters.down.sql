@@ -0,0 +1,162 @@
+ALTER TABLE "users" ADD COLUMN "vote_weight" bigint NOT NULL DEFAULT 1 CHECK ("vote_weight" > 0);
+
+UPDATE "users" SET "vote_weight" = ev."vote_weight"
+FROM "election_voters" ev
+WHERE ev."election_id" = 1 AND ev."national_id" = "users"."national_id";
+
+
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  NEW."election_id" := (SELECT election_id FROM candidates WHERE id = NEW."candidate_id");
+
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    	(
+    		tally.vote_count/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  ), weighted_vote_count = tally.weighted_vote_count, weighted_percentage = (
+    	(
+    		tally.weighted_vote_count/
+    		(select SUM(vote_weight) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  )
+	FROM (
+		SELECT c.id, (
+			select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS vote_count, (
+			select COALESCE(SUM(v.weight), 0) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS weighted_vote_count
+		FROM candidates c
+		WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = NEW."vote_national_id")
+	) AS tally
+	WHERE candidates.id = tally.id;
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION measure_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM measure_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted on measure %', NEW."vote_national_id", NEW."measure_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE measure_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION party_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  IF EXISTS (
+    SELECT 1 FROM party_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := (SELECT vote_weight FROM users WHERE national_id = NEW."vote_national_id");
+
+  UPDATE party_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+DROP FUNCTION IF EXISTS start_election_voting(bigint);
+
+CREATE OR REPLACE FUNCTION election_voting_started_trigger_fnc()
+  RETURNS trigger AS
+$$
+DECLARE
+  e_id bigint;
+BEGIN
+  IF TG_TABLE_NAME = 'measure_votes' THEN
+    e_id := (SELECT election_id FROM ballot_measures WHERE id = NEW."measure_id");
+  ELSE
+    e_id := NEW."election_id";
+  END IF;
+
+  UPDATE elections SET voting_started_at = now()
+  WHERE id = e_id AND voting_started_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+CREATE TRIGGER vote_voting_started_trigger
+  AFTER INSERT
+  ON "votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE election_voting_started_trigger_fnc();
+
+CREATE TRIGGER party_vote_voting_started_trigger
+  AFTER INSERT
+  ON "party_votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE election_voting_started_trigger_fnc();
+
+CREATE TRIGGER measure_vote_voting_started_trigger
+  AFTER INSERT
+  ON "measure_votes"
+  FOR EACH ROW
+  EXECUTE PROCEDURE election_voting_started_trigger_fnc();
+
+
+DROP FUNCTION IF EXISTS election_total_weight(bigint);
+
+DROP FUNCTION IF EXISTS voter_weight(bigint, varchar);
+
+DROP TABLE IF EXISTS "election_voters";
@@ -0,0 +1,179 @@
+CREATE TABLE "election_voters" (
+  "election_id" bigint NOT NULL,
+  "national_id" varchar NOT NULL,
+  "vote_weight" bigint NOT NULL DEFAULT 1 CHECK ("vote_weight" > 0),
+  "create_at" timestamptz NOT NULL DEFAULT (now()),
+  PRIMARY KEY ("election_id", "national_id")
+);
+
+ALTER TABLE "election_voters" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "election_voters" ADD FOREIGN KEY ("national_id") REFERENCES "users" ("national_id") ON DELETE CASCADE;
+
+CREATE INDEX ON "election_voters" ("national_id");
+
+INSERT INTO "election_voters" ("election_id", "national_id", "vote_weight")
+SELECT e."id", u."national_id", u."vote_weight"
+FROM "elections" e CROSS JOIN "users" u
+WHERE u."vote_weight" <> 1;
+
+ALTER TABLE "users" DROP COLUMN "vote_weight";
+
+
+-- a voter without a weight in an election votes with weight 1
+CREATE OR REPLACE FUNCTION voter_weight(e_id bigint, n_id varchar)
+  RETURNS bigint AS
+$$
+  SELECT COALESCE((
+    SELECT vote_weight FROM election_voters
+    WHERE election_id = e_id AND national_id = n_id
+  ), 1);
+$$
+LANGUAGE 'sql' STABLE;
+
+CREATE OR REPLACE FUNCTION election_total_weight(e_id bigint)
+  RETURNS bigint AS
+$$
+  SELECT COALESCE(SUM(voter_weight(e_id, national_id)), 0)::bigint FROM users
+  WHERE 'VOTE'=ANY("permission");
+$$
+LANGUAGE 'sql' STABLE;
+
+
+-- voting starts before the weight of the first ballot is read, a weight change locks the
+-- election row so it either lands before that ballot or is refused
+DROP TRIGGER IF EXISTS vote_voting_started_trigger ON "votes";
+
+DROP TRIGGER IF EXISTS party_vote_voting_started_trigger ON "party_votes";
+
+DROP TRIGGER IF EXISTS measure_vote_voting_started_trigger ON "measure_votes";
+
+DROP FUNCTION IF EXISTS election_voting_started_trigger_fnc();
+
+CREATE OR REPLACE FUNCTION start_election_voting(e_id bigint)
+  RETURNS void AS
+$$
+  UPDATE elections SET voting_started_at = now()
+  WHERE id = e_id AND voting_started_at IS NULL;
+$$
+LANGUAGE 'sql';
+
+
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  NEW."election_id" := (SELECT election_id FROM candidates WHERE id = NEW."candidate_id");
+
+  PERFORM start_election_voting(NEW."election_id");
+
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(NEW."election_id", NEW."vote_national_id");
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    	(
+    		tally.vote_count/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  ), weighted_vote_count = tally.weighted_vote_count, weighted_percentage = (
+    	(
+    		tally.weighted_vote_count/
+    		election_total_weight(candidates.election_id)::float
+    	)*100
+  )
+	FROM (
+		SELECT c.id, (
+			select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS vote_count, (
+			select COALESCE(SUM(v.weight), 0) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS weighted_vote_count
+		FROM candidates c
+		WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = NEW."vote_national_id")
+	) AS tally
+	WHERE candidates.id = tally.id;
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION measure_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+DECLARE
+  e_id bigint;
+BEGIN
+  e_id := (SELECT election_id FROM ballot_measures WHERE id = NEW."measure_id");
+
+  PERFORM start_election_voting(e_id);
+
+  IF EXISTS (
+    SELECT 1 FROM measure_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted on measure %', NEW."vote_national_id", NEW."measure_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(e_id, NEW."vote_national_id");
+
+  UPDATE measure_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION party_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  PERFORM start_election_voting(NEW."election_id");
+
+  IF EXISTS (
+    SELECT 1 FROM party_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(NEW."election_id", NEW."vote_national_id");
+
+  UPDATE party_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
@@ -0,0 +1,104 @@
+ALTER TABLE "users" ADD COLUMN "has_voted" boolean NOT NULL DEFAULT false;
+
+UPDATE "users" SET "has_voted" = true
+WHERE EXISTS (
+  SELECT 1 FROM "election_voters" ev
+  WHERE ev."national_id" = "users"."national_id" AND ev."voted_at" IS NOT NULL
+);
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    	(
+    		tally.vote_count/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  ), weighted_vote_count = tally.weighted_vote_count, weighted_percentage = (
+    	(
+    		tally.weighted_vote_count/
+    		election_total_weight(candidates.election_id)::float
+    	)*100
+  )
+	FROM (
+		SELECT c.id, (
+			select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS vote_count, (
+			select COALESCE(SUM(v.weight), 0) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS weighted_vote_count
+		FROM candidates c
+		WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = NEW."vote_national_id")
+	) AS tally
+	WHERE candidates.id = tally.id;
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION measure_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+DECLARE
+  e_id bigint;
+BEGIN
+  e_id := (SELECT election_id FROM ballot_measures WHERE id = NEW."measure_id");
+
+  PERFORM start_election_voting(e_id);
+
+  IF EXISTS (
+    SELECT 1 FROM measure_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted on measure %', NEW."vote_national_id", NEW."measure_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(e_id, NEW."vote_national_id");
+
+  UPDATE measure_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION party_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  PERFORM start_election_voting(NEW."election_id");
+
+  IF EXISTS (
+    SELECT 1 FROM party_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(NEW."election_id", NEW."vote_national_id");
+
+  UPDATE party_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+
+  UPDATE users SET has_voted = 't'
+  WHERE national_id = NEW."vote_national_id";
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+DROP FUNCTION IF EXISTS election_voter_voted(bigint, varchar);
+
+ALTER TABLE IF EXISTS "election_voters" DROP COLUMN IF EXISTS "voted_at";
@@ -0,0 +1,121 @@
+ALTER TABLE "election_voters" ADD COLUMN "voted_at" timestamptz;
+
+INSERT INTO "election_voters" ("election_id", "national_id", "voted_at")
+SELECT b."election_id", b."vote_national_id", MIN(b."create_at") FROM (
+  SELECT "election_id", "vote_national_id", "create_at" FROM "votes"
+  WHERE "superseded_at" IS NULL
+  UNION ALL
+  SELECT "election_id", "vote_national_id", "create_at" FROM "party_votes"
+  WHERE "superseded_at" IS NULL
+  UNION ALL
+  SELECT m."election_id", mv."vote_national_id", mv."create_at" FROM "measure_votes" mv
+  JOIN "ballot_measures" m ON m."id" = mv."measure_id"
+  WHERE mv."superseded_at" IS NULL
+) b
+GROUP BY b."election_id", b."vote_national_id"
+ON CONFLICT ("election_id", "national_id") DO UPDATE SET "voted_at" = EXCLUDED."voted_at";
+
+ALTER TABLE "users" DROP COLUMN "has_voted";
+
+
+-- a voter has voted in an election, not in every election
+CREATE OR REPLACE FUNCTION election_voter_voted(e_id bigint, n_id varchar)
+  RETURNS void AS
+$$
+  INSERT INTO election_voters (election_id, national_id, voted_at)
+  VALUES (e_id, n_id, now())
+  ON CONFLICT (election_id, national_id) DO UPDATE SET voted_at = now();
+$$
+LANGUAGE 'sql';
+
+
+CREATE OR REPLACE FUNCTION vote_event_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+	UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    	(
+    		tally.vote_count/
+    		(select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    	)*100
+  ), weighted_vote_count = tally.weighted_vote_count, weighted_percentage = (
+    	(
+    		tally.weighted_vote_count/
+    		election_total_weight(candidates.election_id)::float
+    	)*100
+  )
+	FROM (
+		SELECT c.id, (
+			select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS vote_count, (
+			select COALESCE(SUM(v.weight), 0) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+		) AS weighted_vote_count
+		FROM candidates c
+		WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = NEW."vote_national_id")
+	) AS tally
+	WHERE candidates.id = tally.id;
+  PERFORM election_voter_voted(NEW."election_id", NEW."vote_national_id");
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION measure_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+DECLARE
+  e_id bigint;
+BEGIN
+  e_id := (SELECT election_id FROM ballot_measures WHERE id = NEW."measure_id");
+
+  PERFORM start_election_voting(e_id);
+
+  IF EXISTS (
+    SELECT 1 FROM measure_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted on measure %', NEW."vote_national_id", NEW."measure_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(e_id, NEW."vote_national_id");
+
+  UPDATE measure_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND measure_id = NEW."measure_id" AND superseded_at IS NULL;
+
+  PERFORM election_voter_voted(e_id, NEW."vote_national_id");
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+CREATE OR REPLACE FUNCTION party_vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  PERFORM start_election_voting(NEW."election_id");
+
+  IF EXISTS (
+    SELECT 1 FROM party_votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(NEW."election_id", NEW."vote_national_id");
+
+  UPDATE party_votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+
+  PERFORM election_voter_voted(NEW."election_id", NEW."vote_national_id");
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
@@ -0,0 +1,41 @@
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  NEW."election_id" := (SELECT election_id FROM candidates WHERE id = NEW."candidate_id");
+
+  PERFORM start_election_voting(NEW."election_id");
+
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(NEW."election_id", NEW."vote_national_id");
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
+
+
+-- only the vote of the main race is kept active, the other contests are superseded
+UPDATE "votes" SET "superseded_at" = now()
+WHERE "contest_id" IS NOT NULL AND "superseded_at" IS NULL;
+
+DROP INDEX IF EXISTS "votes_active_national_id_key";
+
+CREATE UNIQUE INDEX "votes_active_national_id_key" ON "votes" ("vote_national_id", "election_id") WHERE "superseded_at" IS NULL;
+
+ALTER TABLE "votes" DROP COLUMN IF EXISTS "contest_id";
+
+ALTER TABLE "candidates" DROP COLUMN IF EXISTS "contest_id";
+
+DROP TABLE IF EXISTS "contests";
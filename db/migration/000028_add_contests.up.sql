@@ -0,0 +1,56 @@
+CREATE TABLE "contests" (
+  "id" bigserial PRIMARY KEY,
+  "election_id" bigint NOT NULL,
+  "name" varchar NOT NULL,
+  "create_at" timestamptz NOT NULL DEFAULT (now()),
+  UNIQUE ("election_id", "name")
+);
+
+ALTER TABLE "contests" ADD FOREIGN KEY ("election_id") REFERENCES "elections" ("id");
+
+-- a candidate without a contest runs in the main race of the election
+ALTER TABLE "candidates" ADD COLUMN "contest_id" bigint;
+
+ALTER TABLE "candidates" ADD FOREIGN KEY ("contest_id") REFERENCES "contests" ("id");
+
+CREATE INDEX ON "candidates" ("contest_id");
+
+ALTER TABLE "votes" ADD COLUMN "contest_id" bigint;
+
+ALTER TABLE "votes" ADD FOREIGN KEY ("contest_id") REFERENCES "contests" ("id");
+
+DROP INDEX IF EXISTS "votes_active_national_id_key";
+
+CREATE UNIQUE INDEX "votes_active_national_id_key" ON "votes" ("vote_national_id", "election_id", COALESCE("contest_id", 0)) WHERE "superseded_at" IS NULL;
+
+
+-- a voter has one counted vote per contest of an election
+CREATE OR REPLACE FUNCTION vote_supersede_trigger_fnc()
+  RETURNS trigger AS
+$$
+BEGIN
+  SELECT election_id, contest_id INTO NEW."election_id", NEW."contest_id"
+  FROM candidates WHERE id = NEW."candidate_id";
+
+  PERFORM start_election_voting(NEW."election_id");
+
+  IF EXISTS (
+    SELECT 1 FROM votes
+    WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id"
+      AND contest_id IS NOT DISTINCT FROM NEW."contest_id" AND superseded_at IS NULL
+  ) AND NOT (
+    SELECT value FROM election_properties WHERE name = 'REVOTE_ENABLED'
+  ) THEN
+    RAISE EXCEPTION 'national id % has already voted', NEW."vote_national_id"
+      USING ERRCODE = 'unique_violation';
+  END IF;
+
+  NEW."weight" := voter_weight(NEW."election_id", NEW."vote_national_id");
+
+  UPDATE votes SET superseded_at = now()
+  WHERE vote_national_id = NEW."vote_national_id" AND election_id = NEW."election_id"
+    AND contest_id IS NOT DISTINCT FROM NEW."contest_id" AND superseded_at IS NULL;
+RETURN NEW;
+END;
+$$
+LANGUAGE 'plpgsql';
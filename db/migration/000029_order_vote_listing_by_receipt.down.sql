@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS "votes_election_id_idx";
+
+CREATE INDEX "votes_election_id_idx" ON "votes" ("election_id", "id");
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS "votes_election_id_idx";
+
+-- the public receipt list is ordered by hash, so its order does not reveal when a ballot was cast
+CREATE INDEX "votes_election_id_idx" ON "votes" ("election_id", "receipt_hash");
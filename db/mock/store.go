@@ -6,8 +6,10 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	db "election/db/sqlc"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,153 +37,1582 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddUserPermission mocks base method.
+func (m *MockStore) AddUserPermission(arg0 context.Context, arg1 db.AddUserPermissionParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddUserPermission", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddUserPermission indicates an expected call of AddUserPermission.
+func (mr *MockStoreMockRecorder) AddUserPermission(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUserPermission", reflect.TypeOf((*MockStore)(nil).AddUserPermission), arg0, arg1)
+}
+
+// AdminUpdateUserTx mocks base method.
+func (m *MockStore) AdminUpdateUserTx(arg0 context.Context, arg1 db.AdminUpdateUserTxParams) (db.AdminUpdateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdminUpdateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AdminUpdateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdminUpdateUserTx indicates an expected call of AdminUpdateUserTx.
+func (mr *MockStoreMockRecorder) AdminUpdateUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdminUpdateUserTx", reflect.TypeOf((*MockStore)(nil).AdminUpdateUserTx), arg0, arg1)
+}
+
+// ApproveDelegation mocks base method.
+func (m *MockStore) ApproveDelegation(arg0 context.Context, arg1 db.ApproveDelegationParams) (db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveDelegation", arg0, arg1)
+	ret0, _ := ret[0].(db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveDelegation indicates an expected call of ApproveDelegation.
+func (mr *MockStoreMockRecorder) ApproveDelegation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDelegation", reflect.TypeOf((*MockStore)(nil).ApproveDelegation), arg0, arg1)
+}
+
+// ApproveDelegationTx mocks base method.
+func (m *MockStore) ApproveDelegationTx(arg0 context.Context, arg1 db.ApproveDelegationTxParams) (db.ApproveDelegationTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApproveDelegationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ApproveDelegationTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApproveDelegationTx indicates an expected call of ApproveDelegationTx.
+func (mr *MockStoreMockRecorder) ApproveDelegationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApproveDelegationTx", reflect.TypeOf((*MockStore)(nil).ApproveDelegationTx), arg0, arg1)
+}
+
+// AttemptTwoFactorChallenge mocks base method.
+func (m *MockStore) AttemptTwoFactorChallenge(arg0 context.Context, arg1 string) (db.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttemptTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttemptTwoFactorChallenge indicates an expected call of AttemptTwoFactorChallenge.
+func (mr *MockStoreMockRecorder) AttemptTwoFactorChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttemptTwoFactorChallenge", reflect.TypeOf((*MockStore)(nil).AttemptTwoFactorChallenge), arg0, arg1)
+}
+
+// CastBallotTx mocks base method.
+func (m *MockStore) CastBallotTx(arg0 context.Context, arg1 db.CastBallotTxParams) (db.CastBallotTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CastBallotTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CastBallotTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CastBallotTx indicates an expected call of CastBallotTx.
+func (mr *MockStoreMockRecorder) CastBallotTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CastBallotTx", reflect.TypeOf((*MockStore)(nil).CastBallotTx), arg0, arg1)
+}
+
+// CloseElection mocks base method.
+func (m *MockStore) CloseElection(arg0 context.Context, arg1 int64) (db.Election, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseElection", arg0, arg1)
+	ret0, _ := ret[0].(db.Election)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseElection indicates an expected call of CloseElection.
+func (mr *MockStoreMockRecorder) CloseElection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseElection", reflect.TypeOf((*MockStore)(nil).CloseElection), arg0, arg1)
+}
+
+// CopyElectionVoters mocks base method.
+func (m *MockStore) CopyElectionVoters(arg0 context.Context, arg1 db.CopyElectionVotersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyElectionVoters", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyElectionVoters indicates an expected call of CopyElectionVoters.
+func (mr *MockStoreMockRecorder) CopyElectionVoters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyElectionVoters", reflect.TypeOf((*MockStore)(nil).CopyElectionVoters), arg0, arg1)
+}
+
+// CountActiveVotes mocks base method.
+func (m *MockStore) CountActiveVotes(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountActiveVotes", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountActiveVotes indicates an expected call of CountActiveVotes.
+func (mr *MockStoreMockRecorder) CountActiveVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountActiveVotes", reflect.TypeOf((*MockStore)(nil).CountActiveVotes), arg0, arg1)
+}
+
+// CountCandidates mocks base method.
+func (m *MockStore) CountCandidates(arg0 context.Context, arg1 db.CountCandidatesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCandidates", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCandidates indicates an expected call of CountCandidates.
+func (mr *MockStoreMockRecorder) CountCandidates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCandidates", reflect.TypeOf((*MockStore)(nil).CountCandidates), arg0, arg1)
+}
+
+// CountElectionBallotMeasures mocks base method.
+func (m *MockStore) CountElectionBallotMeasures(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountElectionBallotMeasures", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountElectionBallotMeasures indicates an expected call of CountElectionBallotMeasures.
+func (mr *MockStoreMockRecorder) CountElectionBallotMeasures(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountElectionBallotMeasures", reflect.TypeOf((*MockStore)(nil).CountElectionBallotMeasures), arg0, arg1)
+}
+
+// CountElectionContests mocks base method.
+func (m *MockStore) CountElectionContests(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountElectionContests", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountElectionContests indicates an expected call of CountElectionContests.
+func (mr *MockStoreMockRecorder) CountElectionContests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountElectionContests", reflect.TypeOf((*MockStore)(nil).CountElectionContests), arg0, arg1)
+}
+
+// CountEligibleVoters mocks base method.
+func (m *MockStore) CountEligibleVoters(arg0 context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEligibleVoters", arg0)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEligibleVoters indicates an expected call of CountEligibleVoters.
+func (mr *MockStoreMockRecorder) CountEligibleVoters(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEligibleVoters", reflect.TypeOf((*MockStore)(nil).CountEligibleVoters), arg0)
+}
+
+// CountProxyDelegations mocks base method.
+func (m *MockStore) CountProxyDelegations(arg0 context.Context, arg1 db.CountProxyDelegationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountProxyDelegations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountProxyDelegations indicates an expected call of CountProxyDelegations.
+func (mr *MockStoreMockRecorder) CountProxyDelegations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountProxyDelegations", reflect.TypeOf((*MockStore)(nil).CountProxyDelegations), arg0, arg1)
+}
+
+// CreateBallotMeasure mocks base method.
+func (m *MockStore) CreateBallotMeasure(arg0 context.Context, arg1 db.CreateBallotMeasureParams) (db.BallotMeasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBallotMeasure", arg0, arg1)
+	ret0, _ := ret[0].(db.BallotMeasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBallotMeasure indicates an expected call of CreateBallotMeasure.
+func (mr *MockStoreMockRecorder) CreateBallotMeasure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBallotMeasure", reflect.TypeOf((*MockStore)(nil).CreateBallotMeasure), arg0, arg1)
+}
+
+// CreateBallotMeasureOption mocks base method.
+func (m *MockStore) CreateBallotMeasureOption(arg0 context.Context, arg1 db.CreateBallotMeasureOptionParams) (db.BallotMeasureOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBallotMeasureOption", arg0, arg1)
+	ret0, _ := ret[0].(db.BallotMeasureOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBallotMeasureOption indicates an expected call of CreateBallotMeasureOption.
+func (mr *MockStoreMockRecorder) CreateBallotMeasureOption(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBallotMeasureOption", reflect.TypeOf((*MockStore)(nil).CreateBallotMeasureOption), arg0, arg1)
+}
+
+// CreateBallotMeasureTx mocks base method.
+func (m *MockStore) CreateBallotMeasureTx(arg0 context.Context, arg1 db.CreateBallotMeasureTxParams) (db.CreateBallotMeasureTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBallotMeasureTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateBallotMeasureTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBallotMeasureTx indicates an expected call of CreateBallotMeasureTx.
+func (mr *MockStoreMockRecorder) CreateBallotMeasureTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBallotMeasureTx", reflect.TypeOf((*MockStore)(nil).CreateBallotMeasureTx), arg0, arg1)
+}
+
 // CreateCandidate mocks base method.
 func (m *MockStore) CreateCandidate(arg0 context.Context, arg1 db.CreateCandidateParams) (db.Candidate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateCandidate", arg0, arg1)
-	ret0, _ := ret[0].(db.Candidate)
+	ret := m.ctrl.Call(m, "CreateCandidate", arg0, arg1)
+	ret0, _ := ret[0].(db.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCandidate indicates an expected call of CreateCandidate.
+func (mr *MockStoreMockRecorder) CreateCandidate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCandidate", reflect.TypeOf((*MockStore)(nil).CreateCandidate), arg0, arg1)
+}
+
+// CreateContest mocks base method.
+func (m *MockStore) CreateContest(arg0 context.Context, arg1 db.CreateContestParams) (db.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContest", arg0, arg1)
+	ret0, _ := ret[0].(db.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateContest indicates an expected call of CreateContest.
+func (mr *MockStoreMockRecorder) CreateContest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContest", reflect.TypeOf((*MockStore)(nil).CreateContest), arg0, arg1)
+}
+
+// CreateDelegation mocks base method.
+func (m *MockStore) CreateDelegation(arg0 context.Context, arg1 db.CreateDelegationParams) (db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDelegation", arg0, arg1)
+	ret0, _ := ret[0].(db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDelegation indicates an expected call of CreateDelegation.
+func (mr *MockStoreMockRecorder) CreateDelegation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDelegation", reflect.TypeOf((*MockStore)(nil).CreateDelegation), arg0, arg1)
+}
+
+// CreateDistrict mocks base method.
+func (m *MockStore) CreateDistrict(arg0 context.Context, arg1 string) (db.District, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDistrict", arg0, arg1)
+	ret0, _ := ret[0].(db.District)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDistrict indicates an expected call of CreateDistrict.
+func (mr *MockStoreMockRecorder) CreateDistrict(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDistrict", reflect.TypeOf((*MockStore)(nil).CreateDistrict), arg0, arg1)
+}
+
+// CreateElection mocks base method.
+func (m *MockStore) CreateElection(arg0 context.Context, arg1 db.CreateElectionParams) (db.Election, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateElection", arg0, arg1)
+	ret0, _ := ret[0].(db.Election)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateElection indicates an expected call of CreateElection.
+func (mr *MockStoreMockRecorder) CreateElection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateElection", reflect.TypeOf((*MockStore)(nil).CreateElection), arg0, arg1)
+}
+
+// CreateEmailVerification mocks base method.
+func (m *MockStore) CreateEmailVerification(arg0 context.Context, arg1 db.CreateEmailVerificationParams) (db.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(db.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateEmailVerification indicates an expected call of CreateEmailVerification.
+func (mr *MockStoreMockRecorder) CreateEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEmailVerification", reflect.TypeOf((*MockStore)(nil).CreateEmailVerification), arg0, arg1)
+}
+
+// CreateMeasureVote mocks base method.
+func (m *MockStore) CreateMeasureVote(arg0 context.Context, arg1 db.CreateMeasureVoteParams) (db.MeasureVote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMeasureVote", arg0, arg1)
+	ret0, _ := ret[0].(db.MeasureVote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMeasureVote indicates an expected call of CreateMeasureVote.
+func (mr *MockStoreMockRecorder) CreateMeasureVote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMeasureVote", reflect.TypeOf((*MockStore)(nil).CreateMeasureVote), arg0, arg1)
+}
+
+// CreateOIDCLogin mocks base method.
+func (m *MockStore) CreateOIDCLogin(arg0 context.Context, arg1 db.CreateOIDCLoginParams) (db.OidcLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOIDCLogin", arg0, arg1)
+	ret0, _ := ret[0].(db.OidcLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOIDCLogin indicates an expected call of CreateOIDCLogin.
+func (mr *MockStoreMockRecorder) CreateOIDCLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOIDCLogin", reflect.TypeOf((*MockStore)(nil).CreateOIDCLogin), arg0, arg1)
+}
+
+// CreateParty mocks base method.
+func (m *MockStore) CreateParty(arg0 context.Context, arg1 db.CreatePartyParams) (db.Party, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateParty", arg0, arg1)
+	ret0, _ := ret[0].(db.Party)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateParty indicates an expected call of CreateParty.
+func (mr *MockStoreMockRecorder) CreateParty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateParty", reflect.TypeOf((*MockStore)(nil).CreateParty), arg0, arg1)
+}
+
+// CreatePartyVote mocks base method.
+func (m *MockStore) CreatePartyVote(arg0 context.Context, arg1 db.CreatePartyVoteParams) (db.PartyVote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePartyVote", arg0, arg1)
+	ret0, _ := ret[0].(db.PartyVote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePartyVote indicates an expected call of CreatePartyVote.
+func (mr *MockStoreMockRecorder) CreatePartyVote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePartyVote", reflect.TypeOf((*MockStore)(nil).CreatePartyVote), arg0, arg1)
+}
+
+// CreatePasswordReset mocks base method.
+func (m *MockStore) CreatePasswordReset(arg0 context.Context, arg1 db.CreatePasswordResetParams) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePasswordReset indicates an expected call of CreatePasswordReset.
+func (mr *MockStoreMockRecorder) CreatePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePasswordReset", reflect.TypeOf((*MockStore)(nil).CreatePasswordReset), arg0, arg1)
+}
+
+// CreateRecoveryCode mocks base method.
+func (m *MockStore) CreateRecoveryCode(arg0 context.Context, arg1 db.CreateRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecoveryCode indicates an expected call of CreateRecoveryCode.
+func (mr *MockStoreMockRecorder) CreateRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecoveryCode", reflect.TypeOf((*MockStore)(nil).CreateRecoveryCode), arg0, arg1)
+}
+
+// CreateRunoffCandidate mocks base method.
+func (m *MockStore) CreateRunoffCandidate(arg0 context.Context, arg1 db.CreateRunoffCandidateParams) (db.Candidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRunoffCandidate", arg0, arg1)
+	ret0, _ := ret[0].(db.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRunoffCandidate indicates an expected call of CreateRunoffCandidate.
+func (mr *MockStoreMockRecorder) CreateRunoffCandidate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRunoffCandidate", reflect.TypeOf((*MockStore)(nil).CreateRunoffCandidate), arg0, arg1)
+}
+
+// CreateRunoffTx mocks base method.
+func (m *MockStore) CreateRunoffTx(arg0 context.Context, arg1 db.CreateRunoffTxParams) (db.CreateRunoffTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRunoffTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateRunoffTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRunoffTx indicates an expected call of CreateRunoffTx.
+func (mr *MockStoreMockRecorder) CreateRunoffTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRunoffTx", reflect.TypeOf((*MockStore)(nil).CreateRunoffTx), arg0, arg1)
+}
+
+// CreateTwoFactorChallenge mocks base method.
+func (m *MockStore) CreateTwoFactorChallenge(arg0 context.Context, arg1 db.CreateTwoFactorChallengeParams) (db.TwoFactorChallenge, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactorChallenge)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTwoFactorChallenge indicates an expected call of CreateTwoFactorChallenge.
+func (mr *MockStoreMockRecorder) CreateTwoFactorChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTwoFactorChallenge", reflect.TypeOf((*MockStore)(nil).CreateTwoFactorChallenge), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserAudit mocks base method.
+func (m *MockStore) CreateUserAudit(arg0 context.Context, arg1 db.CreateUserAuditParams) (db.UserAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserAudit", arg0, arg1)
+	ret0, _ := ret[0].(db.UserAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserAudit indicates an expected call of CreateUserAudit.
+func (mr *MockStoreMockRecorder) CreateUserAudit(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserAudit", reflect.TypeOf((*MockStore)(nil).CreateUserAudit), arg0, arg1)
+}
+
+// CreateUserRole mocks base method.
+func (m *MockStore) CreateUserRole(arg0 context.Context, arg1 db.CreateUserRoleParams) (db.UserRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.UserRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserRole indicates an expected call of CreateUserRole.
+func (mr *MockStoreMockRecorder) CreateUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserRole", reflect.TypeOf((*MockStore)(nil).CreateUserRole), arg0, arg1)
+}
+
+// CreateVote mocks base method.
+func (m *MockStore) CreateVote(arg0 context.Context, arg1 db.CreateVoteParams) (db.Vote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVote", arg0, arg1)
+	ret0, _ := ret[0].(db.Vote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVote indicates an expected call of CreateVote.
+func (mr *MockStoreMockRecorder) CreateVote(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVote", reflect.TypeOf((*MockStore)(nil).CreateVote), arg0, arg1)
+}
+
+// DBStats mocks base method.
+func (m *MockStore) DBStats() sql.DBStats {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DBStats")
+	ret0, _ := ret[0].(sql.DBStats)
+	return ret0
+}
+
+// DBStats indicates an expected call of DBStats.
+func (mr *MockStoreMockRecorder) DBStats() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DBStats", reflect.TypeOf((*MockStore)(nil).DBStats))
+}
+
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStore) DeleteRecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStoreMockRecorder) DeleteRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStore)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
+// DeleteTwoFactor mocks base method.
+func (m *MockStore) DeleteTwoFactor(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTwoFactor indicates an expected call of DeleteTwoFactor.
+func (mr *MockStoreMockRecorder) DeleteTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTwoFactor", reflect.TypeOf((*MockStore)(nil).DeleteTwoFactor), arg0, arg1)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStore) DeleteUser(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStoreMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStore)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteUserDelegations mocks base method.
+func (m *MockStore) DeleteUserDelegations(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserDelegations", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserDelegations indicates an expected call of DeleteUserDelegations.
+func (mr *MockStoreMockRecorder) DeleteUserDelegations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserDelegations", reflect.TypeOf((*MockStore)(nil).DeleteUserDelegations), arg0, arg1)
+}
+
+// DeleteUserRole mocks base method.
+func (m *MockStore) DeleteUserRole(arg0 context.Context, arg1 db.DeleteUserRoleParams) (db.UserRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserRole", arg0, arg1)
+	ret0, _ := ret[0].(db.UserRole)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteUserRole indicates an expected call of DeleteUserRole.
+func (mr *MockStoreMockRecorder) DeleteUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserRole", reflect.TypeOf((*MockStore)(nil).DeleteUserRole), arg0, arg1)
+}
+
+// DeleteUserTx mocks base method.
+func (m *MockStore) DeleteUserTx(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserTx indicates an expected call of DeleteUserTx.
+func (mr *MockStoreMockRecorder) DeleteUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserTx", reflect.TypeOf((*MockStore)(nil).DeleteUserTx), arg0, arg1)
+}
+
+// DisableTwoFactorTx mocks base method.
+func (m *MockStore) DisableTwoFactorTx(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableTwoFactorTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableTwoFactorTx indicates an expected call of DisableTwoFactorTx.
+func (mr *MockStoreMockRecorder) DisableTwoFactorTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableTwoFactorTx", reflect.TypeOf((*MockStore)(nil).DisableTwoFactorTx), arg0, arg1)
+}
+
+// DisableUser mocks base method.
+func (m *MockStore) DisableUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DisableUser indicates an expected call of DisableUser.
+func (mr *MockStoreMockRecorder) DisableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableUser", reflect.TypeOf((*MockStore)(nil).DisableUser), arg0, arg1)
+}
+
+// EnableTwoFactor mocks base method.
+func (m *MockStore) EnableTwoFactor(arg0 context.Context, arg1 string) (db.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTwoFactor indicates an expected call of EnableTwoFactor.
+func (mr *MockStoreMockRecorder) EnableTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTwoFactor", reflect.TypeOf((*MockStore)(nil).EnableTwoFactor), arg0, arg1)
+}
+
+// EnableTwoFactorTx mocks base method.
+func (m *MockStore) EnableTwoFactorTx(arg0 context.Context, arg1 db.EnableTwoFactorTxParams) (db.EnableTwoFactorTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableTwoFactorTx", arg0, arg1)
+	ret0, _ := ret[0].(db.EnableTwoFactorTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableTwoFactorTx indicates an expected call of EnableTwoFactorTx.
+func (mr *MockStoreMockRecorder) EnableTwoFactorTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableTwoFactorTx", reflect.TypeOf((*MockStore)(nil).EnableTwoFactorTx), arg0, arg1)
+}
+
+// EnableUser mocks base method.
+func (m *MockStore) EnableUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EnableUser indicates an expected call of EnableUser.
+func (mr *MockStoreMockRecorder) EnableUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableUser", reflect.TypeOf((*MockStore)(nil).EnableUser), arg0, arg1)
+}
+
+// GetApprovedDelegation mocks base method.
+func (m *MockStore) GetApprovedDelegation(arg0 context.Context, arg1 db.GetApprovedDelegationParams) (db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovedDelegation", arg0, arg1)
+	ret0, _ := ret[0].(db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovedDelegation indicates an expected call of GetApprovedDelegation.
+func (mr *MockStoreMockRecorder) GetApprovedDelegation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovedDelegation", reflect.TypeOf((*MockStore)(nil).GetApprovedDelegation), arg0, arg1)
+}
+
+// GetBallotMeasure mocks base method.
+func (m *MockStore) GetBallotMeasure(arg0 context.Context, arg1 int64) (db.BallotMeasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBallotMeasure", arg0, arg1)
+	ret0, _ := ret[0].(db.BallotMeasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBallotMeasure indicates an expected call of GetBallotMeasure.
+func (mr *MockStoreMockRecorder) GetBallotMeasure(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBallotMeasure", reflect.TypeOf((*MockStore)(nil).GetBallotMeasure), arg0, arg1)
+}
+
+// GetBallotMeasureOption mocks base method.
+func (m *MockStore) GetBallotMeasureOption(arg0 context.Context, arg1 int64) (db.GetBallotMeasureOptionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBallotMeasureOption", arg0, arg1)
+	ret0, _ := ret[0].(db.GetBallotMeasureOptionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBallotMeasureOption indicates an expected call of GetBallotMeasureOption.
+func (mr *MockStoreMockRecorder) GetBallotMeasureOption(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBallotMeasureOption", reflect.TypeOf((*MockStore)(nil).GetBallotMeasureOption), arg0, arg1)
+}
+
+// GetCandidate mocks base method.
+func (m *MockStore) GetCandidate(arg0 context.Context, arg1 int64) (db.GetCandidateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCandidate", arg0, arg1)
+	ret0, _ := ret[0].(db.GetCandidateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCandidate indicates an expected call of GetCandidate.
+func (mr *MockStoreMockRecorder) GetCandidate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCandidate", reflect.TypeOf((*MockStore)(nil).GetCandidate), arg0, arg1)
+}
+
+// GetCandidateRevision mocks base method.
+func (m *MockStore) GetCandidateRevision(arg0 context.Context, arg1 db.GetCandidateRevisionParams) (db.CandidateRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCandidateRevision", arg0, arg1)
+	ret0, _ := ret[0].(db.CandidateRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCandidateRevision indicates an expected call of GetCandidateRevision.
+func (mr *MockStoreMockRecorder) GetCandidateRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCandidateRevision", reflect.TypeOf((*MockStore)(nil).GetCandidateRevision), arg0, arg1)
+}
+
+// GetContest mocks base method.
+func (m *MockStore) GetContest(arg0 context.Context, arg1 int64) (db.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContest", arg0, arg1)
+	ret0, _ := ret[0].(db.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContest indicates an expected call of GetContest.
+func (mr *MockStoreMockRecorder) GetContest(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContest", reflect.TypeOf((*MockStore)(nil).GetContest), arg0, arg1)
+}
+
+// GetDelegation mocks base method.
+func (m *MockStore) GetDelegation(arg0 context.Context, arg1 int64) (db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDelegation", arg0, arg1)
+	ret0, _ := ret[0].(db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDelegation indicates an expected call of GetDelegation.
+func (mr *MockStoreMockRecorder) GetDelegation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDelegation", reflect.TypeOf((*MockStore)(nil).GetDelegation), arg0, arg1)
+}
+
+// GetDistrict mocks base method.
+func (m *MockStore) GetDistrict(arg0 context.Context, arg1 int64) (db.District, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDistrict", arg0, arg1)
+	ret0, _ := ret[0].(db.District)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDistrict indicates an expected call of GetDistrict.
+func (mr *MockStoreMockRecorder) GetDistrict(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDistrict", reflect.TypeOf((*MockStore)(nil).GetDistrict), arg0, arg1)
+}
+
+// GetElection mocks base method.
+func (m *MockStore) GetElection(arg0 context.Context, arg1 int64) (db.Election, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetElection", arg0, arg1)
+	ret0, _ := ret[0].(db.Election)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetElection indicates an expected call of GetElection.
+func (mr *MockStoreMockRecorder) GetElection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetElection", reflect.TypeOf((*MockStore)(nil).GetElection), arg0, arg1)
+}
+
+// GetElectionProperty mocks base method.
+func (m *MockStore) GetElectionProperty(arg0 context.Context, arg1 string) (db.ElectionProperty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetElectionProperty", arg0, arg1)
+	ret0, _ := ret[0].(db.ElectionProperty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetElectionProperty indicates an expected call of GetElectionProperty.
+func (mr *MockStoreMockRecorder) GetElectionProperty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetElectionProperty", reflect.TypeOf((*MockStore)(nil).GetElectionProperty), arg0, arg1)
+}
+
+// GetGrantorApprovedDelegation mocks base method.
+func (m *MockStore) GetGrantorApprovedDelegation(arg0 context.Context, arg1 db.GetGrantorApprovedDelegationParams) (db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGrantorApprovedDelegation", arg0, arg1)
+	ret0, _ := ret[0].(db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGrantorApprovedDelegation indicates an expected call of GetGrantorApprovedDelegation.
+func (mr *MockStoreMockRecorder) GetGrantorApprovedDelegation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGrantorApprovedDelegation", reflect.TypeOf((*MockStore)(nil).GetGrantorApprovedDelegation), arg0, arg1)
+}
+
+// GetLatestEmailVerification mocks base method.
+func (m *MockStore) GetLatestEmailVerification(arg0 context.Context, arg1 string) (db.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(db.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestEmailVerification indicates an expected call of GetLatestEmailVerification.
+func (mr *MockStoreMockRecorder) GetLatestEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestEmailVerification", reflect.TypeOf((*MockStore)(nil).GetLatestEmailVerification), arg0, arg1)
+}
+
+// GetParty mocks base method.
+func (m *MockStore) GetParty(arg0 context.Context, arg1 int64) (db.Party, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetParty", arg0, arg1)
+	ret0, _ := ret[0].(db.Party)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetParty indicates an expected call of GetParty.
+func (mr *MockStoreMockRecorder) GetParty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetParty", reflect.TypeOf((*MockStore)(nil).GetParty), arg0, arg1)
+}
+
+// GetRunoffElection mocks base method.
+func (m *MockStore) GetRunoffElection(arg0 context.Context, arg1 sql.NullInt64) (db.Election, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRunoffElection", arg0, arg1)
+	ret0, _ := ret[0].(db.Election)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRunoffElection indicates an expected call of GetRunoffElection.
+func (mr *MockStoreMockRecorder) GetRunoffElection(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRunoffElection", reflect.TypeOf((*MockStore)(nil).GetRunoffElection), arg0, arg1)
+}
+
+// GetTwoFactor mocks base method.
+func (m *MockStore) GetTwoFactor(arg0 context.Context, arg1 string) (db.TwoFactor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTwoFactor indicates an expected call of GetTwoFactor.
+func (mr *MockStoreMockRecorder) GetTwoFactor(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTwoFactor", reflect.TypeOf((*MockStore)(nil).GetTwoFactor), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserByEmail mocks base method.
+func (m *MockStore) GetUserByEmail(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByEmail indicates an expected call of GetUserByEmail.
+func (mr *MockStoreMockRecorder) GetUserByEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockStore)(nil).GetUserByEmail), arg0, arg1)
+}
+
+// GetUserPasswordChangedAt mocks base method.
+func (m *MockStore) GetUserPasswordChangedAt(arg0 context.Context, arg1 string) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPasswordChangedAt", arg0, arg1)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPasswordChangedAt indicates an expected call of GetUserPasswordChangedAt.
+func (mr *MockStoreMockRecorder) GetUserPasswordChangedAt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPasswordChangedAt", reflect.TypeOf((*MockStore)(nil).GetUserPasswordChangedAt), arg0, arg1)
+}
+
+// GetUserPermissions mocks base method.
+func (m *MockStore) GetUserPermissions(arg0 context.Context, arg1 string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserPermissions", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserPermissions indicates an expected call of GetUserPermissions.
+func (mr *MockStoreMockRecorder) GetUserPermissions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserPermissions", reflect.TypeOf((*MockStore)(nil).GetUserPermissions), arg0, arg1)
+}
+
+// GetVoteByReceipt mocks base method.
+func (m *MockStore) GetVoteByReceipt(arg0 context.Context, arg1 string) (db.Vote, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVoteByReceipt", arg0, arg1)
+	ret0, _ := ret[0].(db.Vote)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVoteByReceipt indicates an expected call of GetVoteByReceipt.
+func (mr *MockStoreMockRecorder) GetVoteByReceipt(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVoteByReceipt", reflect.TypeOf((*MockStore)(nil).GetVoteByReceipt), arg0, arg1)
+}
+
+// HasElectionVoterVoted mocks base method.
+func (m *MockStore) HasElectionVoterVoted(arg0 context.Context, arg1 db.HasElectionVoterVotedParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasElectionVoterVoted", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasElectionVoterVoted indicates an expected call of HasElectionVoterVoted.
+func (mr *MockStoreMockRecorder) HasElectionVoterVoted(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasElectionVoterVoted", reflect.TypeOf((*MockStore)(nil).HasElectionVoterVoted), arg0, arg1)
+}
+
+// ImportVoterRollTx mocks base method.
+func (m *MockStore) ImportVoterRollTx(arg0 context.Context, arg1 db.ImportVoterRollTxParams) (db.ImportVoterRollTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportVoterRollTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportVoterRollTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportVoterRollTx indicates an expected call of ImportVoterRollTx.
+func (mr *MockStoreMockRecorder) ImportVoterRollTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportVoterRollTx", reflect.TypeOf((*MockStore)(nil).ImportVoterRollTx), arg0, arg1)
+}
+
+// ImportVoterWeightsTx mocks base method.
+func (m *MockStore) ImportVoterWeightsTx(arg0 context.Context, arg1 db.ImportVoterWeightsTxParams) (db.ImportVoterWeightsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportVoterWeightsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportVoterWeightsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportVoterWeightsTx indicates an expected call of ImportVoterWeightsTx.
+func (mr *MockStoreMockRecorder) ImportVoterWeightsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportVoterWeightsTx", reflect.TypeOf((*MockStore)(nil).ImportVoterWeightsTx), arg0, arg1)
+}
+
+// ListBallotCandidates mocks base method.
+func (m *MockStore) ListBallotCandidates(arg0 context.Context, arg1 db.ListBallotCandidatesParams) ([]db.ListBallotCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBallotCandidates", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBallotCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBallotCandidates indicates an expected call of ListBallotCandidates.
+func (mr *MockStoreMockRecorder) ListBallotCandidates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBallotCandidates", reflect.TypeOf((*MockStore)(nil).ListBallotCandidates), arg0, arg1)
+}
+
+// ListBallotMeasureOptions mocks base method.
+func (m *MockStore) ListBallotMeasureOptions(arg0 context.Context) ([]db.BallotMeasureOption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBallotMeasureOptions", arg0)
+	ret0, _ := ret[0].([]db.BallotMeasureOption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBallotMeasureOptions indicates an expected call of ListBallotMeasureOptions.
+func (mr *MockStoreMockRecorder) ListBallotMeasureOptions(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBallotMeasureOptions", reflect.TypeOf((*MockStore)(nil).ListBallotMeasureOptions), arg0)
+}
+
+// ListBallotMeasures mocks base method.
+func (m *MockStore) ListBallotMeasures(arg0 context.Context) ([]db.BallotMeasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBallotMeasures", arg0)
+	ret0, _ := ret[0].([]db.BallotMeasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBallotMeasures indicates an expected call of ListBallotMeasures.
+func (mr *MockStoreMockRecorder) ListBallotMeasures(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBallotMeasures", reflect.TypeOf((*MockStore)(nil).ListBallotMeasures), arg0)
+}
+
+// ListCandidateRevisions mocks base method.
+func (m *MockStore) ListCandidateRevisions(arg0 context.Context, arg1 int64) ([]db.CandidateRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCandidateRevisions", arg0, arg1)
+	ret0, _ := ret[0].([]db.CandidateRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCandidateRevisions indicates an expected call of ListCandidateRevisions.
+func (mr *MockStoreMockRecorder) ListCandidateRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCandidateRevisions", reflect.TypeOf((*MockStore)(nil).ListCandidateRevisions), arg0, arg1)
+}
+
+// ListCandidates mocks base method.
+func (m *MockStore) ListCandidates(arg0 context.Context, arg1 db.ListCandidatesParams) ([]db.ListCandidatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCandidates", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCandidatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCandidates indicates an expected call of ListCandidates.
+func (mr *MockStoreMockRecorder) ListCandidates(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCandidates", reflect.TypeOf((*MockStore)(nil).ListCandidates), arg0, arg1)
+}
+
+// ListCandidatesResult mocks base method.
+func (m *MockStore) ListCandidatesResult(arg0 context.Context) ([]db.ListCandidatesResultRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCandidatesResult", arg0)
+	ret0, _ := ret[0].([]db.ListCandidatesResultRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCandidatesResult indicates an expected call of ListCandidatesResult.
+func (mr *MockStoreMockRecorder) ListCandidatesResult(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCandidatesResult", reflect.TypeOf((*MockStore)(nil).ListCandidatesResult), arg0)
+}
+
+// ListDelegations mocks base method.
+func (m *MockStore) ListDelegations(arg0 context.Context, arg1 db.ListDelegationsParams) ([]db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDelegations", arg0, arg1)
+	ret0, _ := ret[0].([]db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDelegations indicates an expected call of ListDelegations.
+func (mr *MockStoreMockRecorder) ListDelegations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDelegations", reflect.TypeOf((*MockStore)(nil).ListDelegations), arg0, arg1)
+}
+
+// ListDistrictCandidatesResult mocks base method.
+func (m *MockStore) ListDistrictCandidatesResult(arg0 context.Context, arg1 int64) ([]db.ListDistrictCandidatesResultRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDistrictCandidatesResult", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListDistrictCandidatesResultRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDistrictCandidatesResult indicates an expected call of ListDistrictCandidatesResult.
+func (mr *MockStoreMockRecorder) ListDistrictCandidatesResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDistrictCandidatesResult", reflect.TypeOf((*MockStore)(nil).ListDistrictCandidatesResult), arg0, arg1)
+}
+
+// ListDistricts mocks base method.
+func (m *MockStore) ListDistricts(arg0 context.Context) ([]db.District, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDistricts", arg0)
+	ret0, _ := ret[0].([]db.District)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDistricts indicates an expected call of ListDistricts.
+func (mr *MockStoreMockRecorder) ListDistricts(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDistricts", reflect.TypeOf((*MockStore)(nil).ListDistricts), arg0)
+}
+
+// ListDistrictsResult mocks base method.
+func (m *MockStore) ListDistrictsResult(arg0 context.Context) ([]db.ListDistrictsResultRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDistrictsResult", arg0)
+	ret0, _ := ret[0].([]db.ListDistrictsResultRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDistrictsResult indicates an expected call of ListDistrictsResult.
+func (mr *MockStoreMockRecorder) ListDistrictsResult(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDistrictsResult", reflect.TypeOf((*MockStore)(nil).ListDistrictsResult), arg0)
+}
+
+// ListElectionCandidatesResult mocks base method.
+func (m *MockStore) ListElectionCandidatesResult(arg0 context.Context, arg1 db.ListElectionCandidatesResultParams) ([]db.ListElectionCandidatesResultRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListElectionCandidatesResult", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListElectionCandidatesResultRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListElectionCandidatesResult indicates an expected call of ListElectionCandidatesResult.
+func (mr *MockStoreMockRecorder) ListElectionCandidatesResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListElectionCandidatesResult", reflect.TypeOf((*MockStore)(nil).ListElectionCandidatesResult), arg0, arg1)
+}
+
+// ListElectionContests mocks base method.
+func (m *MockStore) ListElectionContests(arg0 context.Context, arg1 int64) ([]db.Contest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListElectionContests", arg0, arg1)
+	ret0, _ := ret[0].([]db.Contest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListElectionContests indicates an expected call of ListElectionContests.
+func (mr *MockStoreMockRecorder) ListElectionContests(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListElectionContests", reflect.TypeOf((*MockStore)(nil).ListElectionContests), arg0, arg1)
+}
+
+// ListElectionPartiesResult mocks base method.
+func (m *MockStore) ListElectionPartiesResult(arg0 context.Context, arg1 int64) ([]db.ListElectionPartiesResultRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListElectionPartiesResult", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListElectionPartiesResultRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListElectionPartiesResult indicates an expected call of ListElectionPartiesResult.
+func (mr *MockStoreMockRecorder) ListElectionPartiesResult(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListElectionPartiesResult", reflect.TypeOf((*MockStore)(nil).ListElectionPartiesResult), arg0, arg1)
+}
+
+// ListElectionProperties mocks base method.
+func (m *MockStore) ListElectionProperties(arg0 context.Context) ([]db.ElectionProperty, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListElectionProperties", arg0)
+	ret0, _ := ret[0].([]db.ElectionProperty)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListElectionProperties indicates an expected call of ListElectionProperties.
+func (mr *MockStoreMockRecorder) ListElectionProperties(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListElectionProperties", reflect.TypeOf((*MockStore)(nil).ListElectionProperties), arg0)
+}
+
+// ListElectionStates mocks base method.
+func (m *MockStore) ListElectionStates(arg0 context.Context) ([]db.ListElectionStatesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListElectionStates", arg0)
+	ret0, _ := ret[0].([]db.ListElectionStatesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListElectionStates indicates an expected call of ListElectionStates.
+func (mr *MockStoreMockRecorder) ListElectionStates(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListElectionStates", reflect.TypeOf((*MockStore)(nil).ListElectionStates), arg0)
+}
+
+// ListElectionVotes mocks base method.
+func (m *MockStore) ListElectionVotes(arg0 context.Context, arg1 db.ListElectionVotesParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListElectionVotes", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListElectionVotes indicates an expected call of ListElectionVotes.
+func (mr *MockStoreMockRecorder) ListElectionVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListElectionVotes", reflect.TypeOf((*MockStore)(nil).ListElectionVotes), arg0, arg1)
+}
+
+// ListMeasureOptionsResult mocks base method.
+func (m *MockStore) ListMeasureOptionsResult(arg0 context.Context) ([]db.ListMeasureOptionsResultRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMeasureOptionsResult", arg0)
+	ret0, _ := ret[0].([]db.ListMeasureOptionsResultRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMeasureOptionsResult indicates an expected call of ListMeasureOptionsResult.
+func (mr *MockStoreMockRecorder) ListMeasureOptionsResult(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMeasureOptionsResult", reflect.TypeOf((*MockStore)(nil).ListMeasureOptionsResult), arg0)
+}
+
+// ListParties mocks base method.
+func (m *MockStore) ListParties(arg0 context.Context) ([]db.Party, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListParties", arg0)
+	ret0, _ := ret[0].([]db.Party)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListParties indicates an expected call of ListParties.
+func (mr *MockStoreMockRecorder) ListParties(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListParties", reflect.TypeOf((*MockStore)(nil).ListParties), arg0)
+}
+
+// ListUserAudits mocks base method.
+func (m *MockStore) ListUserAudits(arg0 context.Context, arg1 db.ListUserAuditsParams) ([]db.UserAudit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserAudits", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserAudit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserAudits indicates an expected call of ListUserAudits.
+func (mr *MockStoreMockRecorder) ListUserAudits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserAudits", reflect.TypeOf((*MockStore)(nil).ListUserAudits), arg0, arg1)
+}
+
+// ListUserRoles mocks base method.
+func (m *MockStore) ListUserRoles(arg0 context.Context, arg1 string) ([]db.UserRole, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserRoles", arg0, arg1)
+	ret0, _ := ret[0].([]db.UserRole)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateCandidate indicates an expected call of CreateCandidate.
-func (mr *MockStoreMockRecorder) CreateCandidate(arg0, arg1 interface{}) *gomock.Call {
+// ListUserRoles indicates an expected call of ListUserRoles.
+func (mr *MockStoreMockRecorder) ListUserRoles(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCandidate", reflect.TypeOf((*MockStore)(nil).CreateCandidate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserRoles", reflect.TypeOf((*MockStore)(nil).ListUserRoles), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// ListUsers mocks base method.
+func (m *MockStore) ListUsers(arg0 context.Context, arg1 db.ListUsersParams) ([]db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ListUsers", arg0, arg1)
+	ret0, _ := ret[0].([]db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockStoreMockRecorder) ListUsers(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), arg0, arg1)
 }
 
-// CreateVote mocks base method.
-func (m *MockStore) CreateVote(arg0 context.Context, arg1 db.CreateVoteParams) (db.Vote, error) {
+// ListVoteOrderByCandidate mocks base method.
+func (m *MockStore) ListVoteOrderByCandidate(arg0 context.Context) ([]db.ListVoteOrderByCandidateRow, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateVote", arg0, arg1)
-	ret0, _ := ret[0].(db.Vote)
+	ret := m.ctrl.Call(m, "ListVoteOrderByCandidate", arg0)
+	ret0, _ := ret[0].([]db.ListVoteOrderByCandidateRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateVote indicates an expected call of CreateVote.
-func (mr *MockStoreMockRecorder) CreateVote(arg0, arg1 interface{}) *gomock.Call {
+// ListVoteOrderByCandidate indicates an expected call of ListVoteOrderByCandidate.
+func (mr *MockStoreMockRecorder) ListVoteOrderByCandidate(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVote", reflect.TypeOf((*MockStore)(nil).CreateVote), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVoteOrderByCandidate", reflect.TypeOf((*MockStore)(nil).ListVoteOrderByCandidate), arg0)
+}
+
+// LockDelegationProxy mocks base method.
+func (m *MockStore) LockDelegationProxy(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDelegationProxy", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockDelegationProxy indicates an expected call of LockDelegationProxy.
+func (mr *MockStoreMockRecorder) LockDelegationProxy(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDelegationProxy", reflect.TypeOf((*MockStore)(nil).LockDelegationProxy), arg0, arg1)
+}
+
+// ProvisionUserTx mocks base method.
+func (m *MockStore) ProvisionUserTx(arg0 context.Context, arg1 db.ProvisionUserTxParams) (db.ProvisionUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProvisionUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ProvisionUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProvisionUserTx indicates an expected call of ProvisionUserTx.
+func (mr *MockStoreMockRecorder) ProvisionUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProvisionUserTx", reflect.TypeOf((*MockStore)(nil).ProvisionUserTx), arg0, arg1)
 }
 
-// DeleteCandidate mocks base method.
-func (m *MockStore) DeleteCandidate(arg0 context.Context, arg1 int64) error {
+// RecountUserCandidates mocks base method.
+func (m *MockStore) RecountUserCandidates(arg0 context.Context, arg1 db.RecountUserCandidatesParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCandidate", arg0, arg1)
+	ret := m.ctrl.Call(m, "RecountUserCandidates", arg0, arg1)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// DeleteCandidate indicates an expected call of DeleteCandidate.
-func (mr *MockStoreMockRecorder) DeleteCandidate(arg0, arg1 interface{}) *gomock.Call {
+// RecountUserCandidates indicates an expected call of RecountUserCandidates.
+func (mr *MockStoreMockRecorder) RecountUserCandidates(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCandidate", reflect.TypeOf((*MockStore)(nil).DeleteCandidate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecountUserCandidates", reflect.TypeOf((*MockStore)(nil).RecountUserCandidates), arg0, arg1)
 }
 
-// GetCandidate mocks base method.
-func (m *MockStore) GetCandidate(arg0 context.Context, arg1 int64) (db.GetCandidateRow, error) {
+// RemoveUserPermission mocks base method.
+func (m *MockStore) RemoveUserPermission(arg0 context.Context, arg1 db.RemoveUserPermissionParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCandidate", arg0, arg1)
-	ret0, _ := ret[0].(db.GetCandidateRow)
+	ret := m.ctrl.Call(m, "RemoveUserPermission", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCandidate indicates an expected call of GetCandidate.
-func (mr *MockStoreMockRecorder) GetCandidate(arg0, arg1 interface{}) *gomock.Call {
+// RemoveUserPermission indicates an expected call of RemoveUserPermission.
+func (mr *MockStoreMockRecorder) RemoveUserPermission(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCandidate", reflect.TypeOf((*MockStore)(nil).GetCandidate), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveUserPermission", reflect.TypeOf((*MockStore)(nil).RemoveUserPermission), arg0, arg1)
 }
 
-// GetElectionProperty mocks base method.
-func (m *MockStore) GetElectionProperty(arg0 context.Context, arg1 string) (db.ElectionProperty, error) {
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.ResetPasswordTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetElectionProperty", arg0, arg1)
-	ret0, _ := ret[0].(db.ElectionProperty)
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ResetPasswordTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetElectionProperty indicates an expected call of GetElectionProperty.
-func (mr *MockStoreMockRecorder) GetElectionProperty(arg0, arg1 interface{}) *gomock.Call {
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetElectionProperty", reflect.TypeOf((*MockStore)(nil).GetElectionProperty), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// ResetUserVoted mocks base method.
+func (m *MockStore) ResetUserVoted(arg0 context.Context, arg1 db.ResetUserVotedParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ResetUserVoted", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
+// ResetUserVoted indicates an expected call of ResetUserVoted.
+func (mr *MockStoreMockRecorder) ResetUserVoted(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetUserVoted", reflect.TypeOf((*MockStore)(nil).ResetUserVoted), arg0, arg1)
 }
 
-// ListCandidates mocks base method.
-func (m *MockStore) ListCandidates(arg0 context.Context, arg1 db.ListCandidatesParams) ([]db.ListCandidatesRow, error) {
+// RestoreCandidate mocks base method.
+func (m *MockStore) RestoreCandidate(arg0 context.Context, arg1 int64) (db.Candidate, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCandidates", arg0, arg1)
-	ret0, _ := ret[0].([]db.ListCandidatesRow)
+	ret := m.ctrl.Call(m, "RestoreCandidate", arg0, arg1)
+	ret0, _ := ret[0].(db.Candidate)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListCandidates indicates an expected call of ListCandidates.
-func (mr *MockStoreMockRecorder) ListCandidates(arg0, arg1 interface{}) *gomock.Call {
+// RestoreCandidate indicates an expected call of RestoreCandidate.
+func (mr *MockStoreMockRecorder) RestoreCandidate(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCandidates", reflect.TypeOf((*MockStore)(nil).ListCandidates), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreCandidate", reflect.TypeOf((*MockStore)(nil).RestoreCandidate), arg0, arg1)
 }
 
-// ListCandidatesResult mocks base method.
-func (m *MockStore) ListCandidatesResult(arg0 context.Context) ([]db.ListCandidatesResultRow, error) {
+// RevokePasswordResets mocks base method.
+func (m *MockStore) RevokePasswordResets(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListCandidatesResult", arg0)
-	ret0, _ := ret[0].([]db.ListCandidatesResultRow)
+	ret := m.ctrl.Call(m, "RevokePasswordResets", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokePasswordResets indicates an expected call of RevokePasswordResets.
+func (mr *MockStoreMockRecorder) RevokePasswordResets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokePasswordResets", reflect.TypeOf((*MockStore)(nil).RevokePasswordResets), arg0, arg1)
+}
+
+// SetElectionVoterWeight mocks base method.
+func (m *MockStore) SetElectionVoterWeight(arg0 context.Context, arg1 db.SetElectionVoterWeightParams) (db.ElectionVoter, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetElectionVoterWeight", arg0, arg1)
+	ret0, _ := ret[0].(db.ElectionVoter)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListCandidatesResult indicates an expected call of ListCandidatesResult.
-func (mr *MockStoreMockRecorder) ListCandidatesResult(arg0 interface{}) *gomock.Call {
+// SetElectionVoterWeight indicates an expected call of SetElectionVoterWeight.
+func (mr *MockStoreMockRecorder) SetElectionVoterWeight(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCandidatesResult", reflect.TypeOf((*MockStore)(nil).ListCandidatesResult), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetElectionVoterWeight", reflect.TypeOf((*MockStore)(nil).SetElectionVoterWeight), arg0, arg1)
 }
 
-// ListVoteOrderByCandidate mocks base method.
-func (m *MockStore) ListVoteOrderByCandidate(arg0 context.Context) ([]db.ListVoteOrderByCandidateRow, error) {
+// SetupTwoFactor mocks base method.
+func (m *MockStore) SetupTwoFactor(arg0 context.Context, arg1 db.SetupTwoFactorParams) (db.TwoFactor, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListVoteOrderByCandidate", arg0)
-	ret0, _ := ret[0].([]db.ListVoteOrderByCandidateRow)
+	ret := m.ctrl.Call(m, "SetupTwoFactor", arg0, arg1)
+	ret0, _ := ret[0].(db.TwoFactor)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// ListVoteOrderByCandidate indicates an expected call of ListVoteOrderByCandidate.
-func (mr *MockStoreMockRecorder) ListVoteOrderByCandidate(arg0 interface{}) *gomock.Call {
+// SetupTwoFactor indicates an expected call of SetupTwoFactor.
+func (mr *MockStoreMockRecorder) SetupTwoFactor(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVoteOrderByCandidate", reflect.TypeOf((*MockStore)(nil).ListVoteOrderByCandidate), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupTwoFactor", reflect.TypeOf((*MockStore)(nil).SetupTwoFactor), arg0, arg1)
+}
+
+// SupersedeUserMeasureVotes mocks base method.
+func (m *MockStore) SupersedeUserMeasureVotes(arg0 context.Context, arg1 db.SupersedeUserMeasureVotesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupersedeUserMeasureVotes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SupersedeUserMeasureVotes indicates an expected call of SupersedeUserMeasureVotes.
+func (mr *MockStoreMockRecorder) SupersedeUserMeasureVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupersedeUserMeasureVotes", reflect.TypeOf((*MockStore)(nil).SupersedeUserMeasureVotes), arg0, arg1)
+}
+
+// SupersedeUserPartyVotes mocks base method.
+func (m *MockStore) SupersedeUserPartyVotes(arg0 context.Context, arg1 db.SupersedeUserPartyVotesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupersedeUserPartyVotes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SupersedeUserPartyVotes indicates an expected call of SupersedeUserPartyVotes.
+func (mr *MockStoreMockRecorder) SupersedeUserPartyVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupersedeUserPartyVotes", reflect.TypeOf((*MockStore)(nil).SupersedeUserPartyVotes), arg0, arg1)
+}
+
+// SupersedeUserVotes mocks base method.
+func (m *MockStore) SupersedeUserVotes(arg0 context.Context, arg1 db.SupersedeUserVotesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupersedeUserVotes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SupersedeUserVotes indicates an expected call of SupersedeUserVotes.
+func (mr *MockStoreMockRecorder) SupersedeUserVotes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupersedeUserVotes", reflect.TypeOf((*MockStore)(nil).SupersedeUserVotes), arg0, arg1)
 }
 
 // UpdateCandidate mocks base method.
@@ -199,6 +1630,36 @@ func (mr *MockStoreMockRecorder) UpdateCandidate(arg0, arg1 interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCandidate", reflect.TypeOf((*MockStore)(nil).UpdateCandidate), arg0, arg1)
 }
 
+// UpdateCandidateImage mocks base method.
+func (m *MockStore) UpdateCandidateImage(arg0 context.Context, arg1 db.UpdateCandidateImageParams) (db.Candidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCandidateImage", arg0, arg1)
+	ret0, _ := ret[0].(db.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCandidateImage indicates an expected call of UpdateCandidateImage.
+func (mr *MockStoreMockRecorder) UpdateCandidateImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCandidateImage", reflect.TypeOf((*MockStore)(nil).UpdateCandidateImage), arg0, arg1)
+}
+
+// UpdateDelegationStatus mocks base method.
+func (m *MockStore) UpdateDelegationStatus(arg0 context.Context, arg1 db.UpdateDelegationStatusParams) (db.Delegation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelegationStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Delegation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDelegationStatus indicates an expected call of UpdateDelegationStatus.
+func (mr *MockStoreMockRecorder) UpdateDelegationStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelegationStatus", reflect.TypeOf((*MockStore)(nil).UpdateDelegationStatus), arg0, arg1)
+}
+
 // UpdateElectionProperty mocks base method.
 func (m *MockStore) UpdateElectionProperty(arg0 context.Context, arg1 db.UpdateElectionPropertyParams) (db.ElectionProperty, error) {
 	m.ctrl.T.Helper()
@@ -213,3 +1674,212 @@ func (mr *MockStoreMockRecorder) UpdateElectionProperty(arg0, arg1 interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateElectionProperty", reflect.TypeOf((*MockStore)(nil).UpdateElectionProperty), arg0, arg1)
 }
+
+// UpdateElectionRules mocks base method.
+func (m *MockStore) UpdateElectionRules(arg0 context.Context, arg1 db.UpdateElectionRulesParams) (db.Election, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateElectionRules", arg0, arg1)
+	ret0, _ := ret[0].(db.Election)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateElectionRules indicates an expected call of UpdateElectionRules.
+func (mr *MockStoreMockRecorder) UpdateElectionRules(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateElectionRules", reflect.TypeOf((*MockStore)(nil).UpdateElectionRules), arg0, arg1)
+}
+
+// UpdateParty mocks base method.
+func (m *MockStore) UpdateParty(arg0 context.Context, arg1 db.UpdatePartyParams) (db.Party, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateParty", arg0, arg1)
+	ret0, _ := ret[0].(db.Party)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateParty indicates an expected call of UpdateParty.
+func (mr *MockStoreMockRecorder) UpdateParty(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateParty", reflect.TypeOf((*MockStore)(nil).UpdateParty), arg0, arg1)
+}
+
+// UpdateUserDistrict mocks base method.
+func (m *MockStore) UpdateUserDistrict(arg0 context.Context, arg1 db.UpdateUserDistrictParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserDistrict", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserDistrict indicates an expected call of UpdateUserDistrict.
+func (mr *MockStoreMockRecorder) UpdateUserDistrict(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserDistrict", reflect.TypeOf((*MockStore)(nil).UpdateUserDistrict), arg0, arg1)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UpdateUserProfile mocks base method.
+func (m *MockStore) UpdateUserProfile(arg0 context.Context, arg1 db.UpdateUserProfileParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserProfile", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserProfile indicates an expected call of UpdateUserProfile.
+func (mr *MockStoreMockRecorder) UpdateUserProfile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserProfile", reflect.TypeOf((*MockStore)(nil).UpdateUserProfile), arg0, arg1)
+}
+
+// UseEmailVerification mocks base method.
+func (m *MockStore) UseEmailVerification(arg0 context.Context, arg1 string) (db.EmailVerification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseEmailVerification", arg0, arg1)
+	ret0, _ := ret[0].(db.EmailVerification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseEmailVerification indicates an expected call of UseEmailVerification.
+func (mr *MockStoreMockRecorder) UseEmailVerification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseEmailVerification", reflect.TypeOf((*MockStore)(nil).UseEmailVerification), arg0, arg1)
+}
+
+// UseOIDCLogin mocks base method.
+func (m *MockStore) UseOIDCLogin(arg0 context.Context, arg1 string) (db.OidcLogin, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseOIDCLogin", arg0, arg1)
+	ret0, _ := ret[0].(db.OidcLogin)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseOIDCLogin indicates an expected call of UseOIDCLogin.
+func (mr *MockStoreMockRecorder) UseOIDCLogin(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseOIDCLogin", reflect.TypeOf((*MockStore)(nil).UseOIDCLogin), arg0, arg1)
+}
+
+// UsePasswordReset mocks base method.
+func (m *MockStore) UsePasswordReset(arg0 context.Context, arg1 string) (db.PasswordReset, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsePasswordReset", arg0, arg1)
+	ret0, _ := ret[0].(db.PasswordReset)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsePasswordReset indicates an expected call of UsePasswordReset.
+func (mr *MockStoreMockRecorder) UsePasswordReset(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsePasswordReset", reflect.TypeOf((*MockStore)(nil).UsePasswordReset), arg0, arg1)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockStore) UseRecoveryCode(arg0 context.Context, arg1 db.UseRecoveryCodeParams) (db.RecoveryCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", arg0, arg1)
+	ret0, _ := ret[0].(db.RecoveryCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockStoreMockRecorder) UseRecoveryCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockStore)(nil).UseRecoveryCode), arg0, arg1)
+}
+
+// UseTwoFactorChallenge mocks base method.
+func (m *MockStore) UseTwoFactorChallenge(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTwoFactorChallenge", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseTwoFactorChallenge indicates an expected call of UseTwoFactorChallenge.
+func (mr *MockStoreMockRecorder) UseTwoFactorChallenge(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTwoFactorChallenge", reflect.TypeOf((*MockStore)(nil).UseTwoFactorChallenge), arg0, arg1)
+}
+
+// UseTwoFactorStep mocks base method.
+func (m *MockStore) UseTwoFactorStep(arg0 context.Context, arg1 db.UseTwoFactorStepParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseTwoFactorStep", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseTwoFactorStep indicates an expected call of UseTwoFactorStep.
+func (mr *MockStoreMockRecorder) UseTwoFactorStep(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseTwoFactorStep", reflect.TypeOf((*MockStore)(nil).UseTwoFactorStep), arg0, arg1)
+}
+
+// VerifyEmailTx mocks base method.
+func (m *MockStore) VerifyEmailTx(arg0 context.Context, arg1 db.VerifyEmailTxParams) (db.VerifyEmailTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(db.VerifyEmailTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmailTx indicates an expected call of VerifyEmailTx.
+func (mr *MockStoreMockRecorder) VerifyEmailTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailTx", reflect.TypeOf((*MockStore)(nil).VerifyEmailTx), arg0, arg1)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStore) VerifyUserEmail(arg0 context.Context, arg1 db.VerifyUserEmailParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStoreMockRecorder) VerifyUserEmail(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStore)(nil).VerifyUserEmail), arg0, arg1)
+}
+
+// WithdrawCandidate mocks base method.
+func (m *MockStore) WithdrawCandidate(arg0 context.Context, arg1 db.WithdrawCandidateParams) (db.Candidate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawCandidate", arg0, arg1)
+	ret0, _ := ret[0].(db.Candidate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// WithdrawCandidate indicates an expected call of WithdrawCandidate.
+func (mr *MockStoreMockRecorder) WithdrawCandidate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawCandidate", reflect.TypeOf((*MockStore)(nil).WithdrawCandidate), arg0, arg1)
+}
@@ -1,4 +1,38 @@
 -- name: GetCandidate :one
+SELECT 
+  c.id,
+  c.name,
+  c.dob,
+  c.bio_link,
+  c.image_url,
+  c.policy,
+  c.vote_count,
+  c.create_at,
+  c.district_id,
+  c.election_id,
+  c.party_id,
+  c.policy_items,
+  c.links,
+  c.version,
+  c.withdrawn_at,
+  c.withdrawn_reason,
+  c.contest_id,
+  e.closed AS election_closed,
+  e.ballot_type AS election_ballot_type,
+  (e.voting_started_at IS NOT NULL)::boolean AS election_voting_started
+FROM candidates c
+JOIN elections e ON e.id = c.election_id
+WHERE c.id = $1 LIMIT 1;
+
+-- name: CountCandidates :one
+SELECT COUNT(*) FROM candidates
+WHERE (@search::text = '' OR to_tsvector('english', name || ' ' || policy) @@ websearch_to_tsquery('english', @search::text))
+  AND (@party_id::bigint = 0 OR party_id = @party_id::bigint)
+  AND (@district_id::bigint = 0 OR district_id = @district_id::bigint)
+  AND (@election_id::bigint = 0 OR election_id = @election_id::bigint)
+  AND (withdrawn_at IS NOT NULL) = @withdrawn::boolean;
+
+-- name: ListCandidates :many
 SELECT 
   id,
   name,
@@ -7,11 +41,44 @@ SELECT
   image_url,
   policy,
   vote_count,
-  create_at
+  weighted_vote_count,
+  party_id,
+  district_id,
+  election_id,
+  create_at,
+  (CASE WHEN @search::text = '' THEN 0
+   ELSE ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', @search::text)) END)::float8 AS rank
 FROM candidates
-WHERE id = $1 LIMIT 1;
+WHERE (@search::text = '' OR to_tsvector('english', name || ' ' || policy) @@ websearch_to_tsquery('english', @search::text))
+  AND (@party_id::bigint = 0 OR party_id = @party_id::bigint)
+  AND (@district_id::bigint = 0 OR district_id = @district_id::bigint)
+  AND (@election_id::bigint = 0 OR election_id = @election_id::bigint)
+  AND (withdrawn_at IS NOT NULL) = @withdrawn::boolean
+  -- keyset: rows strictly after the last row of the previous page in the sort order
+  AND (@after_id::bigint = 0
+    OR @sort::text IN ('', 'id') AND id > @after_id::bigint
+    OR @sort::text = 'name' AND (name, id) > (@after_name::text, @after_id::bigint)
+    OR @sort::text = '-name' AND (name < @after_name::text OR name = @after_name::text AND id > @after_id::bigint)
+    OR @sort::text = 'votes' AND (weighted_vote_count, id) > (@after_votes::bigint, @after_id::bigint)
+    OR @sort::text = '-votes' AND (weighted_vote_count < @after_votes::bigint OR weighted_vote_count = @after_votes::bigint AND id > @after_id::bigint)
+    OR @sort::text = 'created' AND (create_at, id) > (@after_create_at::timestamptz, @after_id::bigint)
+    OR @sort::text = '-created' AND (create_at < @after_create_at::timestamptz OR create_at = @after_create_at::timestamptz AND id > @after_id::bigint)
+    OR @sort::text = 'relevance' AND (
+      ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', @search::text))::float8 < @after_rank::float8
+      OR ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', @search::text))::float8 = @after_rank::float8 AND id > @after_id::bigint))
+ORDER BY
+  CASE WHEN @sort::text = 'relevance' THEN ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', @search::text))::float8 END DESC,
+  CASE WHEN @sort::text = 'name' THEN name END ASC,
+  CASE WHEN @sort::text = '-name' THEN name END DESC,
+  CASE WHEN @sort::text = 'votes' THEN weighted_vote_count END ASC,
+  CASE WHEN @sort::text = '-votes' THEN weighted_vote_count END DESC,
+  CASE WHEN @sort::text = 'created' THEN create_at END ASC,
+  CASE WHEN @sort::text = '-created' THEN create_at END DESC,
+  id
+LIMIT sqlc.arg('limit')
+OFFSET sqlc.arg('offset');
 
--- name: ListCandidates :many
+-- name: ListCandidatesResult :many
 SELECT 
   id,
   name,
@@ -20,13 +87,20 @@ SELECT
   image_url,
   policy,
   vote_count,
+  CONCAT(percentage, '%')::text as percentage,
+  weighted_vote_count,
+  CONCAT(weighted_percentage, '%')::text as weighted_percentage,
   create_at
-FROM candidates
-ORDER BY id
-LIMIT $1
-OFFSET $2;
+ FROM candidates
+ORDER BY weighted_vote_count DESC, vote_count DESC;
 
--- name: ListCandidatesResult :many
+-- name: ListBallotCandidates :many
+SELECT id, contest_id FROM candidates
+WHERE election_id = @election_id AND withdrawn_at IS NULL
+  AND (district_id IS NULL OR district_id = sqlc.narg(district_id))
+ORDER BY id;
+
+-- name: ListElectionCandidatesResult :many
 SELECT 
   id,
   name,
@@ -36,21 +110,41 @@ SELECT
   policy,
   vote_count,
   CONCAT(percentage, '%')::text as percentage,
-  create_at
+  weighted_vote_count,
+  CONCAT(weighted_percentage, '%')::text as weighted_percentage,
+  create_at,
+  withdrawn_at
  FROM candidates
-ORDER BY vote_count DESC;
+WHERE election_id = $1 AND COALESCE(contest_id, 0) = $2 AND (withdrawn_at IS NULL OR vote_count > 0)
+ORDER BY weighted_vote_count DESC, vote_count DESC;
 
 -- name: CreateCandidate :one
 INSERT INTO candidates (
-  name, dob, bio_link, image_url, policy, vote_count, percentage
+  name, dob, bio_link, image_url, policy, vote_count, percentage, district_id, party_id, policy_items, links, edited_by, election_id, contest_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 )
 RETURNING *;
 
+-- name: CreateRunoffCandidate :one
+INSERT INTO candidates (
+  name, dob, bio_link, image_url, policy, district_id, party_id, policy_items, links, election_id
+)
+SELECT name, dob, bio_link, image_url, policy, district_id, party_id, policy_items, links, $2
+FROM candidates
+WHERE candidates.id = $1
+RETURNING *;
+
 -- name: UpdateCandidate :one
-UPDATE candidates SET name = $2, dob = $3, bio_link = $4, image_url = $5, policy = $6
-WHERE id = $1
+WITH open_election AS (
+  SELECT e.id FROM elections e
+  JOIN candidates c ON c.election_id = e.id
+  WHERE c.id = $1 AND e.voting_started_at IS NULL
+  FOR SHARE OF e
+)
+UPDATE candidates SET name = $2, dob = $3, bio_link = $4, image_url = $5, policy = $6, district_id = $7,
+  party_id = $8, policy_items = $9, links = $10, edited_by = $11
+WHERE id = $1 AND election_id IN (SELECT id FROM open_election)
 RETURNING   
   id,
   name,
@@ -59,8 +153,55 @@ RETURNING
   image_url,
   policy,
   vote_count,
-  create_at;
+  create_at,
+  district_id,
+  party_id,
+  policy_items,
+  links,
+  version;
+
+-- name: UpdateCandidateImage :one
+WITH open_election AS (
+  SELECT e.id FROM elections e
+  JOIN candidates c ON c.election_id = e.id
+  WHERE c.id = $1 AND e.voting_started_at IS NULL
+  FOR SHARE OF e
+)
+UPDATE candidates SET image_url = $2, edited_by = $3
+WHERE id = $1 AND election_id IN (SELECT id FROM open_election)
+RETURNING *;
+
+-- name: WithdrawCandidate :one
+UPDATE candidates
+SET withdrawn_at = now(), withdrawn_reason = @withdrawn_reason, withdrawn_by = @withdrawn_by
+WHERE id = @id AND withdrawn_at IS NULL AND (vote_count = 0 OR @allow_voted::boolean)
+RETURNING *;
+
+-- name: RestoreCandidate :one
+UPDATE candidates
+SET withdrawn_at = NULL, withdrawn_reason = '', withdrawn_by = NULL
+WHERE id = $1 AND withdrawn_at IS NOT NULL
+RETURNING *;
 
--- name: DeleteCandidate :exec
-DELETE FROM candidates
-WHERE id = $1;
\ No newline at end of file
+-- name: RecountUserCandidates :exec
+UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    (
+      tally.vote_count/
+      (select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    )*100
+  ), weighted_vote_count = tally.weighted_vote_count, weighted_percentage = (
+    (
+      tally.weighted_vote_count/
+      election_total_weight(candidates.election_id)::float
+    )*100
+  )
+FROM (
+  SELECT c.id, (
+    select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+  ) AS vote_count, (
+    select COALESCE(SUM(v.weight), 0) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+  ) AS weighted_vote_count
+  FROM candidates c
+  WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = $1 AND election_id = $2)
+) AS tally
+WHERE candidates.id = tally.id;
@@ -0,0 +1,8 @@
+-- name: GetCandidateRevision :one
+SELECT * FROM candidate_revisions
+WHERE candidate_id = $1 AND version = $2 LIMIT 1;
+
+-- name: ListCandidateRevisions :many
+SELECT * FROM candidate_revisions
+WHERE candidate_id = $1
+ORDER BY version;
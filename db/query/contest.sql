@@ -0,0 +1,20 @@
+-- name: CreateContest :one
+INSERT INTO contests (
+  election_id, name
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: GetContest :one
+SELECT * FROM contests
+WHERE id = $1 LIMIT 1;
+
+-- name: ListElectionContests :many
+SELECT * FROM contests
+WHERE election_id = $1
+ORDER BY id;
+
+-- name: CountElectionContests :one
+SELECT COUNT(*) FROM contests
+WHERE election_id = $1;
@@ -0,0 +1,51 @@
+-- name: CreateDelegation :one
+INSERT INTO delegations (
+  grantor_national_id, proxy_national_id, election_id, status
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetDelegation :one
+SELECT * FROM delegations
+WHERE id = $1 LIMIT 1;
+
+-- name: GetGrantorApprovedDelegation :one
+SELECT * FROM delegations
+WHERE grantor_national_id = $1 AND election_id = $2 AND status = 'APPROVED'
+LIMIT 1;
+
+-- name: GetApprovedDelegation :one
+SELECT * FROM delegations
+WHERE grantor_national_id = $1 AND proxy_national_id = $2 AND election_id = $3 AND status = 'APPROVED'
+LIMIT 1;
+
+-- name: ListDelegations :many
+SELECT * FROM delegations
+WHERE status = $1
+ORDER BY id
+LIMIT $2
+OFFSET $3;
+
+-- name: CountProxyDelegations :one
+SELECT COUNT(*) FROM delegations
+WHERE proxy_national_id = $1 AND election_id = $2 AND status = ANY(@statuses::varchar[]);
+
+-- name: LockDelegationProxy :one
+SELECT national_id FROM users
+WHERE national_id = $1
+FOR NO KEY UPDATE;
+
+-- name: ApproveDelegation :one
+UPDATE delegations SET status = 'APPROVED', reviewed_by = $2, reviewed_at = now()
+WHERE id = $1 AND status = 'PENDING'
+RETURNING *;
+
+-- name: UpdateDelegationStatus :one
+UPDATE delegations SET status = $2, reviewed_by = $3, reviewed_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUserDelegations :exec
+DELETE FROM delegations
+WHERE grantor_national_id = $1 OR proxy_national_id = $1;
@@ -0,0 +1,47 @@
+-- name: CreateDistrict :one
+INSERT INTO districts (
+  name
+) VALUES (
+  $1
+)
+RETURNING *;
+
+-- name: GetDistrict :one
+SELECT * FROM districts
+WHERE id = $1 LIMIT 1;
+
+-- name: ListDistricts :many
+SELECT * FROM districts
+ORDER BY id;
+
+-- name: ListDistrictsResult :many
+SELECT
+  d.id,
+  d.name,
+  (SELECT COUNT(*) FROM users u WHERE u.district_id = d.id AND 'VOTE'=ANY(u.permission)) AS voter_count,
+  (
+    SELECT COUNT(DISTINCT ev.national_id) FROM election_voters ev
+    JOIN users u ON u.national_id = ev.national_id
+    WHERE u.district_id = d.id AND ev.voted_at IS NOT NULL
+  ) AS voted_count,
+  (SELECT COALESCE(SUM(c.vote_count), 0) FROM candidates c WHERE c.district_id = d.id)::bigint AS vote_count
+FROM districts d
+ORDER BY d.id;
+
+-- name: ListDistrictCandidatesResult :many
+SELECT 
+  id,
+  name,
+  dob,
+  bio_link,
+  image_url,
+  policy,
+  vote_count,
+  CONCAT(COALESCE(ROUND(vote_count * 100.0 / NULLIF((
+    SELECT COUNT(*) FROM users u WHERE u.district_id = @district_id::bigint AND 'VOTE'=ANY(u.permission)
+  ), 0)), 0), '%')::text as percentage,
+  weighted_vote_count,
+  create_at
+ FROM candidates
+WHERE district_id = @district_id::bigint
+ORDER BY weighted_vote_count DESC, vote_count DESC;
@@ -0,0 +1,38 @@
+-- name: GetElection :one
+SELECT * FROM elections
+WHERE id = $1 LIMIT 1;
+
+-- name: UpdateElectionRules :one
+UPDATE elections SET winner_rule = $2, quorum_percentage = $3, tie_break = $4, tie_break_seed = $5,
+  ballot_type = $6, seats = $7, seat_method = $8
+WHERE id = $1
+RETURNING *;
+
+
+-- name: CreateElection :one
+INSERT INTO elections (
+  name, winner_rule, quorum_percentage, tie_break, tie_break_seed, runoff_of_election_id
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: GetRunoffElection :one
+SELECT * FROM elections
+WHERE runoff_of_election_id = $1 LIMIT 1;
+
+-- name: CloseElection :one
+UPDATE elections SET closed = true
+WHERE id = $1
+RETURNING *;
+
+-- name: ListElectionStates :many
+SELECT e.id, e.closed, (
+  SELECT COUNT(*) FROM votes v
+  WHERE v.election_id = e.id AND v.superseded_at IS NULL
+) + (
+  SELECT COUNT(*) FROM party_votes pv
+  WHERE pv.election_id = e.id AND pv.superseded_at IS NULL
+) AS active_votes
+FROM elections e
+ORDER BY e.id;
@@ -5,4 +5,8 @@ WHERE name = $1 LIMIT 1;
 -- name: UpdateElectionProperty :one
 UPDATE election_properties SET value = $2
 WHERE name = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListElectionProperties :many
+SELECT * FROM election_properties
+ORDER BY name;
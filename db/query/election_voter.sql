@@ -0,0 +1,35 @@
+-- name: SetElectionVoterWeight :one
+WITH open_election AS (
+  SELECT id FROM elections
+  WHERE id = @election_id AND voting_started_at IS NULL
+  FOR SHARE
+)
+INSERT INTO election_voters (
+  election_id,
+  national_id,
+  vote_weight
+)
+SELECT id, @national_id::varchar, @vote_weight::bigint FROM open_election
+ON CONFLICT (election_id, national_id) DO UPDATE SET vote_weight = EXCLUDED.vote_weight
+RETURNING *;
+
+-- name: CopyElectionVoters :exec
+INSERT INTO election_voters (election_id, national_id, vote_weight)
+SELECT @to_election_id::bigint, national_id, vote_weight FROM election_voters
+WHERE election_voters.election_id = @from_election_id;
+
+-- name: HasElectionVoterVoted :one
+SELECT EXISTS (
+  SELECT 1 FROM election_voters
+  WHERE election_id = $1 AND national_id = $2 AND voted_at IS NOT NULL
+);
+
+-- name: ResetUserVoted :execrows
+WITH open_election AS (
+  SELECT id FROM elections
+  WHERE id = @election_id AND NOT closed
+  FOR SHARE
+)
+UPDATE election_voters SET voted_at = NULL
+WHERE election_id IN (SELECT id FROM open_election)
+  AND national_id = @national_id AND voted_at IS NOT NULL;
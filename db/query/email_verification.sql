@@ -0,0 +1,18 @@
+-- name: CreateEmailVerification :one
+INSERT INTO email_verifications (
+  national_id, email, token_hash, expired_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetLatestEmailVerification :one
+SELECT * FROM email_verifications
+WHERE national_id = $1
+ORDER BY create_at DESC
+LIMIT 1;
+
+-- name: UseEmailVerification :one
+UPDATE email_verifications SET used_at = now()
+WHERE token_hash = $1 AND used_at IS NULL AND expired_at > now()
+RETURNING *;
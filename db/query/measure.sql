@@ -0,0 +1,75 @@
+-- name: CreateBallotMeasure :one
+INSERT INTO ballot_measures (
+  question, threshold_percentage, quorum_percentage
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetBallotMeasure :one
+SELECT * FROM ballot_measures
+WHERE id = $1 LIMIT 1;
+
+-- name: ListBallotMeasures :many
+SELECT * FROM ballot_measures
+ORDER BY id;
+
+-- name: CountElectionBallotMeasures :one
+SELECT COUNT(*) FROM ballot_measures
+WHERE election_id = $1;
+
+-- name: CreateBallotMeasureOption :one
+INSERT INTO ballot_measure_options (
+  measure_id, label, position
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetBallotMeasureOption :one
+SELECT 
+  o.id,
+  o.measure_id,
+  o.label,
+  o.position,
+  o.create_at,
+  m.election_id,
+  e.closed AS election_closed
+FROM ballot_measure_options o
+JOIN ballot_measures m ON m.id = o.measure_id
+JOIN elections e ON e.id = m.election_id
+WHERE o.id = $1 LIMIT 1;
+
+-- name: ListBallotMeasureOptions :many
+SELECT * FROM ballot_measure_options
+ORDER BY measure_id, position;
+
+-- name: CreateMeasureVote :one
+INSERT INTO measure_votes (
+  vote_national_id, measure_id, option_id, cast_by_national_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListMeasureOptionsResult :many
+SELECT 
+ o.id,
+ o.measure_id,
+ o.label,
+ o.position,
+ COUNT(v.id) AS vote_count,
+ COALESCE(SUM(v.weight), 0)::bigint AS weighted_vote_count
+ FROM ballot_measure_options o
+ LEFT JOIN measure_votes v ON v.option_id = o.id AND v.superseded_at IS NULL
+GROUP BY o.id
+ORDER BY o.measure_id, o.position;
+
+-- name: CountEligibleVoters :one
+SELECT COUNT(*) FROM users
+WHERE 'VOTE' = ANY(permission);
+
+-- name: SupersedeUserMeasureVotes :exec
+UPDATE measure_votes SET superseded_at = now()
+WHERE vote_national_id = $1 AND superseded_at IS NULL
+  AND measure_id IN (SELECT id FROM ballot_measures WHERE election_id = $2);
@@ -0,0 +1,12 @@
+-- name: CreateOIDCLogin :one
+INSERT INTO oidc_logins (
+  state_hash, nonce, code_verifier, expired_at
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: UseOIDCLogin :one
+UPDATE oidc_logins SET used_at = now()
+WHERE state_hash = $1 AND used_at IS NULL AND expired_at > now()
+RETURNING *;
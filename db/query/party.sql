@@ -0,0 +1,61 @@
+-- name: CreateParty :one
+INSERT INTO parties (
+  name, logo_url, color
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: GetParty :one
+SELECT * FROM parties
+WHERE id = $1 LIMIT 1;
+
+-- name: ListParties :many
+SELECT * FROM parties
+ORDER BY name;
+
+-- name: UpdateParty :one
+UPDATE parties SET name = $2, logo_url = $3, color = $4
+WHERE id = $1
+RETURNING *;
+
+-- name: CreatePartyVote :one
+INSERT INTO party_votes (
+  vote_national_id, election_id, party_id, cast_by_national_id
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: ListElectionPartiesResult :many
+SELECT
+ p.id,
+ p.name,
+ p.logo_url,
+ p.color,
+ (
+   SELECT COUNT(*) FROM candidates c
+   WHERE c.party_id = p.id AND c.election_id = $1 AND c.withdrawn_at IS NULL
+ ) AS candidate_count,
+ (
+   SELECT COUNT(*) FROM party_votes v
+   WHERE v.party_id = p.id AND v.election_id = $1 AND v.superseded_at IS NULL
+ ) AS list_vote_count,
+ (
+   SELECT COALESCE(SUM(v.weight), 0) FROM party_votes v
+   WHERE v.party_id = p.id AND v.election_id = $1 AND v.superseded_at IS NULL
+ )::bigint AS weighted_list_vote_count,
+ (
+   SELECT COALESCE(SUM(c.vote_count), 0) FROM candidates c
+   WHERE c.party_id = p.id AND c.election_id = $1
+ )::bigint AS candidate_vote_count,
+ (
+   SELECT COALESCE(SUM(c.weighted_vote_count), 0) FROM candidates c
+   WHERE c.party_id = p.id AND c.election_id = $1
+ )::bigint AS weighted_candidate_vote_count
+ FROM parties p
+ORDER BY p.id;
+
+-- name: SupersedeUserPartyVotes :exec
+UPDATE party_votes SET superseded_at = now()
+WHERE vote_national_id = $1 AND election_id = $2 AND superseded_at IS NULL;
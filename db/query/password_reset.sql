@@ -0,0 +1,16 @@
+-- name: CreatePasswordReset :one
+INSERT INTO password_resets (
+  national_id, token_hash, expired_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: UsePasswordReset :one
+UPDATE password_resets SET used_at = now()
+WHERE token_hash = $1 AND used_at IS NULL AND expired_at > now()
+RETURNING *;
+
+-- name: RevokePasswordResets :exec
+UPDATE password_resets SET used_at = now()
+WHERE national_id = $1 AND used_at IS NULL;
@@ -0,0 +1,61 @@
+-- name: SetupTwoFactor :one
+INSERT INTO two_factors (
+  national_id, secret
+) VALUES (
+  $1, $2
+)
+ON CONFLICT (national_id) DO UPDATE
+SET secret = EXCLUDED.secret, last_used_step = 0, create_at = now()
+WHERE two_factors.enabled_at IS NULL
+RETURNING *;
+
+-- name: GetTwoFactor :one
+SELECT * FROM two_factors
+WHERE national_id = $1 LIMIT 1;
+
+-- name: EnableTwoFactor :one
+UPDATE two_factors SET enabled_at = now()
+WHERE national_id = $1 AND enabled_at IS NULL
+RETURNING *;
+
+-- name: UseTwoFactorStep :execrows
+UPDATE two_factors SET last_used_step = $2
+WHERE national_id = $1 AND last_used_step < $2;
+
+-- name: DeleteTwoFactor :exec
+DELETE FROM two_factors
+WHERE national_id = $1;
+
+-- name: CreateRecoveryCode :one
+INSERT INTO recovery_codes (
+  national_id, code_hash
+) VALUES (
+  $1, $2
+)
+RETURNING *;
+
+-- name: UseRecoveryCode :one
+UPDATE recovery_codes SET used_at = now()
+WHERE national_id = $1 AND code_hash = $2 AND used_at IS NULL
+RETURNING *;
+
+-- name: DeleteRecoveryCodes :exec
+DELETE FROM recovery_codes
+WHERE national_id = $1;
+
+-- name: CreateTwoFactorChallenge :one
+INSERT INTO two_factor_challenges (
+  national_id, token_hash, expired_at
+) VALUES (
+  $1, $2, $3
+)
+RETURNING *;
+
+-- name: AttemptTwoFactorChallenge :one
+UPDATE two_factor_challenges SET attempts = attempts + 1
+WHERE token_hash = $1 AND used_at IS NULL AND expired_at > now()
+RETURNING *;
+
+-- name: UseTwoFactorChallenge :exec
+UPDATE two_factor_challenges SET used_at = now()
+WHERE id = $1;
@@ -5,8 +5,91 @@ WHERE national_id = $1 LIMIT 1;
 
 -- name: CreateUser :one
 INSERT INTO users (
-  national_id, hashed_password, full_name, email, permission, has_voted
+  national_id, hashed_password, full_name, email, permission, district_id
 ) VALUES (
   $1, $2, $3, $4, $5, $6
 )
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: UpdateUserDistrict :one
+UPDATE users SET district_id = $2
+WHERE national_id = $1
+RETURNING *;
+
+-- name: GetUserByEmail :one
+SELECT * FROM users
+WHERE email = $1 LIMIT 1;
+
+-- name: GetUserPasswordChangedAt :one
+SELECT password_changed_at FROM users
+WHERE national_id = $1 LIMIT 1;
+
+-- name: GetUserPermissions :one
+SELECT permission FROM users
+WHERE national_id = $1 LIMIT 1;
+
+-- name: UpdateUserProfile :one
+UPDATE users
+SET
+  full_name = @full_name,
+  email = @email,
+  verified_at = CASE WHEN email = @email THEN verified_at ELSE NULL END
+WHERE national_id = @national_id
+RETURNING *;
+
+-- name: UpdateUserPassword :one
+UPDATE users SET hashed_password = $2, password_changed_at = $3
+WHERE national_id = $1
+RETURNING *;
+
+-- name: DeleteUser :execrows
+DELETE FROM users u
+WHERE u.national_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM votes v
+    WHERE v.vote_national_id = u.national_id OR v.cast_by_national_id = u.national_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM measure_votes mv
+    WHERE mv.vote_national_id = u.national_id OR mv.cast_by_national_id = u.national_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM party_votes pv
+    WHERE pv.vote_national_id = u.national_id OR pv.cast_by_national_id = u.national_id
+  );
+
+-- name: VerifyUserEmail :one
+UPDATE users SET verified_at = now()
+WHERE national_id = $1 AND email = $2
+RETURNING *;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE (@search::text = ''
+    OR national_id ILIKE '%' || @search::text || '%'
+    OR full_name ILIKE '%' || @search::text || '%'
+    OR email ILIKE '%' || @search::text || '%')
+  AND (@permission::varchar = '' OR @permission::varchar = ANY(permission))
+  AND national_id > @after_national_id::varchar
+ORDER BY national_id
+LIMIT sqlc.arg('limit');
+
+-- name: AddUserPermission :one
+UPDATE users SET permission = array_append(permission, @permission::varchar)
+WHERE national_id = @national_id AND NOT (@permission = ANY(permission))
+RETURNING *;
+
+-- name: RemoveUserPermission :one
+UPDATE users SET permission = array_remove(permission, @permission::varchar)
+WHERE national_id = @national_id AND @permission = ANY(permission)
+RETURNING *;
+
+-- name: DisableUser :one
+UPDATE users SET disabled_at = now(), password_changed_at = now()
+WHERE national_id = $1 AND disabled_at IS NULL
+RETURNING *;
+
+-- name: EnableUser :one
+UPDATE users SET disabled_at = NULL
+WHERE national_id = $1 AND disabled_at IS NOT NULL
+RETURNING *;
@@ -0,0 +1,14 @@
+-- name: CreateUserAudit :one
+INSERT INTO user_audits (
+  actor_national_id, target_national_id, action, permission, role, election_id, reason
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+RETURNING *;
+
+-- name: ListUserAudits :many
+SELECT * FROM user_audits
+WHERE (@target_national_id::varchar = '' OR target_national_id = @target_national_id::varchar)
+  AND (@before_id::bigint = 0 OR id < @before_id::bigint)
+ORDER BY id DESC
+LIMIT sqlc.arg('limit');
@@ -0,0 +1,18 @@
+-- name: CreateUserRole :one
+INSERT INTO user_roles (
+  national_id, role, election_id, granted_by
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (national_id, role, (COALESCE(election_id, 0))) DO NOTHING
+RETURNING *;
+
+-- name: DeleteUserRole :one
+DELETE FROM user_roles
+WHERE national_id = $1 AND role = $2 AND election_id IS NOT DISTINCT FROM $3
+RETURNING *;
+
+-- name: ListUserRoles :many
+SELECT * FROM user_roles
+WHERE national_id = $1
+ORDER BY id;
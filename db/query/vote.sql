@@ -1,15 +1,39 @@
 -- name: CreateVote :one
 INSERT INTO votes (
-  vote_national_id, candidate_id
+  vote_national_id, candidate_id, receipt_hash, cast_by_national_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4
 )
 RETURNING *;
 
+-- name: GetVoteByReceipt :one
+SELECT * FROM votes
+WHERE receipt_hash = $1 LIMIT 1;
 
 -- name: ListVoteOrderByCandidate :many
 SELECT 
  candidate_id,
- vote_national_id 
+ vote_national_id,
+ cast_by_national_id
  FROM votes
-ORDER BY candidate_id;
\ No newline at end of file
+WHERE superseded_at IS NULL
+ORDER BY candidate_id;
+
+-- name: CountActiveVotes :one
+SELECT (
+  SELECT COUNT(DISTINCT v.vote_national_id) FROM votes v
+  WHERE v.election_id = $1 AND v.superseded_at IS NULL
+) + (
+  SELECT COUNT(*) FROM party_votes pv
+  WHERE pv.election_id = $1 AND pv.superseded_at IS NULL
+) AS count;
+
+-- name: ListElectionVotes :many
+SELECT receipt_hash FROM votes
+WHERE election_id = $1 AND receipt_hash > $2
+ORDER BY receipt_hash
+LIMIT $3;
+
+-- name: SupersedeUserVotes :exec
+UPDATE votes SET superseded_at = now()
+WHERE vote_national_id = $1 AND election_id = $2 AND superseded_at IS NULL;
@@ -5,26 +5,65 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+const countCandidates = `-- name: CountCandidates :one
+SELECT COUNT(*) FROM candidates
+WHERE ($1::text = '' OR to_tsvector('english', name || ' ' || policy) @@ websearch_to_tsquery('english', $1::text))
+  AND ($2::bigint = 0 OR party_id = $2::bigint)
+  AND ($3::bigint = 0 OR district_id = $3::bigint)
+  AND ($4::bigint = 0 OR election_id = $4::bigint)
+  AND (withdrawn_at IS NOT NULL) = $5::boolean
+`
+
+type CountCandidatesParams struct {
+	Search     string `json:"search"`
+	PartyID    int64  `json:"party_id"`
+	DistrictID int64  `json:"district_id"`
+	ElectionID int64  `json:"election_id"`
+	Withdrawn  bool   `json:"withdrawn"`
+}
+
+func (q *Queries) CountCandidates(ctx context.Context, arg CountCandidatesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCandidates,
+		arg.Search,
+		arg.PartyID,
+		arg.DistrictID,
+		arg.ElectionID,
+		arg.Withdrawn,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCandidate = `-- name: CreateCandidate :one
 INSERT INTO candidates (
-  name, dob, bio_link, image_url, policy, vote_count, percentage
+  name, dob, bio_link, image_url, policy, vote_count, percentage, district_id, party_id, policy_items, links, edited_by, election_id, contest_id
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 )
-RETURNING id, name, dob, bio_link, image_url, policy, vote_count, percentage, create_at
+RETURNING id, name, dob, bio_link, image_url, policy, vote_count, percentage, create_at, district_id, weighted_vote_count, weighted_percentage, election_id, policy_items, links, version, edited_by, party_id, withdrawn_at, withdrawn_reason, withdrawn_by, contest_id
 `
 
 type CreateCandidateParams struct {
-	Name       string `json:"name"`
-	Dob        string `json:"dob"`
-	BioLink    string `json:"bio_link"`
-	ImageUrl   string `json:"image_url"`
-	Policy     string `json:"policy"`
-	VoteCount  int32  `json:"vote_count"`
-	Percentage int32  `json:"percentage"`
+	Name        string          `json:"name"`
+	Dob         string          `json:"dob"`
+	BioLink     string          `json:"bio_link"`
+	ImageUrl    string          `json:"image_url"`
+	Policy      string          `json:"policy"`
+	VoteCount   int32           `json:"vote_count"`
+	Percentage  int32           `json:"percentage"`
+	DistrictID  sql.NullInt64   `json:"district_id"`
+	PartyID     sql.NullInt64   `json:"party_id"`
+	PolicyItems json.RawMessage `json:"policy_items"`
+	Links       json.RawMessage `json:"links"`
+	EditedBy    sql.NullString  `json:"edited_by"`
+	ElectionID  int64           `json:"election_id"`
+	ContestID   sql.NullInt64   `json:"contest_id"`
 }
 
 func (q *Queries) CreateCandidate(ctx context.Context, arg CreateCandidateParams) (Candidate, error) {
@@ -36,6 +75,13 @@ func (q *Queries) CreateCandidate(ctx context.Context, arg CreateCandidateParams
 		arg.Policy,
 		arg.VoteCount,
 		arg.Percentage,
+		arg.DistrictID,
+		arg.PartyID,
+		arg.PolicyItems,
+		arg.Links,
+		arg.EditedBy,
+		arg.ElectionID,
+		arg.ContestID,
 	)
 	var i Candidate
 	err := row.Scan(
@@ -48,43 +94,116 @@ func (q *Queries) CreateCandidate(ctx context.Context, arg CreateCandidateParams
 		&i.VoteCount,
 		&i.Percentage,
 		&i.CreateAt,
+		&i.DistrictID,
+		&i.WeightedVoteCount,
+		&i.WeightedPercentage,
+		&i.ElectionID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+		&i.EditedBy,
+		&i.PartyID,
+		&i.WithdrawnAt,
+		&i.WithdrawnReason,
+		&i.WithdrawnBy,
+		&i.ContestID,
 	)
 	return i, err
 }
 
-const deleteCandidate = `-- name: DeleteCandidate :exec
-DELETE FROM candidates
-WHERE id = $1
+const createRunoffCandidate = `-- name: CreateRunoffCandidate :one
+INSERT INTO candidates (
+  name, dob, bio_link, image_url, policy, district_id, party_id, policy_items, links, election_id
+)
+SELECT name, dob, bio_link, image_url, policy, district_id, party_id, policy_items, links, $2
+FROM candidates
+WHERE candidates.id = $1
+RETURNING id, name, dob, bio_link, image_url, policy, vote_count, percentage, create_at, district_id, weighted_vote_count, weighted_percentage, election_id, policy_items, links, version, edited_by, party_id, withdrawn_at, withdrawn_reason, withdrawn_by, contest_id
 `
 
-func (q *Queries) DeleteCandidate(ctx context.Context, id int64) error {
-	_, err := q.db.ExecContext(ctx, deleteCandidate, id)
-	return err
+type CreateRunoffCandidateParams struct {
+	ID         int64 `json:"id"`
+	ElectionID int64 `json:"election_id"`
+}
+
+func (q *Queries) CreateRunoffCandidate(ctx context.Context, arg CreateRunoffCandidateParams) (Candidate, error) {
+	row := q.db.QueryRowContext(ctx, createRunoffCandidate, arg.ID, arg.ElectionID)
+	var i Candidate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.BioLink,
+		&i.ImageUrl,
+		&i.Policy,
+		&i.VoteCount,
+		&i.Percentage,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.WeightedVoteCount,
+		&i.WeightedPercentage,
+		&i.ElectionID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+		&i.EditedBy,
+		&i.PartyID,
+		&i.WithdrawnAt,
+		&i.WithdrawnReason,
+		&i.WithdrawnBy,
+		&i.ContestID,
+	)
+	return i, err
 }
 
 const getCandidate = `-- name: GetCandidate :one
 SELECT 
-  id,
-  name,
-  dob,
-  bio_link,
-  image_url,
-  policy,
-  vote_count,
-  create_at
-FROM candidates
-WHERE id = $1 LIMIT 1
+  c.id,
+  c.name,
+  c.dob,
+  c.bio_link,
+  c.image_url,
+  c.policy,
+  c.vote_count,
+  c.create_at,
+  c.district_id,
+  c.election_id,
+  c.party_id,
+  c.policy_items,
+  c.links,
+  c.version,
+  c.withdrawn_at,
+  c.withdrawn_reason,
+  c.contest_id,
+  e.closed AS election_closed,
+  e.ballot_type AS election_ballot_type,
+  (e.voting_started_at IS NOT NULL)::boolean AS election_voting_started
+FROM candidates c
+JOIN elections e ON e.id = c.election_id
+WHERE c.id = $1 LIMIT 1
 `
 
 type GetCandidateRow struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Dob       string    `json:"dob"`
-	BioLink   string    `json:"bio_link"`
-	ImageUrl  string    `json:"image_url"`
-	Policy    string    `json:"policy"`
-	VoteCount int32     `json:"vote_count"`
-	CreateAt  time.Time `json:"create_at"`
+	ID                    int64           `json:"id"`
+	Name                  string          `json:"name"`
+	Dob                   string          `json:"dob"`
+	BioLink               string          `json:"bio_link"`
+	ImageUrl              string          `json:"image_url"`
+	Policy                string          `json:"policy"`
+	VoteCount             int32           `json:"vote_count"`
+	CreateAt              time.Time       `json:"create_at"`
+	DistrictID            sql.NullInt64   `json:"district_id"`
+	ElectionID            int64           `json:"election_id"`
+	PartyID               sql.NullInt64   `json:"party_id"`
+	PolicyItems           json.RawMessage `json:"policy_items"`
+	Links                 json.RawMessage `json:"links"`
+	Version               int32           `json:"version"`
+	WithdrawnAt           sql.NullTime    `json:"withdrawn_at"`
+	WithdrawnReason       string          `json:"withdrawn_reason"`
+	ContestID             sql.NullInt64   `json:"contest_id"`
+	ElectionClosed        bool            `json:"election_closed"`
+	ElectionBallotType    string          `json:"election_ballot_type"`
+	ElectionVotingStarted bool            `json:"election_voting_started"`
 }
 
 func (q *Queries) GetCandidate(ctx context.Context, id int64) (GetCandidateRow, error) {
@@ -99,10 +218,62 @@ func (q *Queries) GetCandidate(ctx context.Context, id int64) (GetCandidateRow,
 		&i.Policy,
 		&i.VoteCount,
 		&i.CreateAt,
+		&i.DistrictID,
+		&i.ElectionID,
+		&i.PartyID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+		&i.WithdrawnAt,
+		&i.WithdrawnReason,
+		&i.ContestID,
+		&i.ElectionClosed,
+		&i.ElectionBallotType,
+		&i.ElectionVotingStarted,
 	)
 	return i, err
 }
 
+const listBallotCandidates = `-- name: ListBallotCandidates :many
+SELECT id, contest_id FROM candidates
+WHERE election_id = $1 AND withdrawn_at IS NULL
+  AND (district_id IS NULL OR district_id = $2)
+ORDER BY id
+`
+
+type ListBallotCandidatesParams struct {
+	ElectionID int64         `json:"election_id"`
+	DistrictID sql.NullInt64 `json:"district_id"`
+}
+
+type ListBallotCandidatesRow struct {
+	ID        int64         `json:"id"`
+	ContestID sql.NullInt64 `json:"contest_id"`
+}
+
+func (q *Queries) ListBallotCandidates(ctx context.Context, arg ListBallotCandidatesParams) ([]ListBallotCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, listBallotCandidates, arg.ElectionID, arg.DistrictID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBallotCandidatesRow{}
+	for rows.Next() {
+		var i ListBallotCandidatesRow
+		if err := rows.Scan(&i.ID, &i.ContestID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listCandidates = `-- name: ListCandidates :many
 SELECT 
   id,
@@ -112,31 +283,92 @@ SELECT
   image_url,
   policy,
   vote_count,
-  create_at
+  weighted_vote_count,
+  party_id,
+  district_id,
+  election_id,
+  create_at,
+  (CASE WHEN $1::text = '' THEN 0
+   ELSE ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', $1::text)) END)::float8 AS rank
 FROM candidates
-ORDER BY id
-LIMIT $1
-OFFSET $2
+WHERE ($1::text = '' OR to_tsvector('english', name || ' ' || policy) @@ websearch_to_tsquery('english', $1::text))
+  AND ($2::bigint = 0 OR party_id = $2::bigint)
+  AND ($3::bigint = 0 OR district_id = $3::bigint)
+  AND ($4::bigint = 0 OR election_id = $4::bigint)
+  AND (withdrawn_at IS NOT NULL) = $5::boolean
+  -- keyset: rows strictly after the last row of the previous page in the sort order
+  AND ($6::bigint = 0
+    OR $7::text IN ('', 'id') AND id > $6::bigint
+    OR $7::text = 'name' AND (name, id) > ($8::text, $6::bigint)
+    OR $7::text = '-name' AND (name < $8::text OR name = $8::text AND id > $6::bigint)
+    OR $7::text = 'votes' AND (weighted_vote_count, id) > ($9::bigint, $6::bigint)
+    OR $7::text = '-votes' AND (weighted_vote_count < $9::bigint OR weighted_vote_count = $9::bigint AND id > $6::bigint)
+    OR $7::text = 'created' AND (create_at, id) > ($10::timestamptz, $6::bigint)
+    OR $7::text = '-created' AND (create_at < $10::timestamptz OR create_at = $10::timestamptz AND id > $6::bigint)
+    OR $7::text = 'relevance' AND (
+      ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', $1::text))::float8 < $11::float8
+      OR ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', $1::text))::float8 = $11::float8 AND id > $6::bigint))
+ORDER BY
+  CASE WHEN $7::text = 'relevance' THEN ts_rank(to_tsvector('english', name || ' ' || policy), websearch_to_tsquery('english', $1::text))::float8 END DESC,
+  CASE WHEN $7::text = 'name' THEN name END ASC,
+  CASE WHEN $7::text = '-name' THEN name END DESC,
+  CASE WHEN $7::text = 'votes' THEN weighted_vote_count END ASC,
+  CASE WHEN $7::text = '-votes' THEN weighted_vote_count END DESC,
+  CASE WHEN $7::text = 'created' THEN create_at END ASC,
+  CASE WHEN $7::text = '-created' THEN create_at END DESC,
+  id
+LIMIT $12
+OFFSET $13
 `
 
 type ListCandidatesParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	Search        string    `json:"search"`
+	PartyID       int64     `json:"party_id"`
+	DistrictID    int64     `json:"district_id"`
+	ElectionID    int64     `json:"election_id"`
+	Withdrawn     bool      `json:"withdrawn"`
+	AfterID       int64     `json:"after_id"`
+	Sort          string    `json:"sort"`
+	AfterName     string    `json:"after_name"`
+	AfterVotes    int64     `json:"after_votes"`
+	AfterCreateAt time.Time `json:"after_create_at"`
+	AfterRank     float64   `json:"after_rank"`
+	Limit         int32     `json:"limit"`
+	Offset        int32     `json:"offset"`
 }
 
 type ListCandidatesRow struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Dob       string    `json:"dob"`
-	BioLink   string    `json:"bio_link"`
-	ImageUrl  string    `json:"image_url"`
-	Policy    string    `json:"policy"`
-	VoteCount int32     `json:"vote_count"`
-	CreateAt  time.Time `json:"create_at"`
+	ID                int64         `json:"id"`
+	Name              string        `json:"name"`
+	Dob               string        `json:"dob"`
+	BioLink           string        `json:"bio_link"`
+	ImageUrl          string        `json:"image_url"`
+	Policy            string        `json:"policy"`
+	VoteCount         int32         `json:"vote_count"`
+	WeightedVoteCount int64         `json:"weighted_vote_count"`
+	PartyID           sql.NullInt64 `json:"party_id"`
+	DistrictID        sql.NullInt64 `json:"district_id"`
+	ElectionID        int64         `json:"election_id"`
+	CreateAt          time.Time     `json:"create_at"`
+	Rank              float64       `json:"rank"`
 }
 
 func (q *Queries) ListCandidates(ctx context.Context, arg ListCandidatesParams) ([]ListCandidatesRow, error) {
-	rows, err := q.db.QueryContext(ctx, listCandidates, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, listCandidates,
+		arg.Search,
+		arg.PartyID,
+		arg.DistrictID,
+		arg.ElectionID,
+		arg.Withdrawn,
+		arg.AfterID,
+		arg.Sort,
+		arg.AfterName,
+		arg.AfterVotes,
+		arg.AfterCreateAt,
+		arg.AfterRank,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -152,7 +384,12 @@ func (q *Queries) ListCandidates(ctx context.Context, arg ListCandidatesParams)
 			&i.ImageUrl,
 			&i.Policy,
 			&i.VoteCount,
+			&i.WeightedVoteCount,
+			&i.PartyID,
+			&i.DistrictID,
+			&i.ElectionID,
 			&i.CreateAt,
+			&i.Rank,
 		); err != nil {
 			return nil, err
 		}
@@ -177,21 +414,25 @@ SELECT
   policy,
   vote_count,
   CONCAT(percentage, '%')::text as percentage,
+  weighted_vote_count,
+  CONCAT(weighted_percentage, '%')::text as weighted_percentage,
   create_at
  FROM candidates
-ORDER BY vote_count DESC
+ORDER BY weighted_vote_count DESC, vote_count DESC
 `
 
 type ListCandidatesResultRow struct {
-	ID         int64     `json:"id"`
-	Name       string    `json:"name"`
-	Dob        string    `json:"dob"`
-	BioLink    string    `json:"bio_link"`
-	ImageUrl   string    `json:"image_url"`
-	Policy     string    `json:"policy"`
-	VoteCount  int32     `json:"vote_count"`
-	Percentage string    `json:"percentage"`
-	CreateAt   time.Time `json:"create_at"`
+	ID                 int64     `json:"id"`
+	Name               string    `json:"name"`
+	Dob                string    `json:"dob"`
+	BioLink            string    `json:"bio_link"`
+	ImageUrl           string    `json:"image_url"`
+	Policy             string    `json:"policy"`
+	VoteCount          int32     `json:"vote_count"`
+	Percentage         string    `json:"percentage"`
+	WeightedVoteCount  int64     `json:"weighted_vote_count"`
+	WeightedPercentage string    `json:"weighted_percentage"`
+	CreateAt           time.Time `json:"create_at"`
 }
 
 func (q *Queries) ListCandidatesResult(ctx context.Context) ([]ListCandidatesResultRow, error) {
@@ -212,6 +453,8 @@ func (q *Queries) ListCandidatesResult(ctx context.Context) ([]ListCandidatesRes
 			&i.Policy,
 			&i.VoteCount,
 			&i.Percentage,
+			&i.WeightedVoteCount,
+			&i.WeightedPercentage,
 			&i.CreateAt,
 		); err != nil {
 			return nil, err
@@ -227,9 +470,162 @@ func (q *Queries) ListCandidatesResult(ctx context.Context) ([]ListCandidatesRes
 	return items, nil
 }
 
+const listElectionCandidatesResult = `-- name: ListElectionCandidatesResult :many
+SELECT 
+  id,
+  name,
+  dob,
+  bio_link,
+  image_url,
+  policy,
+  vote_count,
+  CONCAT(percentage, '%')::text as percentage,
+  weighted_vote_count,
+  CONCAT(weighted_percentage, '%')::text as weighted_percentage,
+  create_at,
+  withdrawn_at
+ FROM candidates
+WHERE election_id = $1 AND COALESCE(contest_id, 0) = $2 AND (withdrawn_at IS NULL OR vote_count > 0)
+ORDER BY weighted_vote_count DESC, vote_count DESC
+`
+
+type ListElectionCandidatesResultRow struct {
+	ID                 int64        `json:"id"`
+	Name               string       `json:"name"`
+	Dob                string       `json:"dob"`
+	BioLink            string       `json:"bio_link"`
+	ImageUrl           string       `json:"image_url"`
+	Policy             string       `json:"policy"`
+	VoteCount          int32        `json:"vote_count"`
+	Percentage         string       `json:"percentage"`
+	WeightedVoteCount  int64        `json:"weighted_vote_count"`
+	WeightedPercentage string       `json:"weighted_percentage"`
+	CreateAt           time.Time    `json:"create_at"`
+	WithdrawnAt        sql.NullTime `json:"withdrawn_at"`
+}
+
+type ListElectionCandidatesResultParams struct {
+	ElectionID int64 `json:"election_id"`
+	ContestID  int64 `json:"contest_id"`
+}
+
+func (q *Queries) ListElectionCandidatesResult(ctx context.Context, arg ListElectionCandidatesResultParams) ([]ListElectionCandidatesResultRow, error) {
+	rows, err := q.db.QueryContext(ctx, listElectionCandidatesResult, arg.ElectionID, arg.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListElectionCandidatesResultRow{}
+	for rows.Next() {
+		var i ListElectionCandidatesResultRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.BioLink,
+			&i.ImageUrl,
+			&i.Policy,
+			&i.VoteCount,
+			&i.Percentage,
+			&i.WeightedVoteCount,
+			&i.WeightedPercentage,
+			&i.CreateAt,
+			&i.WithdrawnAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recountUserCandidates = `-- name: RecountUserCandidates :exec
+UPDATE candidates SET vote_count = tally.vote_count, percentage = (
+    (
+      tally.vote_count/
+      (select COUNT(*) from users where 'VOTE'=ANY("permission"))::float
+    )*100
+  ), weighted_vote_count = tally.weighted_vote_count, weighted_percentage = (
+    (
+      tally.weighted_vote_count/
+      election_total_weight(candidates.election_id)::float
+    )*100
+  )
+FROM (
+  SELECT c.id, (
+    select COUNT(*) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+  ) AS vote_count, (
+    select COALESCE(SUM(v.weight), 0) from votes v where v.candidate_id = c.id AND v.superseded_at IS NULL
+  ) AS weighted_vote_count
+  FROM candidates c
+  WHERE c.id IN (SELECT candidate_id FROM votes WHERE vote_national_id = $1 AND election_id = $2)
+) AS tally
+WHERE candidates.id = tally.id
+`
+
+type RecountUserCandidatesParams struct {
+	VoteNationalID string `json:"vote_national_id"`
+	ElectionID     int64  `json:"election_id"`
+}
+
+func (q *Queries) RecountUserCandidates(ctx context.Context, arg RecountUserCandidatesParams) error {
+	_, err := q.db.ExecContext(ctx, recountUserCandidates, arg.VoteNationalID, arg.ElectionID)
+	return err
+}
+
+const restoreCandidate = `-- name: RestoreCandidate :one
+UPDATE candidates
+SET withdrawn_at = NULL, withdrawn_reason = '', withdrawn_by = NULL
+WHERE id = $1 AND withdrawn_at IS NOT NULL
+RETURNING id, name, dob, bio_link, image_url, policy, vote_count, percentage, create_at, district_id, weighted_vote_count, weighted_percentage, election_id, policy_items, links, version, edited_by, party_id, withdrawn_at, withdrawn_reason, withdrawn_by, contest_id
+`
+
+func (q *Queries) RestoreCandidate(ctx context.Context, id int64) (Candidate, error) {
+	row := q.db.QueryRowContext(ctx, restoreCandidate, id)
+	var i Candidate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.BioLink,
+		&i.ImageUrl,
+		&i.Policy,
+		&i.VoteCount,
+		&i.Percentage,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.WeightedVoteCount,
+		&i.WeightedPercentage,
+		&i.ElectionID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+		&i.EditedBy,
+		&i.PartyID,
+		&i.WithdrawnAt,
+		&i.WithdrawnReason,
+		&i.WithdrawnBy,
+		&i.ContestID,
+	)
+	return i, err
+}
+
 const updateCandidate = `-- name: UpdateCandidate :one
-UPDATE candidates SET name = $2, dob = $3, bio_link = $4, image_url = $5, policy = $6
-WHERE id = $1
+WITH open_election AS (
+  SELECT e.id FROM elections e
+  JOIN candidates c ON c.election_id = e.id
+  WHERE c.id = $1 AND e.voting_started_at IS NULL
+  FOR SHARE OF e
+)
+UPDATE candidates SET name = $2, dob = $3, bio_link = $4, image_url = $5, policy = $6, district_id = $7,
+  party_id = $8, policy_items = $9, links = $10, edited_by = $11
+WHERE id = $1 AND election_id IN (SELECT id FROM open_election)
 RETURNING   
   id,
   name,
@@ -238,27 +634,42 @@ RETURNING
   image_url,
   policy,
   vote_count,
-  create_at
+  create_at,
+  district_id,
+  party_id,
+  policy_items,
+  links,
+  version
 `
 
 type UpdateCandidateParams struct {
-	ID       int64  `json:"id"`
-	Name     string `json:"name"`
-	Dob      string `json:"dob"`
-	BioLink  string `json:"bio_link"`
-	ImageUrl string `json:"image_url"`
-	Policy   string `json:"policy"`
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Dob         string          `json:"dob"`
+	BioLink     string          `json:"bio_link"`
+	ImageUrl    string          `json:"image_url"`
+	Policy      string          `json:"policy"`
+	DistrictID  sql.NullInt64   `json:"district_id"`
+	PartyID     sql.NullInt64   `json:"party_id"`
+	PolicyItems json.RawMessage `json:"policy_items"`
+	Links       json.RawMessage `json:"links"`
+	EditedBy    sql.NullString  `json:"edited_by"`
 }
 
 type UpdateCandidateRow struct {
-	ID        int64     `json:"id"`
-	Name      string    `json:"name"`
-	Dob       string    `json:"dob"`
-	BioLink   string    `json:"bio_link"`
-	ImageUrl  string    `json:"image_url"`
-	Policy    string    `json:"policy"`
-	VoteCount int32     `json:"vote_count"`
-	CreateAt  time.Time `json:"create_at"`
+	ID          int64           `json:"id"`
+	Name        string          `json:"name"`
+	Dob         string          `json:"dob"`
+	BioLink     string          `json:"bio_link"`
+	ImageUrl    string          `json:"image_url"`
+	Policy      string          `json:"policy"`
+	VoteCount   int32           `json:"vote_count"`
+	CreateAt    time.Time       `json:"create_at"`
+	DistrictID  sql.NullInt64   `json:"district_id"`
+	PartyID     sql.NullInt64   `json:"party_id"`
+	PolicyItems json.RawMessage `json:"policy_items"`
+	Links       json.RawMessage `json:"links"`
+	Version     int32           `json:"version"`
 }
 
 func (q *Queries) UpdateCandidate(ctx context.Context, arg UpdateCandidateParams) (UpdateCandidateRow, error) {
@@ -269,6 +680,11 @@ func (q *Queries) UpdateCandidate(ctx context.Context, arg UpdateCandidateParams
 		arg.BioLink,
 		arg.ImageUrl,
 		arg.Policy,
+		arg.DistrictID,
+		arg.PartyID,
+		arg.PolicyItems,
+		arg.Links,
+		arg.EditedBy,
 	)
 	var i UpdateCandidateRow
 	err := row.Scan(
@@ -280,6 +696,108 @@ func (q *Queries) UpdateCandidate(ctx context.Context, arg UpdateCandidateParams
 		&i.Policy,
 		&i.VoteCount,
 		&i.CreateAt,
+		&i.DistrictID,
+		&i.PartyID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+	)
+	return i, err
+}
+
+const updateCandidateImage = `-- name: UpdateCandidateImage :one
+WITH open_election AS (
+  SELECT e.id FROM elections e
+  JOIN candidates c ON c.election_id = e.id
+  WHERE c.id = $1 AND e.voting_started_at IS NULL
+  FOR SHARE OF e
+)
+UPDATE candidates SET image_url = $2, edited_by = $3
+WHERE id = $1 AND election_id IN (SELECT id FROM open_election)
+RETURNING id, name, dob, bio_link, image_url, policy, vote_count, percentage, create_at, district_id, weighted_vote_count, weighted_percentage, election_id, policy_items, links, version, edited_by, party_id, withdrawn_at, withdrawn_reason, withdrawn_by, contest_id
+`
+
+type UpdateCandidateImageParams struct {
+	ID       int64          `json:"id"`
+	ImageUrl string         `json:"image_url"`
+	EditedBy sql.NullString `json:"edited_by"`
+}
+
+func (q *Queries) UpdateCandidateImage(ctx context.Context, arg UpdateCandidateImageParams) (Candidate, error) {
+	row := q.db.QueryRowContext(ctx, updateCandidateImage, arg.ID, arg.ImageUrl, arg.EditedBy)
+	var i Candidate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.BioLink,
+		&i.ImageUrl,
+		&i.Policy,
+		&i.VoteCount,
+		&i.Percentage,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.WeightedVoteCount,
+		&i.WeightedPercentage,
+		&i.ElectionID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+		&i.EditedBy,
+		&i.PartyID,
+		&i.WithdrawnAt,
+		&i.WithdrawnReason,
+		&i.WithdrawnBy,
+		&i.ContestID,
+	)
+	return i, err
+}
+
+const withdrawCandidate = `-- name: WithdrawCandidate :one
+UPDATE candidates
+SET withdrawn_at = now(), withdrawn_reason = $1, withdrawn_by = $2
+WHERE id = $3 AND withdrawn_at IS NULL AND (vote_count = 0 OR $4::boolean)
+RETURNING id, name, dob, bio_link, image_url, policy, vote_count, percentage, create_at, district_id, weighted_vote_count, weighted_percentage, election_id, policy_items, links, version, edited_by, party_id, withdrawn_at, withdrawn_reason, withdrawn_by, contest_id
+`
+
+type WithdrawCandidateParams struct {
+	WithdrawnReason string         `json:"withdrawn_reason"`
+	WithdrawnBy     sql.NullString `json:"withdrawn_by"`
+	ID              int64          `json:"id"`
+	AllowVoted      bool           `json:"allow_voted"`
+}
+
+func (q *Queries) WithdrawCandidate(ctx context.Context, arg WithdrawCandidateParams) (Candidate, error) {
+	row := q.db.QueryRowContext(ctx, withdrawCandidate,
+		arg.WithdrawnReason,
+		arg.WithdrawnBy,
+		arg.ID,
+		arg.AllowVoted,
+	)
+	var i Candidate
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.BioLink,
+		&i.ImageUrl,
+		&i.Policy,
+		&i.VoteCount,
+		&i.Percentage,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.WeightedVoteCount,
+		&i.WeightedPercentage,
+		&i.ElectionID,
+		&i.PolicyItems,
+		&i.Links,
+		&i.Version,
+		&i.EditedBy,
+		&i.PartyID,
+		&i.WithdrawnAt,
+		&i.WithdrawnReason,
+		&i.WithdrawnBy,
+		&i.ContestID,
 	)
 	return i, err
 }
@@ -0,0 +1,55 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandidateRevisions(t *testing.T) {
+	candidate := CreateEditableCandidate(t)
+	require.Equal(t, int32(1), candidate.Version)
+
+	arg := UpdateCandidateParams{
+		ID:          candidate.ID,
+		Name:        candidate.Name,
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      util.RandomString(15),
+		PartyID:     candidate.PartyID,
+		PolicyItems: json.RawMessage(`[{"title": "Health", "body": "Free clinics", "category": "welfare"}]`),
+		Links:       candidate.Links,
+	}
+	updated, err := testQueries.UpdateCandidate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), updated.Version)
+
+	// saving the same profile again is not a new version
+	updated, err = testQueries.UpdateCandidate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), updated.Version)
+
+	revisions, err := testQueries.ListCandidateRevisions(context.Background(), candidate.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 2)
+	require.Equal(t, int32(1), revisions[0].Version)
+	require.Equal(t, int32(2), revisions[1].Version)
+
+	revision, err := testQueries.GetCandidateRevision(context.Background(), GetCandidateRevisionParams{
+		CandidateID: candidate.ID,
+		Version:     1,
+	})
+	require.NoError(t, err)
+
+	var profile map[string]interface{}
+	err = json.Unmarshal(revision.Profile, &profile)
+	require.NoError(t, err)
+	require.Equal(t, candidate.Name, profile["name"])
+	require.Equal(t, candidate.Policy, profile["policy"])
+	require.Empty(t, profile["policy_items"])
+}
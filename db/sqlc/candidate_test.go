@@ -3,6 +3,7 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -33,14 +34,17 @@ func TestGetCandidate(t *testing.T) {
 }
 
 func TestUpdateCandidate(t *testing.T) {
-	candidate := CreateCandidate(t)
+	candidate := CreateEditableCandidate(t)
 	arg := UpdateCandidateParams{
-		ID:       candidate.ID,
-		Name:     util.RandomName(),
-		Dob:      util.RandomString(10),
-		BioLink:  util.RandomBioLink(),
-		ImageUrl: util.RandomImageLink(),
-		Policy:   util.RandomString(15),
+		ID:          candidate.ID,
+		Name:        util.RandomName(),
+		Dob:         util.RandomString(10),
+		BioLink:     util.RandomBioLink(),
+		ImageUrl:    util.RandomImageLink(),
+		Policy:      util.RandomString(15),
+		PartyID:     sql.NullInt64{Int64: CreateParty(t).ID, Valid: true},
+		PolicyItems: json.RawMessage("[]"),
+		Links:       json.RawMessage("[]"),
 	}
 	updatedCandidate, err := testQueries.UpdateCandidate(context.Background(), arg)
 	require.NoError(t, err)
@@ -52,17 +56,164 @@ func TestUpdateCandidate(t *testing.T) {
 	require.Equal(t, arg.BioLink, updatedCandidate.BioLink)
 	require.Equal(t, arg.ImageUrl, updatedCandidate.ImageUrl)
 	require.Equal(t, arg.Policy, updatedCandidate.Policy)
+	require.Equal(t, arg.PartyID, updatedCandidate.PartyID)
+	require.Equal(t, candidate.Version+1, updatedCandidate.Version)
+}
+
+func TestUpdateCandidateVotingStarted(t *testing.T) {
+	candidate := CreateEditableCandidate(t)
+
+	row, err := testQueries.GetCandidate(context.Background(), candidate.ID)
+	require.NoError(t, err)
+	require.False(t, row.ElectionVotingStarted)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: CreateUser(t).NationalID,
+		CandidateID:    candidate.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+
+	row, err = testQueries.GetCandidate(context.Background(), candidate.ID)
+	require.NoError(t, err)
+	require.True(t, row.ElectionVotingStarted)
+
+	_, err = testQueries.UpdateCandidate(context.Background(), UpdateCandidateParams{
+		ID:          candidate.ID,
+		Name:        util.RandomName(),
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      candidate.Policy,
+		PolicyItems: candidate.PolicyItems,
+		Links:       candidate.Links,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.UpdateCandidateImage(context.Background(), UpdateCandidateImageParams{
+		ID:       candidate.ID,
+		ImageUrl: util.RandomImageLink(),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
 }
 
-func TestDeleteCandidate(t *testing.T) {
+func TestUpdateCandidateConcurrentBallot(t *testing.T) {
+	candidate := CreateEditableCandidate(t)
+	other, err := testQueries.CreateRunoffCandidate(context.Background(), CreateRunoffCandidateParams{
+		ID:         CreateCandidate(t).ID,
+		ElectionID: candidate.ElectionID,
+	})
+	require.NoError(t, err)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	// the first ballot of the election is not committed yet while another candidate is edited
+	tx, err := testDB.BeginTx(context.Background(), nil)
+	require.NoError(t, err)
+
+	_, err = New(tx).CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: CreateUser(t).NationalID,
+		CandidateID:    candidate.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+
+	errs := make(chan error)
+	go func() {
+		_, err := testQueries.UpdateCandidate(context.Background(), UpdateCandidateParams{
+			ID:          other.ID,
+			Name:        util.RandomName(),
+			Dob:         other.Dob,
+			BioLink:     other.BioLink,
+			ImageUrl:    other.ImageUrl,
+			Policy:      other.Policy,
+			PolicyItems: other.PolicyItems,
+			Links:       other.Links,
+		})
+		errs <- err
+	}()
+
+	require.NoError(t, tx.Commit())
+	require.ErrorIs(t, <-errs, sql.ErrNoRows)
+
+	election, err := testQueries.GetElection(context.Background(), candidate.ElectionID)
+	require.NoError(t, err)
+	require.True(t, election.VotingStartedAt.Valid)
+}
+
+func TestWithdrawCandidate(t *testing.T) {
 	candidate1 := CreateCandidate(t)
-	err := testQueries.DeleteCandidate(context.Background(), candidate1.ID)
+	user := CreateUser(t)
+
+	arg := WithdrawCandidateParams{
+		ID:              candidate1.ID,
+		WithdrawnReason: util.RandomString(20),
+		WithdrawnBy:     sql.NullString{String: user.NationalID, Valid: true},
+	}
+	candidate2, err := testQueries.WithdrawCandidate(context.Background(), arg)
 	require.NoError(t, err)
+	require.True(t, candidate2.WithdrawnAt.Valid)
+	require.Equal(t, arg.WithdrawnReason, candidate2.WithdrawnReason)
+	require.Equal(t, arg.WithdrawnBy, candidate2.WithdrawnBy)
 
-	candidate2, err := testQueries.GetCandidate(context.Background(), candidate1.ID)
-	require.Error(t, err)
+	_, err = testQueries.WithdrawCandidate(context.Background(), arg)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+
+	candidate3, err := testQueries.RestoreCandidate(context.Background(), candidate1.ID)
+	require.NoError(t, err)
+	require.False(t, candidate3.WithdrawnAt.Valid)
+	require.Empty(t, candidate3.WithdrawnReason)
+
+	_, err = testQueries.RestoreCandidate(context.Background(), candidate1.ID)
+	require.EqualError(t, err, sql.ErrNoRows.Error())
+}
+
+func TestWithdrawVotedCandidate(t *testing.T) {
+	vote := CreateVote(t)
+
+	arg := WithdrawCandidateParams{
+		ID: vote.CandidateID,
+	}
+	_, err := testQueries.WithdrawCandidate(context.Background(), arg)
 	require.EqualError(t, err, sql.ErrNoRows.Error())
-	require.Empty(t, candidate2)
+
+	arg.AllowVoted = true
+	candidate, err := testQueries.WithdrawCandidate(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, candidate.WithdrawnAt.Valid)
+
+	count, err := testQueries.CountCandidates(context.Background(), CountCandidatesParams{
+		ElectionID: candidate.ElectionID,
+		Withdrawn:  true,
+	})
+	require.NoError(t, err)
+	require.NotZero(t, count)
+
+	// the counted votes stay in the result, a withdrawn candidate without votes does not
+	unvoted, err := testQueries.WithdrawCandidate(context.Background(), WithdrawCandidateParams{
+		ID: CreateCandidate(t).ID,
+	})
+	require.NoError(t, err)
+
+	results, err := testQueries.ListElectionCandidatesResult(context.Background(), ListElectionCandidatesResultParams{
+		ElectionID: candidate.ElectionID,
+	})
+	require.NoError(t, err)
+
+	var listed bool
+	for _, result := range results {
+		require.NotEqual(t, unvoted.ID, result.ID)
+		if result.ID == candidate.ID {
+			listed = true
+			require.True(t, result.WithdrawnAt.Valid)
+			require.NotZero(t, result.VoteCount)
+		}
+	}
+	require.True(t, listed)
 }
 
 func TestListCandidates(t *testing.T) {
@@ -84,6 +235,89 @@ func TestListCandidates(t *testing.T) {
 	}
 }
 
+func TestSearchCandidates(t *testing.T) {
+	party := CreateParty(t)
+	policy := util.RandomString(12)
+
+	var matched []Candidate
+	for i := 0; i < 3; i++ {
+		arg := CreateCandidateParams{
+			Name:        util.RandomName(),
+			Dob:         util.RandomDob(),
+			BioLink:     util.RandomBioLink(),
+			ImageUrl:    util.RandomImageLink(),
+			Policy:      policy + " " + util.RandomString(15),
+			PartyID:     sql.NullInt64{Int64: party.ID, Valid: true},
+			PolicyItems: json.RawMessage("[]"),
+			Links:       json.RawMessage("[]"),
+			ElectionID:  1,
+		}
+		candidate, err := testQueries.CreateCandidate(context.Background(), arg)
+		require.NoError(t, err)
+		matched = append(matched, candidate)
+	}
+	CreateCandidate(t)
+
+	count, err := testQueries.CountCandidates(context.Background(), CountCandidatesParams{
+		Search:  policy,
+		PartyID: party.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(len(matched)), count)
+
+	candidates, err := testQueries.ListCandidates(context.Background(), ListCandidatesParams{
+		Search:  policy,
+		PartyID: party.ID,
+		Sort:    "-created",
+		Limit:   5,
+	})
+	require.NoError(t, err)
+	require.Len(t, candidates, len(matched))
+
+	for i, candidate := range candidates {
+		require.Equal(t, matched[len(matched)-1-i].ID, candidate.ID)
+		require.Equal(t, party.ID, candidate.PartyID.Int64)
+	}
+}
+
+func TestListCandidatesAfter(t *testing.T) {
+	party := CreateParty(t)
+
+	for i := 0; i < 3; i++ {
+		arg := CreateCandidateParams{
+			Name:        util.RandomName(),
+			Dob:         util.RandomDob(),
+			BioLink:     util.RandomBioLink(),
+			ImageUrl:    util.RandomImageLink(),
+			Policy:      util.RandomString(15),
+			PartyID:     sql.NullInt64{Int64: party.ID, Valid: true},
+			PolicyItems: json.RawMessage("[]"),
+			Links:       json.RawMessage("[]"),
+			ElectionID:  1,
+		}
+		_, err := testQueries.CreateCandidate(context.Background(), arg)
+		require.NoError(t, err)
+	}
+
+	all, err := testQueries.ListCandidates(context.Background(), ListCandidatesParams{
+		PartyID: party.ID,
+		Sort:    "-name",
+		Limit:   3,
+	})
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+
+	after, err := testQueries.ListCandidates(context.Background(), ListCandidatesParams{
+		PartyID:   party.ID,
+		Sort:      "-name",
+		AfterID:   all[0].ID,
+		AfterName: all[0].Name,
+		Limit:     3,
+	})
+	require.NoError(t, err)
+	require.Equal(t, all[1:], after)
+}
+
 func TestListCandidatesResult(t *testing.T) {
 	for i := 0; i < 3; i++ {
 		CreateCandidate(t)
@@ -99,12 +333,15 @@ func TestListCandidatesResult(t *testing.T) {
 
 func CreateCandidate(t *testing.T) Candidate {
 	arg := CreateCandidateParams{
-		Name:      util.RandomName(),
-		Dob:       util.RandomDob(),
-		BioLink:   util.RandomBioLink(),
-		ImageUrl:  util.RandomImageLink(),
-		Policy:    util.RandomString(15),
-		VoteCount: 0,
+		Name:        util.RandomName(),
+		Dob:         util.RandomDob(),
+		BioLink:     util.RandomBioLink(),
+		ImageUrl:    util.RandomImageLink(),
+		Policy:      util.RandomString(15),
+		VoteCount:   0,
+		PolicyItems: json.RawMessage("[]"),
+		Links:       json.RawMessage("[]"),
+		ElectionID:  1,
 	}
 
 	candidate, err := testQueries.CreateCandidate(context.Background(), arg)
@@ -121,3 +358,23 @@ func CreateCandidate(t *testing.T) Candidate {
 	require.NotZero(t, candidate.CreateAt)
 	return candidate
 }
+
+// CreateEditableCandidate creates a candidate in an election without ballots,
+// its profile may still change
+func CreateEditableCandidate(t *testing.T) Candidate {
+	election, err := testQueries.CreateElection(context.Background(), CreateElectionParams{
+		Name:         util.RandomName(),
+		WinnerRule:   util.WinnerRulePlurality,
+		TieBreak:     util.TieBreakLottery,
+		TieBreakSeed: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	candidate, err := testQueries.CreateRunoffCandidate(context.Background(), CreateRunoffCandidateParams{
+		ID:         CreateCandidate(t).ID,
+		ElectionID: election.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, election.ID, candidate.ElectionID)
+	return candidate
+}
@@ -0,0 +1,144 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateContest(t *testing.T) {
+	CreateContest(t, CreateElection(t).ID)
+}
+
+func TestGetContest(t *testing.T) {
+	contest1 := CreateContest(t, CreateElection(t).ID)
+
+	contest2, err := testQueries.GetContest(context.Background(), contest1.ID)
+	require.NoError(t, err)
+	require.Equal(t, contest1, contest2)
+}
+
+func TestListElectionContests(t *testing.T) {
+	election := CreateElection(t)
+	for i := 0; i < 3; i++ {
+		CreateContest(t, election.ID)
+	}
+	CreateContest(t, CreateElection(t).ID)
+
+	contests, err := testQueries.ListElectionContests(context.Background(), election.ID)
+	require.NoError(t, err)
+	require.Len(t, contests, 3)
+	for _, contest := range contests {
+		require.Equal(t, election.ID, contest.ElectionID)
+	}
+
+	count, err := testQueries.CountElectionContests(context.Background(), election.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+}
+
+func TestDuplicateContestName(t *testing.T) {
+	contest := CreateContest(t, CreateElection(t).ID)
+
+	_, err := testQueries.CreateContest(context.Background(), CreateContestParams{
+		ElectionID: contest.ElectionID,
+		Name:       contest.Name,
+	})
+	require.Error(t, err)
+}
+
+func TestVotePerContest(t *testing.T) {
+	user := CreateUser(t)
+	election := CreateElection(t)
+	contest := CreateContest(t, election.ID)
+	president := CreateContestCandidate(t, election.ID, sql.NullInt64{})
+	treasurer := CreateContestCandidate(t, election.ID, sql.NullInt64{Int64: contest.ID, Valid: true})
+
+	candidates, err := testQueries.ListBallotCandidates(context.Background(), ListBallotCandidatesParams{
+		ElectionID: election.ID,
+	})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []ListBallotCandidatesRow{
+		{ID: president.ID},
+		{ID: treasurer.ID, ContestID: treasurer.ContestID},
+	}, candidates)
+
+	for _, candidate := range []Candidate{president, treasurer} {
+		receiptCode, err := util.NewReceiptCode()
+		require.NoError(t, err)
+
+		voted, err := testQueries.CreateVote(context.Background(), CreateVoteParams{
+			VoteNationalID: user.NationalID,
+			CandidateID:    candidate.ID,
+			ReceiptHash:    util.HashReceiptCode(receiptCode),
+		})
+		require.NoError(t, err)
+		require.Equal(t, candidate.ContestID, voted.ContestID)
+	}
+
+	// a second vote in the same contest is refused while revoting is disabled
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: user.NationalID,
+		CandidateID:    treasurer.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.Error(t, err)
+
+	turnout, err := testQueries.CountActiveVotes(context.Background(), election.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), turnout)
+}
+
+func CreateElection(t *testing.T) Election {
+	election, err := testQueries.CreateElection(context.Background(), CreateElectionParams{
+		Name:         util.RandomName(),
+		WinnerRule:   util.WinnerRulePlurality,
+		TieBreak:     util.TieBreakLottery,
+		TieBreakSeed: util.RandomString(32),
+	})
+	require.NoError(t, err)
+	return election
+}
+
+func CreateContest(t *testing.T, electionID int64) Contest {
+	arg := CreateContestParams{
+		ElectionID: electionID,
+		Name:       util.RandomString(10),
+	}
+
+	contest, err := testQueries.CreateContest(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, contest)
+
+	require.Equal(t, arg.ElectionID, contest.ElectionID)
+	require.Equal(t, arg.Name, contest.Name)
+	require.NotZero(t, contest.ID)
+	require.NotZero(t, contest.CreateAt)
+	return contest
+}
+
+func CreateContestCandidate(t *testing.T, electionID int64, contestID sql.NullInt64) Candidate {
+	candidate, err := testQueries.CreateCandidate(context.Background(), CreateCandidateParams{
+		Name:        util.RandomName(),
+		Dob:         util.RandomDob(),
+		BioLink:     util.RandomBioLink(),
+		ImageUrl:    util.RandomImageLink(),
+		Policy:      util.RandomString(15),
+		PolicyItems: json.RawMessage("[]"),
+		Links:       json.RawMessage("[]"),
+		ElectionID:  electionID,
+		ContestID:   contestID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, electionID, candidate.ElectionID)
+	require.Equal(t, contestID, candidate.ContestID)
+	return candidate
+}
@@ -0,0 +1,201 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDelegation(t *testing.T) {
+	CreateDelegation(t)
+}
+
+func TestCreateDelegationActiveGrantor(t *testing.T) {
+	delegation := CreateDelegation(t)
+	proxy := CreateUser(t)
+
+	_, err := testQueries.CreateDelegation(context.Background(), CreateDelegationParams{
+		GrantorNationalID: delegation.GrantorNationalID,
+		ProxyNationalID:   proxy.NationalID,
+		ElectionID:        delegation.ElectionID,
+		Status:            util.DelegationPending,
+	})
+	require.Error(t, err)
+}
+
+func TestCreateDelegationOtherElection(t *testing.T) {
+	delegation := CreateDelegation(t)
+	election := CreatePartyListElection(t)
+
+	other, err := testQueries.CreateDelegation(context.Background(), CreateDelegationParams{
+		GrantorNationalID: delegation.GrantorNationalID,
+		ProxyNationalID:   delegation.ProxyNationalID,
+		ElectionID:        election.ID,
+		Status:            util.DelegationPending,
+	})
+	require.NoError(t, err)
+	require.Equal(t, election.ID, other.ElectionID)
+}
+
+func TestCreateDelegationSelf(t *testing.T) {
+	user := CreateUser(t)
+
+	_, err := testQueries.CreateDelegation(context.Background(), CreateDelegationParams{
+		GrantorNationalID: user.NationalID,
+		ProxyNationalID:   user.NationalID,
+		ElectionID:        1,
+		Status:            util.DelegationPending,
+	})
+	require.Error(t, err)
+}
+
+func TestUpdateDelegationStatus(t *testing.T) {
+	delegation1 := CreateDelegation(t)
+	reviewer := CreateUser(t)
+
+	arg := UpdateDelegationStatusParams{
+		ID:         delegation1.ID,
+		Status:     util.DelegationApproved,
+		ReviewedBy: sql.NullString{String: reviewer.NationalID, Valid: true},
+	}
+
+	delegation2, err := testQueries.UpdateDelegationStatus(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, delegation1.ID, delegation2.ID)
+	require.Equal(t, util.DelegationApproved, delegation2.Status)
+	require.Equal(t, arg.ReviewedBy, delegation2.ReviewedBy)
+	require.True(t, delegation2.ReviewedAt.Valid)
+
+	approved, err := testQueries.GetApprovedDelegation(context.Background(), GetApprovedDelegationParams{
+		GrantorNationalID: delegation1.GrantorNationalID,
+		ProxyNationalID:   delegation1.ProxyNationalID,
+		ElectionID:        delegation1.ElectionID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, delegation1.ID, approved.ID)
+
+	// the approval does not carry over to another election
+	election := CreatePartyListElection(t)
+	_, err = testQueries.GetApprovedDelegation(context.Background(), GetApprovedDelegationParams{
+		GrantorNationalID: delegation1.GrantorNationalID,
+		ProxyNationalID:   delegation1.ProxyNationalID,
+		ElectionID:        election.ID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	count, err := testQueries.CountProxyDelegations(context.Background(), CountProxyDelegationsParams{
+		ProxyNationalID: delegation1.ProxyNationalID,
+		ElectionID:      delegation1.ElectionID,
+		Statuses:        []string{util.DelegationApproved},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
+
+func TestApproveDelegationTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	delegation1 := CreateDelegation(t)
+	grantor := CreateUser(t)
+	reviewer := CreateUser(t)
+
+	delegation2, err := testQueries.CreateDelegation(context.Background(), CreateDelegationParams{
+		GrantorNationalID: grantor.NationalID,
+		ProxyNationalID:   delegation1.ProxyNationalID,
+		ElectionID:        delegation1.ElectionID,
+		Status:            util.DelegationPending,
+	})
+	require.NoError(t, err)
+
+	arg := ApproveDelegationTxParams{
+		ID:          delegation1.ID,
+		ReviewedBy:  sql.NullString{String: reviewer.NationalID, Valid: true},
+		MaxApproved: 1,
+	}
+
+	result, err := store.ApproveDelegationTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.DelegationApproved, result.Delegation.Status)
+	require.Equal(t, arg.ReviewedBy, result.Delegation.ReviewedBy)
+
+	approved, err := testQueries.GetGrantorApprovedDelegation(context.Background(), GetGrantorApprovedDelegationParams{
+		GrantorNationalID: delegation1.GrantorNationalID,
+		ElectionID:        delegation1.ElectionID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, delegation1.ID, approved.ID)
+
+	// the delegation is not pending anymore
+	_, err = store.ApproveDelegationTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	// the proxy holds the maximum number of ballots
+	arg.ID = delegation2.ID
+	_, err = store.ApproveDelegationTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrProxyLimitReached)
+
+	pending, err := testQueries.GetDelegation(context.Background(), delegation2.ID)
+	require.NoError(t, err)
+	require.Equal(t, util.DelegationPending, pending.Status)
+}
+
+func TestProxyVote(t *testing.T) {
+	delegation := CreateDelegation(t)
+	candidate := CreateCandidate(t)
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	arg := CreateVoteParams{
+		VoteNationalID:   delegation.GrantorNationalID,
+		CandidateID:      candidate.ID,
+		ReceiptHash:      util.HashReceiptCode(receiptCode),
+		CastByNationalID: sql.NullString{String: delegation.ProxyNationalID, Valid: true},
+	}
+
+	vote, err := testQueries.CreateVote(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.VoteNationalID, vote.VoteNationalID)
+	require.Equal(t, arg.CastByNationalID, vote.CastByNationalID)
+
+	voted, err := testQueries.HasElectionVoterVoted(context.Background(), HasElectionVoterVotedParams{
+		ElectionID: vote.ElectionID,
+		NationalID: delegation.GrantorNationalID,
+	})
+	require.NoError(t, err)
+	require.True(t, voted)
+
+	voted, err = testQueries.HasElectionVoterVoted(context.Background(), HasElectionVoterVotedParams{
+		ElectionID: vote.ElectionID,
+		NationalID: delegation.ProxyNationalID,
+	})
+	require.NoError(t, err)
+	require.False(t, voted)
+}
+
+func CreateDelegation(t *testing.T) Delegation {
+	grantor := CreateUser(t)
+	proxy := CreateUser(t)
+
+	arg := CreateDelegationParams{
+		GrantorNationalID: grantor.NationalID,
+		ProxyNationalID:   proxy.NationalID,
+		ElectionID:        1,
+		Status:            util.DelegationPending,
+	}
+
+	delegation, err := testQueries.CreateDelegation(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, delegation)
+
+	require.Equal(t, arg.GrantorNationalID, delegation.GrantorNationalID)
+	require.Equal(t, arg.ProxyNationalID, delegation.ProxyNationalID)
+	require.Equal(t, arg.ElectionID, delegation.ElectionID)
+	require.Equal(t, arg.Status, delegation.Status)
+	require.False(t, delegation.ReviewedBy.Valid)
+	require.NotZero(t, delegation.CreateAt)
+	return delegation
+}
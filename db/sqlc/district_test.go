@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateDistrict(t *testing.T) {
+	CreateDistrict(t)
+}
+
+func TestGetDistrict(t *testing.T) {
+	district1 := CreateDistrict(t)
+
+	district2, err := testQueries.GetDistrict(context.Background(), district1.ID)
+	require.NoError(t, err)
+	require.NotEmpty(t, district2)
+
+	require.Equal(t, district1.ID, district2.ID)
+	require.Equal(t, district1.Name, district2.Name)
+	require.WithinDuration(t, district1.CreateAt, district2.CreateAt, time.Second)
+}
+
+func TestListDistricts(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		CreateDistrict(t)
+	}
+
+	districts, err := testQueries.ListDistricts(context.Background())
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, len(districts), 3)
+}
+
+func TestListDistrictCandidatesResult(t *testing.T) {
+	district := CreateDistrict(t)
+
+	candidate, err := testQueries.CreateCandidate(context.Background(), CreateCandidateParams{
+		Name:        util.RandomName(),
+		Dob:         util.RandomDob(),
+		BioLink:     util.RandomBioLink(),
+		ImageUrl:    util.RandomImageLink(),
+		Policy:      util.RandomString(15),
+		DistrictID:  sql.NullInt64{Int64: district.ID, Valid: true},
+		PolicyItems: json.RawMessage("[]"),
+		Links:       json.RawMessage("[]"),
+		ElectionID:  1,
+	})
+	require.NoError(t, err)
+	CreateCandidate(t)
+
+	results, err := testQueries.ListDistrictCandidatesResult(context.Background(), district.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Equal(t, candidate.ID, results[0].ID)
+	require.Equal(t, "0%", results[0].Percentage)
+}
+
+func CreateDistrict(t *testing.T) District {
+	name := util.RandomString(10)
+
+	district, err := testQueries.CreateDistrict(context.Background(), name)
+	require.NoError(t, err)
+	require.NotEmpty(t, district)
+
+	require.Equal(t, name, district.Name)
+	require.NotZero(t, district.ID)
+	require.NotZero(t, district.CreateAt)
+	return district
+}
@@ -24,6 +24,39 @@ func (q *Queries) GetElectionProperty(ctx context.Context, name string) (Electio
 	return i, err
 }
 
+const listElectionProperties = `-- name: ListElectionProperties :many
+SELECT id, name, value, create_at FROM election_properties
+ORDER BY name
+`
+
+func (q *Queries) ListElectionProperties(ctx context.Context) ([]ElectionProperty, error) {
+	rows, err := q.db.QueryContext(ctx, listElectionProperties)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ElectionProperty{}
+	for rows.Next() {
+		var i ElectionProperty
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Value,
+			&i.CreateAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updateElectionProperty = `-- name: UpdateElectionProperty :one
 UPDATE election_properties SET value = $2
 WHERE name = $1
@@ -32,3 +32,16 @@ func TestUpdateClosedElection(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, hasClosedElection)
 }
+
+func TestListElectionProperties(t *testing.T) {
+	properties, err := testQueries.ListElectionProperties(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, properties)
+
+	names := make([]string, len(properties))
+	for i, property := range properties {
+		names[i] = property.Name
+	}
+	require.Contains(t, names, util.ElectionClosed)
+	require.IsIncreasing(t, names)
+}
@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetElection(t *testing.T) {
+	election, err := testQueries.GetElection(context.Background(), 1)
+	require.NoError(t, err)
+	require.NotEmpty(t, election.Name)
+	require.NotEmpty(t, election.TieBreakSeed)
+}
+
+func TestListElectionStates(t *testing.T) {
+	election, err := testQueries.GetElection(context.Background(), 1)
+	require.NoError(t, err)
+
+	states, err := testQueries.ListElectionStates(context.Background())
+	require.NoError(t, err)
+	require.NotEmpty(t, states)
+	require.Equal(t, election.ID, states[0].ID)
+	require.Equal(t, election.Closed, states[0].Closed)
+	require.GreaterOrEqual(t, states[0].ActiveVotes, int64(0))
+}
+
+func TestUpdateElectionRules(t *testing.T) {
+	election1, err := testQueries.GetElection(context.Background(), 1)
+	require.NoError(t, err)
+
+	arg := UpdateElectionRulesParams{
+		ID:               election1.ID,
+		WinnerRule:       util.WinnerRuleAbsoluteMajority,
+		QuorumPercentage: 40,
+		TieBreak:         util.TieBreakLottery,
+		TieBreakSeed:     util.RandomString(32),
+		BallotType:       util.BallotTypePartyList,
+		Seats:            5,
+		SeatMethod:       util.SeatMethodSainteLague,
+	}
+
+	election2, err := testQueries.UpdateElectionRules(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.WinnerRule, election2.WinnerRule)
+	require.Equal(t, arg.QuorumPercentage, election2.QuorumPercentage)
+	require.Equal(t, arg.TieBreak, election2.TieBreak)
+	require.Equal(t, arg.TieBreakSeed, election2.TieBreakSeed)
+	require.Equal(t, arg.BallotType, election2.BallotType)
+	require.Equal(t, arg.Seats, election2.Seats)
+	require.Equal(t, arg.SeatMethod, election2.SeatMethod)
+
+	arg.WinnerRule = "RANKED"
+	_, err = testQueries.UpdateElectionRules(context.Background(), arg)
+	require.Error(t, err)
+
+	_, err = testQueries.UpdateElectionRules(context.Background(), UpdateElectionRulesParams{
+		ID:               election1.ID,
+		WinnerRule:       election1.WinnerRule,
+		QuorumPercentage: election1.QuorumPercentage,
+		TieBreak:         election1.TieBreak,
+		TieBreakSeed:     election1.TieBreakSeed,
+		BallotType:       election1.BallotType,
+		Seats:            election1.Seats,
+		SeatMethod:       election1.SeatMethod,
+	})
+	require.NoError(t, err)
+}
+
+func TestCreateRunoffTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	election, err := testQueries.CreateElection(context.Background(), CreateElectionParams{
+		Name:         util.RandomName(),
+		WinnerRule:   util.WinnerRuleAbsoluteMajority,
+		TieBreak:     util.TieBreakRunoff,
+		TieBreakSeed: util.RandomString(32),
+	})
+	require.NoError(t, err)
+	require.False(t, election.Closed)
+
+	candidate1 := CreateCandidate(t)
+	candidate2 := CreateCandidate(t)
+	vote := CreateVote(t)
+
+	arg := CreateRunoffTxParams{
+		Election:     election,
+		CandidateIDs: []int64{candidate1.ID, candidate2.ID},
+		TieBreakSeed: util.RandomString(32),
+	}
+
+	result, err := store.CreateRunoffTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.Election.Closed)
+	require.Equal(t, election.ID, result.Runoff.RunoffOfElectionID.Int64)
+	require.Equal(t, election.WinnerRule, result.Runoff.WinnerRule)
+	require.Equal(t, arg.TieBreakSeed, result.Runoff.TieBreakSeed)
+	require.NotEqual(t, election.TieBreakSeed, result.Runoff.TieBreakSeed)
+	require.False(t, result.Runoff.Closed)
+
+	require.Len(t, result.Candidates, 2)
+	require.Equal(t, candidate1.Name, result.Candidates[0].Name)
+	require.Equal(t, candidate2.Name, result.Candidates[1].Name)
+	for _, candidate := range result.Candidates {
+		require.Equal(t, result.Runoff.ID, candidate.ElectionID)
+		require.Zero(t, candidate.VoteCount)
+	}
+
+	runoff, err := testQueries.GetRunoffElection(context.Background(), sql.NullInt64{Int64: election.ID, Valid: true})
+	require.NoError(t, err)
+	require.Equal(t, result.Runoff.ID, runoff.ID)
+
+	// a voter of another election is still recorded as having voted there
+	voted, err := testQueries.HasElectionVoterVoted(context.Background(), HasElectionVoterVotedParams{
+		ElectionID: vote.ElectionID,
+		NationalID: vote.VoteNationalID,
+	})
+	require.NoError(t, err)
+	require.True(t, voted)
+
+	_, err = store.CreateRunoffTx(context.Background(), arg)
+	require.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetElectionVoterWeight(t *testing.T) {
+	user := CreateUser(t)
+	candidate := CreateEditableCandidate(t)
+
+	arg := SetElectionVoterWeightParams{
+		ElectionID: candidate.ElectionID,
+		NationalID: user.NationalID,
+		VoteWeight: util.RandomInt(2, 1000),
+	}
+
+	voter, err := testQueries.SetElectionVoterWeight(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.ElectionID, voter.ElectionID)
+	require.Equal(t, arg.NationalID, voter.NationalID)
+	require.Equal(t, arg.VoteWeight, voter.VoteWeight)
+	require.NotZero(t, voter.CreateAt)
+
+	arg.VoteWeight++
+	voter, err = testQueries.SetElectionVoterWeight(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.VoteWeight, voter.VoteWeight)
+
+	arg.VoteWeight = 0
+	_, err = testQueries.SetElectionVoterWeight(context.Background(), arg)
+	require.Error(t, err)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: CreateUser(t).NationalID,
+		CandidateID:    candidate.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+
+	arg.VoteWeight = 1
+	_, err = testQueries.SetElectionVoterWeight(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCopyElectionVoters(t *testing.T) {
+	user := CreateUser(t)
+	from := CreateEditableCandidate(t).ElectionID
+	candidate := CreateEditableCandidate(t)
+
+	voter, err := testQueries.SetElectionVoterWeight(context.Background(), SetElectionVoterWeightParams{
+		ElectionID: from,
+		NationalID: user.NationalID,
+		VoteWeight: util.RandomInt(2, 1000),
+	})
+	require.NoError(t, err)
+
+	err = testQueries.CopyElectionVoters(context.Background(), CopyElectionVotersParams{
+		ToElectionID:   candidate.ElectionID,
+		FromElectionID: from,
+	})
+	require.NoError(t, err)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	vote, err := testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: user.NationalID,
+		CandidateID:    candidate.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+	require.Equal(t, voter.VoteWeight, vote.Weight)
+}
@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateEmailVerification(t *testing.T) {
+	CreateEmailVerification(t, CreateUser(t))
+}
+
+func TestGetLatestEmailVerification(t *testing.T) {
+	user := CreateUser(t)
+
+	CreateEmailVerification(t, user)
+	verification2 := CreateEmailVerification(t, user)
+
+	latest, err := testQueries.GetLatestEmailVerification(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.Equal(t, verification2.ID, latest.ID)
+}
+
+func TestVerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := CreateUser(t)
+	verification := CreateEmailVerification(t, user)
+
+	result, err := store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{TokenHash: verification.TokenHash})
+	require.NoError(t, err)
+	require.Equal(t, user.NationalID, result.User.NationalID)
+	require.True(t, result.User.VerifiedAt.Valid)
+
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{TokenHash: verification.TokenHash})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestVerifyChangedEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := CreateUser(t)
+	verification := CreateEmailVerification(t, user)
+
+	_, err := testQueries.UpdateUserProfile(context.Background(), UpdateUserProfileParams{
+		NationalID: user.NationalID,
+		FullName:   user.FullName,
+		Email:      util.RandomEmail(),
+	})
+	require.NoError(t, err)
+
+	_, err = store.VerifyEmailTx(context.Background(), VerifyEmailTxParams{TokenHash: verification.TokenHash})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	user2, err := testQueries.GetUser(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.False(t, user2.VerifiedAt.Valid)
+}
+
+func CreateEmailVerification(t *testing.T, user User) EmailVerification {
+	arg := CreateEmailVerificationParams{
+		NationalID: user.NationalID,
+		Email:      user.Email,
+		TokenHash:  util.HashSecretToken(util.RandomString(32)),
+		ExpiredAt:  time.Now().Add(time.Hour),
+	}
+
+	verification, err := testQueries.CreateEmailVerification(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, verification)
+
+	require.Equal(t, arg.NationalID, verification.NationalID)
+	require.Equal(t, arg.Email, verification.Email)
+	require.Equal(t, arg.TokenHash, verification.TokenHash)
+	require.False(t, verification.UsedAt.Valid)
+	require.NotZero(t, verification.CreateAt)
+	return verification
+}
@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBallotMeasureTx(t *testing.T) {
+	CreateBallotMeasure(t)
+}
+
+func TestCreateMeasureVote(t *testing.T) {
+	result := CreateBallotMeasure(t)
+	user := CreateUser(t)
+
+	arg := CreateMeasureVoteParams{
+		VoteNationalID: user.NationalID,
+		MeasureID:      result.Measure.ID,
+		OptionID:       result.Options[0].ID,
+	}
+
+	vote, err := testQueries.CreateMeasureVote(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.VoteNationalID, vote.VoteNationalID)
+	require.Equal(t, arg.OptionID, vote.OptionID)
+	require.Equal(t, int64(1), vote.Weight)
+	require.False(t, vote.SupersededAt.Valid)
+
+	arg.OptionID = result.Options[1].ID
+	_, err = testQueries.CreateMeasureVote(context.Background(), arg)
+	require.Error(t, err)
+
+	rows, err := testQueries.ListMeasureOptionsResult(context.Background())
+	require.NoError(t, err)
+	for _, row := range rows {
+		switch row.ID {
+		case result.Options[0].ID:
+			require.Equal(t, int64(1), row.VoteCount)
+		case result.Options[1].ID:
+			require.Zero(t, row.VoteCount)
+		}
+	}
+}
+
+func TestMeasureRevote(t *testing.T) {
+	setRevoteEnabled(t, true)
+	defer setRevoteEnabled(t, false)
+
+	result := CreateBallotMeasure(t)
+	user := CreateUser(t)
+
+	arg := CreateMeasureVoteParams{
+		VoteNationalID: user.NationalID,
+		MeasureID:      result.Measure.ID,
+		OptionID:       result.Options[0].ID,
+	}
+	_, err := testQueries.CreateMeasureVote(context.Background(), arg)
+	require.NoError(t, err)
+
+	arg.OptionID = result.Options[1].ID
+	_, err = testQueries.CreateMeasureVote(context.Background(), arg)
+	require.NoError(t, err)
+
+	rows, err := testQueries.ListMeasureOptionsResult(context.Background())
+	require.NoError(t, err)
+	for _, row := range rows {
+		switch row.ID {
+		case result.Options[0].ID:
+			require.Zero(t, row.VoteCount)
+		case result.Options[1].ID:
+			require.Equal(t, int64(1), row.VoteCount)
+		}
+	}
+}
+
+func TestCountElectionBallotMeasures(t *testing.T) {
+	result := CreateBallotMeasure(t)
+
+	count, err := testQueries.CountElectionBallotMeasures(context.Background(), result.Measure.ElectionID)
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, count, int64(1))
+
+	// a new election has no measures
+	candidate := CreateEditableCandidate(t)
+	count, err = testQueries.CountElectionBallotMeasures(context.Background(), candidate.ElectionID)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
+
+func CreateBallotMeasure(t *testing.T) CreateBallotMeasureTxResult {
+	store := NewStore(testDB)
+
+	arg := CreateBallotMeasureTxParams{
+		CreateBallotMeasureParams: CreateBallotMeasureParams{
+			Question:            util.RandomString(20),
+			ThresholdPercentage: 66,
+			QuorumPercentage:    25,
+		},
+		Options: []string{"Yes", "No"},
+	}
+
+	result, err := store.CreateBallotMeasureTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Question, result.Measure.Question)
+	require.Equal(t, arg.ThresholdPercentage, result.Measure.ThresholdPercentage)
+	require.Equal(t, arg.QuorumPercentage, result.Measure.QuorumPercentage)
+	require.Len(t, result.Options, len(arg.Options))
+
+	for i, option := range result.Options {
+		require.Equal(t, result.Measure.ID, option.MeasureID)
+		require.Equal(t, arg.Options[i], option.Label)
+		require.Equal(t, int32(i+1), option.Position)
+	}
+	return result
+}
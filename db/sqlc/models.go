@@ -3,21 +3,102 @@
 package db
 
 import (
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+type BallotMeasure struct {
+	ID                  int64     `json:"id"`
+	Question            string    `json:"question"`
+	ThresholdPercentage int32     `json:"threshold_percentage"`
+	QuorumPercentage    int32     `json:"quorum_percentage"`
+	CreateAt            time.Time `json:"create_at"`
+	ElectionID          int64     `json:"election_id"`
+}
+
+type BallotMeasureOption struct {
+	ID        int64     `json:"id"`
+	MeasureID int64     `json:"measure_id"`
+	Label     string    `json:"label"`
+	Position  int32     `json:"position"`
+	CreateAt  time.Time `json:"create_at"`
+}
+
 type Candidate struct {
+	ID                 int64           `json:"id"`
+	Name               string          `json:"name"`
+	Dob                string          `json:"dob"`
+	BioLink            string          `json:"bio_link"`
+	ImageUrl           string          `json:"image_url"`
+	Policy             string          `json:"policy"`
+	VoteCount          int32           `json:"vote_count"`
+	Percentage         int32           `json:"percentage"`
+	CreateAt           time.Time       `json:"create_at"`
+	DistrictID         sql.NullInt64   `json:"district_id"`
+	WeightedVoteCount  int64           `json:"weighted_vote_count"`
+	WeightedPercentage int32           `json:"weighted_percentage"`
+	ElectionID         int64           `json:"election_id"`
+	PolicyItems        json.RawMessage `json:"policy_items"`
+	Links              json.RawMessage `json:"links"`
+	Version            int32           `json:"version"`
+	EditedBy           sql.NullString  `json:"edited_by"`
+	PartyID            sql.NullInt64   `json:"party_id"`
+	WithdrawnAt        sql.NullTime    `json:"withdrawn_at"`
+	WithdrawnReason    string          `json:"withdrawn_reason"`
+	WithdrawnBy        sql.NullString  `json:"withdrawn_by"`
+	ContestID          sql.NullInt64   `json:"contest_id"`
+}
+
+type CandidateRevision struct {
+	ID          int64           `json:"id"`
+	CandidateID int64           `json:"candidate_id"`
+	Version     int32           `json:"version"`
+	Profile     json.RawMessage `json:"profile"`
+	EditedBy    sql.NullString  `json:"edited_by"`
+	CreateAt    time.Time       `json:"create_at"`
+}
+
+type Contest struct {
 	ID         int64     `json:"id"`
+	ElectionID int64     `json:"election_id"`
 	Name       string    `json:"name"`
-	Dob        string    `json:"dob"`
-	BioLink    string    `json:"bio_link"`
-	ImageUrl   string    `json:"image_url"`
-	Policy     string    `json:"policy"`
-	VoteCount  int32     `json:"vote_count"`
-	Percentage int32     `json:"percentage"`
 	CreateAt   time.Time `json:"create_at"`
 }
 
+type Delegation struct {
+	ID                int64          `json:"id"`
+	GrantorNationalID string         `json:"grantor_national_id"`
+	ProxyNationalID   string         `json:"proxy_national_id"`
+	Status            string         `json:"status"`
+	ReviewedBy        sql.NullString `json:"reviewed_by"`
+	ReviewedAt        sql.NullTime   `json:"reviewed_at"`
+	CreateAt          time.Time      `json:"create_at"`
+	ElectionID        int64          `json:"election_id"`
+}
+
+type District struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	CreateAt time.Time `json:"create_at"`
+}
+
+type Election struct {
+	ID                 int64         `json:"id"`
+	Name               string        `json:"name"`
+	WinnerRule         string        `json:"winner_rule"`
+	QuorumPercentage   int32         `json:"quorum_percentage"`
+	TieBreak           string        `json:"tie_break"`
+	TieBreakSeed       string        `json:"tie_break_seed"`
+	CreateAt           time.Time     `json:"create_at"`
+	Closed             bool          `json:"closed"`
+	RunoffOfElectionID sql.NullInt64 `json:"runoff_of_election_id"`
+	BallotType         string        `json:"ballot_type"`
+	Seats              int32         `json:"seats"`
+	SeatMethod         string        `json:"seat_method"`
+	VotingStartedAt    sql.NullTime  `json:"voting_started_at"`
+}
+
 type ElectionProperty struct {
 	ID       int64     `json:"id"`
 	Name     string    `json:"name"`
@@ -25,20 +106,142 @@ type ElectionProperty struct {
 	CreateAt time.Time `json:"create_at"`
 }
 
+type ElectionVoter struct {
+	ElectionID int64        `json:"election_id"`
+	NationalID string       `json:"national_id"`
+	VoteWeight int64        `json:"vote_weight"`
+	CreateAt   time.Time    `json:"create_at"`
+	VotedAt    sql.NullTime `json:"voted_at"`
+}
+
+type EmailVerification struct {
+	ID         int64        `json:"id"`
+	NationalID string       `json:"national_id"`
+	Email      string       `json:"email"`
+	TokenHash  string       `json:"token_hash"`
+	ExpiredAt  time.Time    `json:"expired_at"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreateAt   time.Time    `json:"create_at"`
+}
+
+type MeasureVote struct {
+	ID               int64          `json:"id"`
+	VoteNationalID   string         `json:"vote_national_id"`
+	MeasureID        int64          `json:"measure_id"`
+	OptionID         int64          `json:"option_id"`
+	CastByNationalID sql.NullString `json:"cast_by_national_id"`
+	Weight           int64          `json:"weight"`
+	SupersededAt     sql.NullTime   `json:"superseded_at"`
+	CreateAt         time.Time      `json:"create_at"`
+}
+
+type OidcLogin struct {
+	ID           int64        `json:"id"`
+	StateHash    string       `json:"state_hash"`
+	Nonce        string       `json:"nonce"`
+	CodeVerifier string       `json:"code_verifier"`
+	ExpiredAt    time.Time    `json:"expired_at"`
+	UsedAt       sql.NullTime `json:"used_at"`
+	CreateAt     time.Time    `json:"create_at"`
+}
+
+type Party struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	LogoUrl  string    `json:"logo_url"`
+	Color    string    `json:"color"`
+	CreateAt time.Time `json:"create_at"`
+}
+
+type PartyVote struct {
+	ID               int64          `json:"id"`
+	VoteNationalID   string         `json:"vote_national_id"`
+	ElectionID       int64          `json:"election_id"`
+	PartyID          int64          `json:"party_id"`
+	CastByNationalID sql.NullString `json:"cast_by_national_id"`
+	Weight           int64          `json:"weight"`
+	SupersededAt     sql.NullTime   `json:"superseded_at"`
+	CreateAt         time.Time      `json:"create_at"`
+}
+
+type PasswordReset struct {
+	ID         int64        `json:"id"`
+	NationalID string       `json:"national_id"`
+	TokenHash  string       `json:"token_hash"`
+	ExpiredAt  time.Time    `json:"expired_at"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreateAt   time.Time    `json:"create_at"`
+}
+
+type RecoveryCode struct {
+	ID         int64        `json:"id"`
+	NationalID string       `json:"national_id"`
+	CodeHash   string       `json:"code_hash"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreateAt   time.Time    `json:"create_at"`
+}
+
+type TwoFactor struct {
+	NationalID   string       `json:"national_id"`
+	Secret       string       `json:"secret"`
+	LastUsedStep int64        `json:"last_used_step"`
+	EnabledAt    sql.NullTime `json:"enabled_at"`
+	CreateAt     time.Time    `json:"create_at"`
+}
+
+type TwoFactorChallenge struct {
+	ID         int64        `json:"id"`
+	NationalID string       `json:"national_id"`
+	TokenHash  string       `json:"token_hash"`
+	Attempts   int32        `json:"attempts"`
+	ExpiredAt  time.Time    `json:"expired_at"`
+	UsedAt     sql.NullTime `json:"used_at"`
+	CreateAt   time.Time    `json:"create_at"`
+}
+
 type User struct {
-	NationalID        string    `json:"national_id"`
-	HashedPassword    string    `json:"hashed_password"`
-	FullName          string    `json:"full_name"`
-	Email             string    `json:"email"`
-	Permission        []string  `json:"permission"`
-	HasVoted          bool      `json:"has_voted"`
-	PasswordChangedAt time.Time `json:"password_changed_at"`
-	CreateAt          time.Time `json:"create_at"`
+	NationalID        string        `json:"national_id"`
+	HashedPassword    string        `json:"hashed_password"`
+	FullName          string        `json:"full_name"`
+	Email             string        `json:"email"`
+	Permission        []string      `json:"permission"`
+	PasswordChangedAt time.Time     `json:"password_changed_at"`
+	CreateAt          time.Time     `json:"create_at"`
+	DistrictID        sql.NullInt64 `json:"district_id"`
+	VerifiedAt        sql.NullTime  `json:"verified_at"`
+	DisabledAt        sql.NullTime  `json:"disabled_at"`
+}
+
+type UserAudit struct {
+	ID               int64          `json:"id"`
+	ActorNationalID  string         `json:"actor_national_id"`
+	TargetNationalID string         `json:"target_national_id"`
+	Action           string         `json:"action"`
+	Permission       sql.NullString `json:"permission"`
+	Reason           string         `json:"reason"`
+	CreateAt         time.Time      `json:"create_at"`
+	Role             sql.NullString `json:"role"`
+	ElectionID       sql.NullInt64  `json:"election_id"`
+}
+
+type UserRole struct {
+	ID         int64         `json:"id"`
+	NationalID string        `json:"national_id"`
+	Role       string        `json:"role"`
+	ElectionID sql.NullInt64 `json:"election_id"`
+	GrantedBy  string        `json:"granted_by"`
+	CreateAt   time.Time     `json:"create_at"`
 }
 
 type Vote struct {
-	ID             int64     `json:"id"`
-	VoteNationalID string    `json:"vote_national_id"`
-	CandidateID    int64     `json:"candidate_id"`
-	CreateAt       time.Time `json:"create_at"`
+	ID               int64          `json:"id"`
+	VoteNationalID   string         `json:"vote_national_id"`
+	CandidateID      int64          `json:"candidate_id"`
+	CreateAt         time.Time      `json:"create_at"`
+	ReceiptHash      string         `json:"receipt_hash"`
+	SupersededAt     sql.NullTime   `json:"superseded_at"`
+	Weight           int64          `json:"weight"`
+	CastByNationalID sql.NullString `json:"cast_by_national_id"`
+	ElectionID       int64          `json:"election_id"`
+	ContestID        sql.NullInt64  `json:"contest_id"`
 }
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseOIDCLogin(t *testing.T) {
+	login := CreateOIDCLogin(t, time.Minute)
+
+	used, err := testQueries.UseOIDCLogin(context.Background(), login.StateHash)
+	require.NoError(t, err)
+	require.Equal(t, login.ID, used.ID)
+	require.True(t, used.UsedAt.Valid)
+
+	_, err = testQueries.UseOIDCLogin(context.Background(), login.StateHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	expired := CreateOIDCLogin(t, -time.Minute)
+	_, err = testQueries.UseOIDCLogin(context.Background(), expired.StateHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestProvisionUserTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(32))
+	require.NoError(t, err)
+
+	arg := ProvisionUserTxParams{
+		CreateUserParams: CreateUserParams{
+			NationalID:     util.RandomString(13),
+			HashedPassword: hashedPassword,
+			FullName:       util.RandomName(),
+			Email:          util.RandomEmail(),
+			Permission:     []string{util.Vote},
+		},
+		EmailVerified: true,
+	}
+	result, err := store.ProvisionUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.NationalID, result.User.NationalID)
+	require.True(t, result.User.VerifiedAt.Valid)
+
+	arg.NationalID = util.RandomString(13)
+	arg.Email = util.RandomEmail()
+	arg.EmailVerified = false
+	result, err = store.ProvisionUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, result.User.VerifiedAt.Valid)
+}
+
+func CreateOIDCLogin(t *testing.T, duration time.Duration) OidcLogin {
+	arg := CreateOIDCLoginParams{
+		StateHash:    util.HashSecretToken(util.RandomString(32)),
+		Nonce:        util.RandomString(32),
+		CodeVerifier: util.RandomString(43),
+		ExpiredAt:    time.Now().Add(duration),
+	}
+
+	login, err := testQueries.CreateOIDCLogin(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.StateHash, login.StateHash)
+	require.Equal(t, arg.Nonce, login.Nonce)
+	require.Equal(t, arg.CodeVerifier, login.CodeVerifier)
+	require.WithinDuration(t, arg.ExpiredAt, login.ExpiredAt, time.Second)
+	require.False(t, login.UsedAt.Valid)
+	return login
+}
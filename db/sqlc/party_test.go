@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateParty(t *testing.T) {
+	party1 := CreateParty(t)
+
+	party2, err := testQueries.GetParty(context.Background(), party1.ID)
+	require.NoError(t, err)
+	require.Equal(t, party1, party2)
+
+	_, err = testQueries.CreateParty(context.Background(), CreatePartyParams{Name: party1.Name})
+	require.Error(t, err)
+}
+
+func TestUpdateParty(t *testing.T) {
+	party1 := CreateParty(t)
+
+	arg := UpdatePartyParams{
+		ID:      party1.ID,
+		Name:    util.RandomName(),
+		LogoUrl: util.RandomImageLink(),
+		Color:   "#00ff00",
+	}
+
+	party2, err := testQueries.UpdateParty(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, party2.Name)
+	require.Equal(t, arg.LogoUrl, party2.LogoUrl)
+	require.Equal(t, arg.Color, party2.Color)
+}
+
+func TestPartyVote(t *testing.T) {
+	election := CreatePartyListElection(t)
+	party1 := CreateParty(t)
+	party2 := CreateParty(t)
+	user := CreateUser(t)
+
+	arg := CreatePartyVoteParams{
+		VoteNationalID: user.NationalID,
+		ElectionID:     election.ID,
+		PartyID:        party1.ID,
+	}
+
+	vote, err := testQueries.CreatePartyVote(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.PartyID, vote.PartyID)
+	require.Equal(t, int64(1), vote.Weight)
+
+	arg.PartyID = party2.ID
+	_, err = testQueries.CreatePartyVote(context.Background(), arg)
+	require.Error(t, err)
+
+	voted, err := testQueries.HasElectionVoterVoted(context.Background(), HasElectionVoterVotedParams{
+		ElectionID: election.ID,
+		NationalID: user.NationalID,
+	})
+	require.NoError(t, err)
+	require.True(t, voted)
+
+	turnout, err := testQueries.CountActiveVotes(context.Background(), election.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), turnout)
+
+	rows, err := testQueries.ListElectionPartiesResult(context.Background(), election.ID)
+	require.NoError(t, err)
+	for _, row := range rows {
+		switch row.ID {
+		case party1.ID:
+			require.Equal(t, int64(1), row.ListVoteCount)
+			require.Equal(t, int64(1), row.WeightedListVoteCount)
+		case party2.ID:
+			require.Zero(t, row.ListVoteCount)
+		}
+	}
+}
+
+func TestListElectionPartiesResultCandidates(t *testing.T) {
+	party := CreateParty(t)
+
+	candidate := CreateEditableCandidate(t)
+	_, err := testQueries.UpdateCandidate(context.Background(), UpdateCandidateParams{
+		ID:          candidate.ID,
+		Name:        candidate.Name,
+		Dob:         candidate.Dob,
+		BioLink:     candidate.BioLink,
+		ImageUrl:    candidate.ImageUrl,
+		Policy:      candidate.Policy,
+		PartyID:     sql.NullInt64{Int64: party.ID, Valid: true},
+		PolicyItems: candidate.PolicyItems,
+		Links:       candidate.Links,
+	})
+	require.NoError(t, err)
+
+	rows, err := testQueries.ListElectionPartiesResult(context.Background(), candidate.ElectionID)
+	require.NoError(t, err)
+	for _, row := range rows {
+		if row.ID == party.ID {
+			require.Equal(t, int64(1), row.CandidateCount)
+			require.Equal(t, int64(candidate.VoteCount), row.CandidateVoteCount)
+		}
+	}
+}
+
+func CreateParty(t *testing.T) Party {
+	arg := CreatePartyParams{
+		Name:    util.RandomName(),
+		LogoUrl: util.RandomImageLink(),
+		Color:   "#ff0000",
+	}
+
+	party, err := testQueries.CreateParty(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, party.ID)
+	require.Equal(t, arg.Name, party.Name)
+	require.Equal(t, arg.LogoUrl, party.LogoUrl)
+	require.Equal(t, arg.Color, party.Color)
+	require.NotZero(t, party.CreateAt)
+	return party
+}
+
+func CreatePartyListElection(t *testing.T) Election {
+	election, err := testQueries.CreateElection(context.Background(), CreateElectionParams{
+		Name:         util.RandomName(),
+		WinnerRule:   util.WinnerRulePlurality,
+		TieBreak:     util.TieBreakLottery,
+		TieBreakSeed: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	election, err = testQueries.UpdateElectionRules(context.Background(), UpdateElectionRulesParams{
+		ID:           election.ID,
+		WinnerRule:   election.WinnerRule,
+		TieBreak:     election.TieBreak,
+		TieBreakSeed: election.TieBreakSeed,
+		BallotType:   util.BallotTypePartyList,
+		Seats:        10,
+		SeatMethod:   util.SeatMethodDHondt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, util.BallotTypePartyList, election.BallotType)
+	return election
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreatePasswordReset(t *testing.T) {
+	CreatePasswordReset(t, time.Hour)
+}
+
+func TestResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	reset1 := CreatePasswordReset(t, time.Hour)
+
+	// a second token of the same user is revoked by the reset
+	arg := CreatePasswordResetParams{
+		NationalID: reset1.NationalID,
+		TokenHash:  util.HashSecretToken(util.RandomString(32)),
+		ExpiredAt:  time.Now().Add(time.Hour),
+	}
+	reset2, err := testQueries.CreatePasswordReset(context.Background(), arg)
+	require.NoError(t, err)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	txArg := ResetPasswordTxParams{
+		TokenHash:         reset1.TokenHash,
+		HashedPassword:    hashedPassword,
+		PasswordChangedAt: time.Now(),
+	}
+
+	result, err := store.ResetPasswordTx(context.Background(), txArg)
+	require.NoError(t, err)
+	require.Equal(t, reset1.NationalID, result.User.NationalID)
+	require.Equal(t, hashedPassword, result.User.HashedPassword)
+	require.WithinDuration(t, txArg.PasswordChangedAt, result.User.PasswordChangedAt, time.Millisecond)
+
+	_, err = store.ResetPasswordTx(context.Background(), txArg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	txArg.TokenHash = reset2.TokenHash
+	_, err = store.ResetPasswordTx(context.Background(), txArg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseExpiredPasswordReset(t *testing.T) {
+	reset := CreatePasswordReset(t, -time.Minute)
+
+	_, err := testQueries.UsePasswordReset(context.Background(), reset.TokenHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func CreatePasswordReset(t *testing.T, duration time.Duration) PasswordReset {
+	user := CreateUser(t)
+
+	arg := CreatePasswordResetParams{
+		NationalID: user.NationalID,
+		TokenHash:  util.HashSecretToken(util.RandomString(32)),
+		ExpiredAt:  time.Now().Add(duration),
+	}
+
+	reset, err := testQueries.CreatePasswordReset(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, reset)
+
+	require.Equal(t, arg.NationalID, reset.NationalID)
+	require.Equal(t, arg.TokenHash, reset.TokenHash)
+	require.WithinDuration(t, arg.ExpiredAt, reset.ExpiredAt, time.Millisecond)
+	require.False(t, reset.UsedAt.Valid)
+	require.NotZero(t, reset.CreateAt)
+	return reset
+}
@@ -4,21 +4,121 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	AddUserPermission(ctx context.Context, arg AddUserPermissionParams) (User, error)
+	ApproveDelegation(ctx context.Context, arg ApproveDelegationParams) (Delegation, error)
+	AttemptTwoFactorChallenge(ctx context.Context, tokenHash string) (TwoFactorChallenge, error)
+	CloseElection(ctx context.Context, id int64) (Election, error)
+	CopyElectionVoters(ctx context.Context, arg CopyElectionVotersParams) error
+	CountActiveVotes(ctx context.Context, electionID int64) (int64, error)
+	CountCandidates(ctx context.Context, arg CountCandidatesParams) (int64, error)
+	CountElectionBallotMeasures(ctx context.Context, electionID int64) (int64, error)
+	CountElectionContests(ctx context.Context, electionID int64) (int64, error)
+	CountEligibleVoters(ctx context.Context) (int64, error)
+	CountProxyDelegations(ctx context.Context, arg CountProxyDelegationsParams) (int64, error)
+	CreateBallotMeasure(ctx context.Context, arg CreateBallotMeasureParams) (BallotMeasure, error)
+	CreateBallotMeasureOption(ctx context.Context, arg CreateBallotMeasureOptionParams) (BallotMeasureOption, error)
 	CreateCandidate(ctx context.Context, arg CreateCandidateParams) (Candidate, error)
+	CreateContest(ctx context.Context, arg CreateContestParams) (Contest, error)
+	CreateDelegation(ctx context.Context, arg CreateDelegationParams) (Delegation, error)
+	CreateDistrict(ctx context.Context, name string) (District, error)
+	CreateElection(ctx context.Context, arg CreateElectionParams) (Election, error)
+	CreateEmailVerification(ctx context.Context, arg CreateEmailVerificationParams) (EmailVerification, error)
+	CreateMeasureVote(ctx context.Context, arg CreateMeasureVoteParams) (MeasureVote, error)
+	CreateOIDCLogin(ctx context.Context, arg CreateOIDCLoginParams) (OidcLogin, error)
+	CreateParty(ctx context.Context, arg CreatePartyParams) (Party, error)
+	CreatePartyVote(ctx context.Context, arg CreatePartyVoteParams) (PartyVote, error)
+	CreatePasswordReset(ctx context.Context, arg CreatePasswordResetParams) (PasswordReset, error)
+	CreateRecoveryCode(ctx context.Context, arg CreateRecoveryCodeParams) (RecoveryCode, error)
+	CreateRunoffCandidate(ctx context.Context, arg CreateRunoffCandidateParams) (Candidate, error)
+	CreateTwoFactorChallenge(ctx context.Context, arg CreateTwoFactorChallengeParams) (TwoFactorChallenge, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserAudit(ctx context.Context, arg CreateUserAuditParams) (UserAudit, error)
+	CreateUserRole(ctx context.Context, arg CreateUserRoleParams) (UserRole, error)
 	CreateVote(ctx context.Context, arg CreateVoteParams) (Vote, error)
-	DeleteCandidate(ctx context.Context, id int64) error
+	DeleteRecoveryCodes(ctx context.Context, nationalID string) error
+	DeleteTwoFactor(ctx context.Context, nationalID string) error
+	DeleteUser(ctx context.Context, nationalID string) (int64, error)
+	DeleteUserDelegations(ctx context.Context, grantorNationalID string) error
+	DeleteUserRole(ctx context.Context, arg DeleteUserRoleParams) (UserRole, error)
+	DisableUser(ctx context.Context, nationalID string) (User, error)
+	EnableTwoFactor(ctx context.Context, nationalID string) (TwoFactor, error)
+	EnableUser(ctx context.Context, nationalID string) (User, error)
+	GetApprovedDelegation(ctx context.Context, arg GetApprovedDelegationParams) (Delegation, error)
+	GetBallotMeasure(ctx context.Context, id int64) (BallotMeasure, error)
+	GetBallotMeasureOption(ctx context.Context, id int64) (GetBallotMeasureOptionRow, error)
 	GetCandidate(ctx context.Context, id int64) (GetCandidateRow, error)
+	GetCandidateRevision(ctx context.Context, arg GetCandidateRevisionParams) (CandidateRevision, error)
+	GetContest(ctx context.Context, id int64) (Contest, error)
+	GetDelegation(ctx context.Context, id int64) (Delegation, error)
+	GetDistrict(ctx context.Context, id int64) (District, error)
+	GetElection(ctx context.Context, id int64) (Election, error)
 	GetElectionProperty(ctx context.Context, name string) (ElectionProperty, error)
+	GetGrantorApprovedDelegation(ctx context.Context, arg GetGrantorApprovedDelegationParams) (Delegation, error)
+	GetLatestEmailVerification(ctx context.Context, nationalID string) (EmailVerification, error)
+	GetParty(ctx context.Context, id int64) (Party, error)
+	GetRunoffElection(ctx context.Context, runoffOfElectionID sql.NullInt64) (Election, error)
+	GetTwoFactor(ctx context.Context, nationalID string) (TwoFactor, error)
 	GetUser(ctx context.Context, nationalID string) (User, error)
+	GetUserByEmail(ctx context.Context, email string) (User, error)
+	GetUserPasswordChangedAt(ctx context.Context, nationalID string) (time.Time, error)
+	GetUserPermissions(ctx context.Context, nationalID string) ([]string, error)
+	GetVoteByReceipt(ctx context.Context, receiptHash string) (Vote, error)
+	HasElectionVoterVoted(ctx context.Context, arg HasElectionVoterVotedParams) (bool, error)
+	ListBallotCandidates(ctx context.Context, arg ListBallotCandidatesParams) ([]ListBallotCandidatesRow, error)
+	ListBallotMeasureOptions(ctx context.Context) ([]BallotMeasureOption, error)
+	ListBallotMeasures(ctx context.Context) ([]BallotMeasure, error)
+	ListCandidateRevisions(ctx context.Context, candidateID int64) ([]CandidateRevision, error)
 	ListCandidates(ctx context.Context, arg ListCandidatesParams) ([]ListCandidatesRow, error)
 	ListCandidatesResult(ctx context.Context) ([]ListCandidatesResultRow, error)
+	ListDelegations(ctx context.Context, arg ListDelegationsParams) ([]Delegation, error)
+	ListDistrictCandidatesResult(ctx context.Context, districtID int64) ([]ListDistrictCandidatesResultRow, error)
+	ListDistricts(ctx context.Context) ([]District, error)
+	ListDistrictsResult(ctx context.Context) ([]ListDistrictsResultRow, error)
+	ListElectionCandidatesResult(ctx context.Context, arg ListElectionCandidatesResultParams) ([]ListElectionCandidatesResultRow, error)
+	ListElectionContests(ctx context.Context, electionID int64) ([]Contest, error)
+	ListElectionPartiesResult(ctx context.Context, electionID int64) ([]ListElectionPartiesResultRow, error)
+	ListElectionProperties(ctx context.Context) ([]ElectionProperty, error)
+	ListElectionStates(ctx context.Context) ([]ListElectionStatesRow, error)
+	ListElectionVotes(ctx context.Context, arg ListElectionVotesParams) ([]string, error)
+	ListMeasureOptionsResult(ctx context.Context) ([]ListMeasureOptionsResultRow, error)
+	ListParties(ctx context.Context) ([]Party, error)
+	ListUserAudits(ctx context.Context, arg ListUserAuditsParams) ([]UserAudit, error)
+	ListUserRoles(ctx context.Context, nationalID string) ([]UserRole, error)
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
 	ListVoteOrderByCandidate(ctx context.Context) ([]ListVoteOrderByCandidateRow, error)
+	LockDelegationProxy(ctx context.Context, nationalID string) (string, error)
+	RecountUserCandidates(ctx context.Context, arg RecountUserCandidatesParams) error
+	RemoveUserPermission(ctx context.Context, arg RemoveUserPermissionParams) (User, error)
+	ResetUserVoted(ctx context.Context, arg ResetUserVotedParams) (int64, error)
+	RestoreCandidate(ctx context.Context, id int64) (Candidate, error)
+	RevokePasswordResets(ctx context.Context, nationalID string) error
+	SetElectionVoterWeight(ctx context.Context, arg SetElectionVoterWeightParams) (ElectionVoter, error)
+	SetupTwoFactor(ctx context.Context, arg SetupTwoFactorParams) (TwoFactor, error)
+	SupersedeUserMeasureVotes(ctx context.Context, arg SupersedeUserMeasureVotesParams) error
+	SupersedeUserPartyVotes(ctx context.Context, arg SupersedeUserPartyVotesParams) error
+	SupersedeUserVotes(ctx context.Context, arg SupersedeUserVotesParams) error
 	UpdateCandidate(ctx context.Context, arg UpdateCandidateParams) (UpdateCandidateRow, error)
+	UpdateCandidateImage(ctx context.Context, arg UpdateCandidateImageParams) (Candidate, error)
+	UpdateDelegationStatus(ctx context.Context, arg UpdateDelegationStatusParams) (Delegation, error)
 	UpdateElectionProperty(ctx context.Context, arg UpdateElectionPropertyParams) (ElectionProperty, error)
+	UpdateElectionRules(ctx context.Context, arg UpdateElectionRulesParams) (Election, error)
+	UpdateParty(ctx context.Context, arg UpdatePartyParams) (Party, error)
+	UpdateUserDistrict(ctx context.Context, arg UpdateUserDistrictParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UseEmailVerification(ctx context.Context, tokenHash string) (EmailVerification, error)
+	UseOIDCLogin(ctx context.Context, stateHash string) (OidcLogin, error)
+	UsePasswordReset(ctx context.Context, tokenHash string) (PasswordReset, error)
+	UseRecoveryCode(ctx context.Context, arg UseRecoveryCodeParams) (RecoveryCode, error)
+	UseTwoFactorChallenge(ctx context.Context, id int64) error
+	UseTwoFactorStep(ctx context.Context, arg UseTwoFactorStepParams) (int64, error)
+	VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error)
+	WithdrawCandidate(ctx context.Context, arg WithdrawCandidateParams) (Candidate, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"election/logging"
+)
+
+// requestIDDB prefixes every statement with a comment holding the request ID of its context,
+// so a statement in the database logs and pg_stat_activity can be traced to the request
+type requestIDDB struct {
+	db DBTX
+}
+
+func withRequestID(db DBTX) DBTX {
+	return requestIDDB{db: db}
+}
+
+func (r requestIDDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return r.db.ExecContext(ctx, tagQuery(ctx, query), args...)
+}
+
+func (r requestIDDB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return r.db.PrepareContext(ctx, tagQuery(ctx, query))
+}
+
+func (r requestIDDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return r.db.QueryContext(ctx, tagQuery(ctx, query), args...)
+}
+
+func (r requestIDDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return r.db.QueryRowContext(ctx, tagQuery(ctx, query), args...)
+}
+
+func tagQuery(ctx context.Context, query string) string {
+	requestID := logging.RequestID(ctx)
+	// a valid request ID cannot close the comment it is written in
+	if !logging.IsValidRequestID(requestID) {
+		return query
+	}
+	return "/* request_id=" + requestID + " */ " + query
+}
@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"election/logging"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTagQuery(t *testing.T) {
+	query := "-- name: GetUser :one\nSELECT national_id FROM users WHERE national_id = $1"
+
+	require.Equal(t, query, tagQuery(context.Background(), query))
+
+	ctx := logging.WithRequestID(context.Background(), "4f9c2d1e-7b1a-4c55-9d3e-2a6f8e0b1c7d")
+	require.Equal(t, "/* request_id=4f9c2d1e-7b1a-4c55-9d3e-2a6f8e0b1c7d */ "+query, tagQuery(ctx, query))
+
+	ctx = logging.WithRequestID(context.Background(), "*/ DROP TABLE users; /*")
+	require.Equal(t, query, tagQuery(ctx, query))
+}
+
+func TestRequestIDQuery(t *testing.T) {
+	ctx := logging.WithRequestID(context.Background(), "test-request")
+	store := NewStore(testDB).(*SQLStore)
+
+	user := CreateUser(t)
+	got, err := store.GetUser(ctx, user.NationalID)
+	require.NoError(t, err)
+	require.Equal(t, user.NationalID, got.NationalID)
+}
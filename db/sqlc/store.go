@@ -1,11 +1,40 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"election/util"
 )
 
+// ErrUserHasVoted is returned when deleting a user whose ballot is part of an election record
+var ErrUserHasVoted = errors.New("user has voted")
+
+// ErrVotingStarted is returned when changing voter weights of an election that has ballots
+var ErrVotingStarted = errors.New("voting has started")
+
+// ErrProxyLimitReached is returned when approving a delegation to a proxy that holds the maximum number of ballots
+var ErrProxyLimitReached = errors.New("proxy limit reached")
+
 type Store interface {
 	Querier
+	ImportVoterRollTx(ctx context.Context, arg ImportVoterRollTxParams) (ImportVoterRollTxResult, error)
+	ImportVoterWeightsTx(ctx context.Context, arg ImportVoterWeightsTxParams) (ImportVoterWeightsTxResult, error)
+	CreateBallotMeasureTx(ctx context.Context, arg CreateBallotMeasureTxParams) (CreateBallotMeasureTxResult, error)
+	CastBallotTx(ctx context.Context, arg CastBallotTxParams) (CastBallotTxResult, error)
+	CreateRunoffTx(ctx context.Context, arg CreateRunoffTxParams) (CreateRunoffTxResult, error)
+	ApproveDelegationTx(ctx context.Context, arg ApproveDelegationTxParams) (ApproveDelegationTxResult, error)
+	DeleteUserTx(ctx context.Context, nationalID string) error
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error)
+	VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error)
+	EnableTwoFactorTx(ctx context.Context, arg EnableTwoFactorTxParams) (EnableTwoFactorTxResult, error)
+	DisableTwoFactorTx(ctx context.Context, nationalID string) error
+	AdminUpdateUserTx(ctx context.Context, arg AdminUpdateUserTxParams) (AdminUpdateUserTxResult, error)
+	ProvisionUserTx(ctx context.Context, arg ProvisionUserTxParams) (ProvisionUserTxResult, error)
+	DBStats() sql.DBStats
 }
 
 //Store provides all functions to execute db queries
@@ -18,6 +47,650 @@ type SQLStore struct {
 func NewStore(db *sql.DB) Store {
 	return &SQLStore{
 		db:      db,
-		Queries: New(db),
+		Queries: New(withRequestID(db)),
+	}
+}
+
+// DBStats returns the connection pool statistics of the database
+func (store *SQLStore) DBStats() sql.DBStats {
+	return store.db.Stats()
+}
+
+// execTx executes a function within a database transaction
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	q := New(withRequestID(tx))
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("tx err: %v, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// VoterRollEntry assigns a registered voter to a district
+type VoterRollEntry struct {
+	NationalID string `json:"national_id"`
+	DistrictID int64  `json:"district_id"`
+}
+
+// ImportVoterRollTxParams contains the input parameters of the voter roll import
+type ImportVoterRollTxParams struct {
+	Entries []VoterRollEntry `json:"entries"`
+}
+
+// ImportVoterRollTxResult is the result of the voter roll import
+type ImportVoterRollTxResult struct {
+	Users []User `json:"users"`
+}
+
+// ImportVoterRollTx assigns every voter of the roll to its district in a single transaction,
+// so a roll with an unknown voter or district is not partially applied
+func (store *SQLStore) ImportVoterRollTx(ctx context.Context, arg ImportVoterRollTxParams) (ImportVoterRollTxResult, error) {
+	var result ImportVoterRollTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result.Users = make([]User, 0, len(arg.Entries))
+
+		for i, entry := range arg.Entries {
+			user, err := q.UpdateUserDistrict(ctx, UpdateUserDistrictParams{
+				NationalID: entry.NationalID,
+				DistrictID: sql.NullInt64{Int64: entry.DistrictID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("roll entry %d: %w", i+1, err)
+			}
+			result.Users = append(result.Users, user)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// VoterWeightEntry sets the vote weight, e.g. share count, of a registered voter
+type VoterWeightEntry struct {
+	NationalID string `json:"national_id"`
+	VoteWeight int64  `json:"vote_weight"`
+}
+
+// ImportVoterWeightsTxParams contains the input parameters of the voter weights import
+type ImportVoterWeightsTxParams struct {
+	ElectionID int64              `json:"election_id"`
+	Entries    []VoterWeightEntry `json:"entries"`
+}
+
+// ImportVoterWeightsTxResult is the result of the voter weights import
+type ImportVoterWeightsTxResult struct {
+	Voters []ElectionVoter `json:"voters"`
+}
+
+// ImportVoterWeightsTx sets the vote weight of every listed voter in the election in a single
+// transaction, it fails with ErrVotingStarted once a ballot was cast in the election
+func (store *SQLStore) ImportVoterWeightsTx(ctx context.Context, arg ImportVoterWeightsTxParams) (ImportVoterWeightsTxResult, error) {
+	var result ImportVoterWeightsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result.Voters = make([]ElectionVoter, 0, len(arg.Entries))
+
+		for i, entry := range arg.Entries {
+			_, err := q.GetUser(ctx, entry.NationalID)
+			if err != nil {
+				return fmt.Errorf("weight entry %d: %w", i+1, err)
+			}
+
+			voter, err := q.SetElectionVoterWeight(ctx, SetElectionVoterWeightParams{
+				ElectionID: arg.ElectionID,
+				NationalID: entry.NationalID,
+				VoteWeight: entry.VoteWeight,
+			})
+			if err != nil {
+				if err == sql.ErrNoRows {
+					return ErrVotingStarted
+				}
+				return fmt.Errorf("weight entry %d: %w", i+1, err)
+			}
+			result.Voters = append(result.Voters, voter)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateBallotMeasureTxParams contains the input parameters of the ballot measure creation
+type CreateBallotMeasureTxParams struct {
+	CreateBallotMeasureParams
+	Options []string `json:"options"`
+}
+
+// CreateBallotMeasureTxResult is the result of the ballot measure creation
+type CreateBallotMeasureTxResult struct {
+	Measure BallotMeasure         `json:"measure"`
+	Options []BallotMeasureOption `json:"options"`
+}
+
+// CreateBallotMeasureTx creates a ballot measure together with its options in a single transaction,
+// options are positioned in the given order starting at 1
+func (store *SQLStore) CreateBallotMeasureTx(ctx context.Context, arg CreateBallotMeasureTxParams) (CreateBallotMeasureTxResult, error) {
+	var result CreateBallotMeasureTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Measure, err = q.CreateBallotMeasure(ctx, arg.CreateBallotMeasureParams)
+		if err != nil {
+			return err
+		}
+
+		result.Options = make([]BallotMeasureOption, 0, len(arg.Options))
+		for i, label := range arg.Options {
+			option, err := q.CreateBallotMeasureOption(ctx, CreateBallotMeasureOptionParams{
+				MeasureID: result.Measure.ID,
+				Label:     label,
+				Position:  int32(i + 1),
+			})
+			if err != nil {
+				return fmt.Errorf("option %d: %w", i+1, err)
+			}
+			result.Options = append(result.Options, option)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CastBallotTxParams contains the selections of every contest on a ballot
+type CastBallotTxParams struct {
+	Votes        []CreateVoteParams        `json:"votes"`
+	MeasureVotes []CreateMeasureVoteParams `json:"measure_votes"`
+}
+
+// CastBallotTxResult is the result of the ballot casting
+type CastBallotTxResult struct {
+	Votes        []Vote        `json:"votes"`
+	MeasureVotes []MeasureVote `json:"measure_votes"`
+}
+
+// CastBallotTx records the candidate vote of every contest and all measure votes of a ballot in a
+// single transaction, so a ballot is never partially recorded
+func (store *SQLStore) CastBallotTx(ctx context.Context, arg CastBallotTxParams) (CastBallotTxResult, error) {
+	var result CastBallotTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		result.Votes = make([]Vote, 0, len(arg.Votes))
+		for _, candidateVote := range arg.Votes {
+			vote, err := q.CreateVote(ctx, candidateVote)
+			if err != nil {
+				return fmt.Errorf("candidate %d: %w", candidateVote.CandidateID, err)
+			}
+			result.Votes = append(result.Votes, vote)
+		}
+
+		result.MeasureVotes = make([]MeasureVote, 0, len(arg.MeasureVotes))
+		for _, measureVote := range arg.MeasureVotes {
+			vote, err := q.CreateMeasureVote(ctx, measureVote)
+			if err != nil {
+				return fmt.Errorf("measure %d: %w", measureVote.MeasureID, err)
+			}
+			result.MeasureVotes = append(result.MeasureVotes, vote)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateRunoffTxParams contains the input parameters of the runoff creation
+type CreateRunoffTxParams struct {
+	Election     Election `json:"election"`
+	CandidateIDs []int64  `json:"candidate_ids"`
+	TieBreakSeed string   `json:"tie_break_seed"`
+}
+
+// CreateRunoffTxResult is the result of the runoff creation
+type CreateRunoffTxResult struct {
+	Election   Election    `json:"election"`
+	Runoff     Election    `json:"runoff"`
+	Candidates []Candidate `json:"candidates"`
+}
+
+// CreateRunoffTx closes an election and creates its runoff with the same rules and the given
+// candidates in a single transaction. The voter roll and its weights are kept, every voter may vote
+// in the runoff while the ballots of the election stay as they were. The runoff draws its lottery with
+// its own seed.
+func (store *SQLStore) CreateRunoffTx(ctx context.Context, arg CreateRunoffTxParams) (CreateRunoffTxResult, error) {
+	var result CreateRunoffTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Election, err = q.CloseElection(ctx, arg.Election.ID)
+		if err != nil {
+			return err
+		}
+
+		result.Runoff, err = q.CreateElection(ctx, CreateElectionParams{
+			Name:               arg.Election.Name + " runoff",
+			WinnerRule:         arg.Election.WinnerRule,
+			QuorumPercentage:   arg.Election.QuorumPercentage,
+			TieBreak:           arg.Election.TieBreak,
+			TieBreakSeed:       arg.TieBreakSeed,
+			RunoffOfElectionID: sql.NullInt64{Int64: arg.Election.ID, Valid: true},
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.CopyElectionVoters(ctx, CopyElectionVotersParams{
+			ToElectionID:   result.Runoff.ID,
+			FromElectionID: arg.Election.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.Candidates = make([]Candidate, 0, len(arg.CandidateIDs))
+		for _, id := range arg.CandidateIDs {
+			candidate, err := q.CreateRunoffCandidate(ctx, CreateRunoffCandidateParams{
+				ID:         id,
+				ElectionID: result.Runoff.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("candidate %d: %w", id, err)
+			}
+			result.Candidates = append(result.Candidates, candidate)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// ApproveDelegationTxParams contains the input parameters of the delegation approval
+type ApproveDelegationTxParams struct {
+	ID          int64          `json:"id"`
+	ReviewedBy  sql.NullString `json:"reviewed_by"`
+	MaxApproved int64          `json:"max_approved"`
+}
+
+// ApproveDelegationTxResult is the result of the delegation approval
+type ApproveDelegationTxResult struct {
+	Delegation Delegation `json:"delegation"`
+}
+
+// ApproveDelegationTx approves a pending delegation unless its proxy already holds MaxApproved approved
+// ballots in the election, it fails with ErrProxyLimitReached then. The proxy is locked first so that
+// concurrent approvals for the same proxy are counted one after the other.
+func (store *SQLStore) ApproveDelegationTx(ctx context.Context, arg ApproveDelegationTxParams) (ApproveDelegationTxResult, error) {
+	var result ApproveDelegationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		delegation, err := q.GetDelegation(ctx, arg.ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = q.LockDelegationProxy(ctx, delegation.ProxyNationalID)
+		if err != nil {
+			return err
+		}
+
+		approved, err := q.CountProxyDelegations(ctx, CountProxyDelegationsParams{
+			ProxyNationalID: delegation.ProxyNationalID,
+			ElectionID:      delegation.ElectionID,
+			Statuses:        []string{util.DelegationApproved},
+		})
+		if err != nil {
+			return err
+		}
+
+		if approved >= arg.MaxApproved {
+			return ErrProxyLimitReached
+		}
+
+		result.Delegation, err = q.ApproveDelegation(ctx, ApproveDelegationParams{
+			ID:         delegation.ID,
+			ReviewedBy: arg.ReviewedBy,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteUserTx deletes a user together with the delegations they granted or received in a single
+// transaction, a user who voted or cast a ballot as a proxy is kept so the election record stays intact
+func (store *SQLStore) DeleteUserTx(ctx context.Context, nationalID string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := q.DeleteUserDelegations(ctx, nationalID)
+		if err != nil {
+			return err
+		}
+
+		deleted, err := q.DeleteUser(ctx, nationalID)
+		if err != nil {
+			return err
+		}
+		if deleted == 0 {
+			return ErrUserHasVoted
+		}
+
+		return nil
+	})
+}
+
+// ResetPasswordTxParams contains the input parameters of the password reset
+type ResetPasswordTxParams struct {
+	TokenHash         string    `json:"token_hash"`
+	HashedPassword    string    `json:"hashed_password"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+}
+
+// ResetPasswordTxResult is the result of the password reset
+type ResetPasswordTxResult struct {
+	User User `json:"user"`
+}
+
+// ResetPasswordTx uses a reset token to set a new password in a single transaction, the other unused
+// tokens of the user are revoked, a token which is unknown, used or expired returns sql.ErrNoRows
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (ResetPasswordTxResult, error) {
+	var result ResetPasswordTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		reset, err := q.UsePasswordReset(ctx, arg.TokenHash)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			NationalID:        reset.NationalID,
+			HashedPassword:    arg.HashedPassword,
+			PasswordChangedAt: arg.PasswordChangedAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		return q.RevokePasswordResets(ctx, reset.NationalID)
+	})
+
+	return result, err
+}
+
+// VerifyEmailTxParams contains the input parameters of the email verification
+type VerifyEmailTxParams struct {
+	TokenHash string `json:"token_hash"`
+}
+
+// VerifyEmailTxResult is the result of the email verification
+type VerifyEmailTxResult struct {
+	User User `json:"user"`
+}
+
+// VerifyEmailTx uses a verification token to mark the email of its user as verified in a single transaction,
+// a token which is unknown, used, expired or sent to an email the user no longer has returns sql.ErrNoRows
+func (store *SQLStore) VerifyEmailTx(ctx context.Context, arg VerifyEmailTxParams) (VerifyEmailTxResult, error) {
+	var result VerifyEmailTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		verification, err := q.UseEmailVerification(ctx, arg.TokenHash)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			NationalID: verification.NationalID,
+			Email:      verification.Email,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// EnableTwoFactorTxParams contains the input parameters of the two-factor enrollment
+type EnableTwoFactorTxParams struct {
+	NationalID         string   `json:"national_id"`
+	RecoveryCodeHashes []string `json:"recovery_code_hashes"`
+}
+
+// EnableTwoFactorTxResult is the result of the two-factor enrollment
+type EnableTwoFactorTxResult struct {
+	TwoFactor     TwoFactor      `json:"two_factor"`
+	RecoveryCodes []RecoveryCode `json:"recovery_codes"`
+}
+
+// EnableTwoFactorTx enables the pending authenticator of a user and replaces the recovery codes
+// in a single transaction, an authenticator which is not set up or already enabled returns sql.ErrNoRows
+func (store *SQLStore) EnableTwoFactorTx(ctx context.Context, arg EnableTwoFactorTxParams) (EnableTwoFactorTxResult, error) {
+	var result EnableTwoFactorTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.TwoFactor, err = q.EnableTwoFactor(ctx, arg.NationalID)
+		if err != nil {
+			return err
+		}
+
+		err = q.DeleteRecoveryCodes(ctx, arg.NationalID)
+		if err != nil {
+			return err
+		}
+
+		result.RecoveryCodes = make([]RecoveryCode, 0, len(arg.RecoveryCodeHashes))
+		for i, hash := range arg.RecoveryCodeHashes {
+			code, err := q.CreateRecoveryCode(ctx, CreateRecoveryCodeParams{
+				NationalID: arg.NationalID,
+				CodeHash:   hash,
+			})
+			if err != nil {
+				return fmt.Errorf("recovery code %d: %w", i+1, err)
+			}
+			result.RecoveryCodes = append(result.RecoveryCodes, code)
+		}
+
+		return nil
+	})
+
+	return result, err
+}
+
+// DisableTwoFactorTx removes the authenticator and the recovery codes of a user in a single transaction
+func (store *SQLStore) DisableTwoFactorTx(ctx context.Context, nationalID string) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		err := q.DeleteRecoveryCodes(ctx, nationalID)
+		if err != nil {
+			return err
+		}
+
+		return q.DeleteTwoFactor(ctx, nationalID)
+	})
+}
+
+// AdminUpdateUserTxParams contains the input parameters of an administrative change to a user
+type AdminUpdateUserTxParams struct {
+	ActorNationalID  string        `json:"actor_national_id"`
+	TargetNationalID string        `json:"target_national_id"`
+	Action           string        `json:"action"`
+	Permission       string        `json:"permission"`
+	Role             string        `json:"role"`
+	ElectionID       sql.NullInt64 `json:"election_id"`
+	Reason           string        `json:"reason"`
+}
+
+// AdminUpdateUserTxResult is the result of an administrative change to a user
+type AdminUpdateUserTxResult struct {
+	User  User      `json:"user"`
+	Audit UserAudit `json:"audit"`
+}
+
+// AdminUpdateUserTx applies an administrative change to a user and records it for audit
+// in a single transaction, a change which does not apply to the user returns sql.ErrNoRows
+func (store *SQLStore) AdminUpdateUserTx(ctx context.Context, arg AdminUpdateUserTxParams) (AdminUpdateUserTxResult, error) {
+	var result AdminUpdateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		switch arg.Action {
+		case util.GrantPermission:
+			result.User, err = q.AddUserPermission(ctx, AddUserPermissionParams{
+				Permission: arg.Permission,
+				NationalID: arg.TargetNationalID,
+			})
+		case util.RevokePermission:
+			result.User, err = q.RemoveUserPermission(ctx, RemoveUserPermissionParams{
+				Permission: arg.Permission,
+				NationalID: arg.TargetNationalID,
+			})
+		case util.DisableAccount:
+			result.User, err = q.DisableUser(ctx, arg.TargetNationalID)
+		case util.EnableAccount:
+			result.User, err = q.EnableUser(ctx, arg.TargetNationalID)
+		case util.ResetVoterStatus:
+			result.User, err = q.resetVoterStatus(ctx, arg.TargetNationalID, arg.ElectionID.Int64)
+		case util.AssignRole:
+			_, err = q.CreateUserRole(ctx, CreateUserRoleParams{
+				NationalID: arg.TargetNationalID,
+				Role:       arg.Role,
+				ElectionID: arg.ElectionID,
+				GrantedBy:  arg.ActorNationalID,
+			})
+		case util.UnassignRole:
+			_, err = q.DeleteUserRole(ctx, DeleteUserRoleParams{
+				NationalID: arg.TargetNationalID,
+				Role:       arg.Role,
+				ElectionID: arg.ElectionID,
+			})
+		default:
+			return fmt.Errorf("unknown user action %q", arg.Action)
+		}
+		if err != nil {
+			return err
+		}
+
+		if result.User.NationalID == "" {
+			result.User, err = q.GetUser(ctx, arg.TargetNationalID)
+			if err != nil {
+				return err
+			}
+		}
+
+		result.Audit, err = q.CreateUserAudit(ctx, CreateUserAuditParams{
+			ActorNationalID:  arg.ActorNationalID,
+			TargetNationalID: arg.TargetNationalID,
+			Action:           arg.Action,
+			Permission:       sql.NullString{String: arg.Permission, Valid: arg.Permission != ""},
+			Role:             sql.NullString{String: arg.Role, Valid: arg.Role != ""},
+			ElectionID:       arg.ElectionID,
+			Reason:           arg.Reason,
+		})
+		return err
+	})
+
+	return result, err
+}
+
+// resetVoterStatus lets a user vote again in an open election, the ballots already cast in it stay
+// for audit but are superseded and taken out of the candidate tallies. The ballots of other elections,
+// closed ones and the election a runoff was held for included, are left as they were.
+func (q *Queries) resetVoterStatus(ctx context.Context, nationalID string, electionID int64) (User, error) {
+	reset, err := q.ResetUserVoted(ctx, ResetUserVotedParams{
+		ElectionID: electionID,
+		NationalID: nationalID,
+	})
+	if err != nil {
+		return User{}, err
 	}
+	if reset == 0 {
+		return User{}, sql.ErrNoRows
+	}
+
+	err = q.SupersedeUserVotes(ctx, SupersedeUserVotesParams{
+		VoteNationalID: nationalID,
+		ElectionID:     electionID,
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	err = q.SupersedeUserPartyVotes(ctx, SupersedeUserPartyVotesParams{
+		VoteNationalID: nationalID,
+		ElectionID:     electionID,
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	err = q.SupersedeUserMeasureVotes(ctx, SupersedeUserMeasureVotesParams{
+		VoteNationalID: nationalID,
+		ElectionID:     electionID,
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	err = q.RecountUserCandidates(ctx, RecountUserCandidatesParams{
+		VoteNationalID: nationalID,
+		ElectionID:     electionID,
+	})
+	if err != nil {
+		return User{}, err
+	}
+
+	return q.GetUser(ctx, nationalID)
+}
+
+// ProvisionUserTxParams contains the input parameters of the provisioning of a user signing in
+// through the identity provider for the first time
+type ProvisionUserTxParams struct {
+	CreateUserParams
+	EmailVerified bool `json:"email_verified"`
+}
+
+// ProvisionUserTxResult is the result of the provisioning
+type ProvisionUserTxResult struct {
+	User User `json:"user"`
+}
+
+// ProvisionUserTx creates the user in a single transaction, an email the identity provider has
+// verified is marked as verified so no verification email is needed
+func (store *SQLStore) ProvisionUserTx(ctx context.Context, arg ProvisionUserTxParams) (ProvisionUserTxResult, error) {
+	var result ProvisionUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.User, err = q.CreateUser(ctx, arg.CreateUserParams)
+		if err != nil {
+			return err
+		}
+
+		if !arg.EmailVerified {
+			return nil
+		}
+
+		result.User, err = q.VerifyUserEmail(ctx, VerifyUserEmailParams{
+			NationalID: result.User.NationalID,
+			Email:      result.User.Email,
+		})
+		return err
+	})
+
+	return result, err
 }
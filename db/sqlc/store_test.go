@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportVoterRollTx(t *testing.T) {
+	store := NewStore(testDB)
+	district := CreateDistrict(t)
+
+	n := 3
+	entries := make([]VoterRollEntry, n)
+	for i := 0; i < n; i++ {
+		entries[i] = VoterRollEntry{
+			NationalID: CreateUser(t).NationalID,
+			DistrictID: district.ID,
+		}
+	}
+
+	result, err := store.ImportVoterRollTx(context.Background(), ImportVoterRollTxParams{Entries: entries})
+	require.NoError(t, err)
+	require.Len(t, result.Users, n)
+
+	for i, user := range result.Users {
+		require.Equal(t, entries[i].NationalID, user.NationalID)
+		require.Equal(t, sql.NullInt64{Int64: district.ID, Valid: true}, user.DistrictID)
+	}
+}
+
+func TestImportVoterRollTxRollback(t *testing.T) {
+	store := NewStore(testDB)
+	district := CreateDistrict(t)
+	user := CreateUser(t)
+
+	entries := []VoterRollEntry{
+		{NationalID: user.NationalID, DistrictID: district.ID},
+		{NationalID: util.RandomString(13), DistrictID: district.ID},
+	}
+
+	_, err := store.ImportVoterRollTx(context.Background(), ImportVoterRollTxParams{Entries: entries})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	user2, err := testQueries.GetUser(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.False(t, user2.DistrictID.Valid)
+}
+
+func TestImportVoterWeightsTx(t *testing.T) {
+	store := NewStore(testDB)
+	electionID := CreateEditableCandidate(t).ElectionID
+
+	n := 3
+	entries := make([]VoterWeightEntry, n)
+	for i := 0; i < n; i++ {
+		entries[i] = VoterWeightEntry{
+			NationalID: CreateUser(t).NationalID,
+			VoteWeight: util.RandomInt(1, 1000),
+		}
+	}
+
+	arg := ImportVoterWeightsTxParams{
+		ElectionID: electionID,
+		Entries:    entries,
+	}
+
+	result, err := store.ImportVoterWeightsTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Voters, n)
+
+	for i, voter := range result.Voters {
+		require.Equal(t, electionID, voter.ElectionID)
+		require.Equal(t, entries[i].NationalID, voter.NationalID)
+		require.Equal(t, entries[i].VoteWeight, voter.VoteWeight)
+	}
+
+	arg.Entries = []VoterWeightEntry{{NationalID: util.RandomString(13), VoteWeight: 2}}
+	_, err = store.ImportVoterWeightsTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestCastBallotTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateUser(t)
+	candidate := CreateCandidate(t)
+	measure := CreateBallotMeasure(t)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	arg := CastBallotTxParams{
+		Votes: []CreateVoteParams{
+			{
+				VoteNationalID: user.NationalID,
+				CandidateID:    candidate.ID,
+				ReceiptHash:    util.HashReceiptCode(receiptCode),
+			},
+		},
+		MeasureVotes: []CreateMeasureVoteParams{
+			{
+				VoteNationalID: user.NationalID,
+				MeasureID:      measure.Measure.ID,
+				OptionID:       measure.Options[0].ID,
+			},
+		},
+	}
+
+	result, err := store.CastBallotTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Len(t, result.Votes, 1)
+	require.Equal(t, candidate.ID, result.Votes[0].CandidateID)
+	require.Len(t, result.MeasureVotes, 1)
+	require.Equal(t, measure.Options[0].ID, result.MeasureVotes[0].OptionID)
+}
+
+func TestCastBallotTxRollback(t *testing.T) {
+	store := NewStore(testDB)
+	user := CreateUser(t)
+	candidate := CreateCandidate(t)
+	measure := CreateBallotMeasure(t)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	measureVote := CreateMeasureVoteParams{
+		VoteNationalID: user.NationalID,
+		MeasureID:      measure.Measure.ID,
+		OptionID:       measure.Options[0].ID,
+	}
+
+	_, err = store.CastBallotTx(context.Background(), CastBallotTxParams{
+		Votes: []CreateVoteParams{
+			{
+				VoteNationalID: user.NationalID,
+				CandidateID:    candidate.ID,
+				ReceiptHash:    util.HashReceiptCode(receiptCode),
+			},
+		},
+		MeasureVotes: []CreateMeasureVoteParams{measureVote, measureVote},
+	})
+	require.Error(t, err)
+
+	_, err = testQueries.GetVoteByReceipt(context.Background(), util.HashReceiptCode(receiptCode))
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	voted, err := testQueries.HasElectionVoterVoted(context.Background(), HasElectionVoterVotedParams{
+		ElectionID: candidate.ElectionID,
+		NationalID: user.NationalID,
+	})
+	require.NoError(t, err)
+	require.False(t, voted)
+}
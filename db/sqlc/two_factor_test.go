@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupTwoFactor(t *testing.T) {
+	user := CreateUser(t)
+	twoFactor1 := SetupTwoFactor(t, user)
+	twoFactor2 := SetupTwoFactor(t, user)
+	require.NotEqual(t, twoFactor1.Secret, twoFactor2.Secret)
+
+	_, err := testQueries.EnableTwoFactor(context.Background(), user.NationalID)
+	require.NoError(t, err)
+
+	secret, err := util.NewTOTPSecret()
+	require.NoError(t, err)
+
+	_, err = testQueries.SetupTwoFactor(context.Background(), SetupTwoFactorParams{
+		NationalID: user.NationalID,
+		Secret:     secret,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestUseTwoFactorStep(t *testing.T) {
+	user := CreateUser(t)
+	SetupTwoFactor(t, user)
+
+	step := util.TOTPStep(time.Now())
+	arg := UseTwoFactorStepParams{
+		NationalID:   user.NationalID,
+		LastUsedStep: step,
+	}
+
+	rows, err := testQueries.UseTwoFactorStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), rows)
+
+	rows, err = testQueries.UseTwoFactorStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+
+	arg.LastUsedStep = step - 1
+	rows, err = testQueries.UseTwoFactorStep(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, rows)
+}
+
+func TestEnableTwoFactorTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := CreateUser(t)
+	SetupTwoFactor(t, user)
+
+	code, err := util.NewRecoveryCode()
+	require.NoError(t, err)
+
+	arg := EnableTwoFactorTxParams{
+		NationalID:         user.NationalID,
+		RecoveryCodeHashes: []string{util.HashRecoveryCode(code), util.HashRecoveryCode(util.RandomString(10))},
+	}
+
+	result, err := store.EnableTwoFactorTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.TwoFactor.EnabledAt.Valid)
+	require.Len(t, result.RecoveryCodes, 2)
+
+	_, err = store.EnableTwoFactorTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	useArg := UseRecoveryCodeParams{
+		NationalID: user.NationalID,
+		CodeHash:   util.HashRecoveryCode(code),
+	}
+	recoveryCode, err := testQueries.UseRecoveryCode(context.Background(), useArg)
+	require.NoError(t, err)
+	require.True(t, recoveryCode.UsedAt.Valid)
+
+	_, err = testQueries.UseRecoveryCode(context.Background(), useArg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = store.DisableTwoFactorTx(context.Background(), user.NationalID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTwoFactor(context.Background(), user.NationalID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestAttemptTwoFactorChallenge(t *testing.T) {
+	user := CreateUser(t)
+
+	arg := CreateTwoFactorChallengeParams{
+		NationalID: user.NationalID,
+		TokenHash:  util.HashSecretToken(util.RandomString(32)),
+		ExpiredAt:  time.Now().Add(time.Minute),
+	}
+	challenge, err := testQueries.CreateTwoFactorChallenge(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, challenge.Attempts)
+
+	challenge, err = testQueries.AttemptTwoFactorChallenge(context.Background(), arg.TokenHash)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), challenge.Attempts)
+
+	err = testQueries.UseTwoFactorChallenge(context.Background(), challenge.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.AttemptTwoFactorChallenge(context.Background(), arg.TokenHash)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func SetupTwoFactor(t *testing.T, user User) TwoFactor {
+	secret, err := util.NewTOTPSecret()
+	require.NoError(t, err)
+
+	arg := SetupTwoFactorParams{
+		NationalID: user.NationalID,
+		Secret:     secret,
+	}
+
+	twoFactor, err := testQueries.SetupTwoFactor(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.NationalID, twoFactor.NationalID)
+	require.Equal(t, arg.Secret, twoFactor.Secret)
+	require.Zero(t, twoFactor.LastUsedStep)
+	require.False(t, twoFactor.EnabledAt.Valid)
+	return twoFactor
+}
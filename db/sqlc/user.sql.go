@@ -5,26 +5,57 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/lib/pq"
 )
 
+const addUserPermission = `-- name: AddUserPermission :one
+UPDATE users SET permission = array_append(permission, $1::varchar)
+WHERE national_id = $2 AND NOT ($1 = ANY(permission))
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+type AddUserPermissionParams struct {
+	Permission string `json:"permission"`
+	NationalID string `json:"national_id"`
+}
+
+func (q *Queries) AddUserPermission(ctx context.Context, arg AddUserPermissionParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, addUserPermission, arg.Permission, arg.NationalID)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
-  national_id, hashed_password, full_name, email, permission, has_voted
+  national_id, hashed_password, full_name, email, permission, district_id
 ) VALUES (
   $1, $2, $3, $4, $5, $6
 )
-RETURNING national_id, hashed_password, full_name, email, permission, has_voted, password_changed_at, create_at
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
 `
 
 type CreateUserParams struct {
-	NationalID     string   `json:"national_id"`
-	HashedPassword string   `json:"hashed_password"`
-	FullName       string   `json:"full_name"`
-	Email          string   `json:"email"`
-	Permission     []string `json:"permission"`
-	HasVoted       bool     `json:"has_voted"`
+	NationalID     string        `json:"national_id"`
+	HashedPassword string        `json:"hashed_password"`
+	FullName       string        `json:"full_name"`
+	Email          string        `json:"email"`
+	Permission     []string      `json:"permission"`
+	DistrictID     sql.NullInt64 `json:"district_id"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
@@ -34,8 +65,81 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		arg.FullName,
 		arg.Email,
 		pq.Array(arg.Permission),
-		arg.HasVoted,
+		arg.DistrictID,
+	)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const deleteUser = `-- name: DeleteUser :execrows
+DELETE FROM users u
+WHERE u.national_id = $1
+  AND NOT EXISTS (
+    SELECT 1 FROM votes v
+    WHERE v.vote_national_id = u.national_id OR v.cast_by_national_id = u.national_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM measure_votes mv
+    WHERE mv.vote_national_id = u.national_id OR mv.cast_by_national_id = u.national_id
+  )
+  AND NOT EXISTS (
+    SELECT 1 FROM party_votes pv
+    WHERE pv.vote_national_id = u.national_id OR pv.cast_by_national_id = u.national_id
+  )
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, nationalID string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUser, nationalID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const disableUser = `-- name: DisableUser :one
+UPDATE users SET disabled_at = now(), password_changed_at = now()
+WHERE national_id = $1 AND disabled_at IS NULL
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+func (q *Queries) DisableUser(ctx context.Context, nationalID string) (User, error) {
+	row := q.db.QueryRowContext(ctx, disableUser, nationalID)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
 	)
+	return i, err
+}
+
+const enableUser = `-- name: EnableUser :one
+UPDATE users SET disabled_at = NULL
+WHERE national_id = $1 AND disabled_at IS NOT NULL
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+func (q *Queries) EnableUser(ctx context.Context, nationalID string) (User, error) {
+	row := q.db.QueryRowContext(ctx, enableUser, nationalID)
 	var i User
 	err := row.Scan(
 		&i.NationalID,
@@ -43,15 +147,17 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.FullName,
 		&i.Email,
 		pq.Array(&i.Permission),
-		&i.HasVoted,
 		&i.PasswordChangedAt,
 		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT national_id, hashed_password, full_name, email, permission, has_voted, password_changed_at, create_at FROM users
+SELECT national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at FROM users
 WHERE national_id = $1 LIMIT 1
 `
 
@@ -64,9 +170,267 @@ func (q *Queries) GetUser(ctx context.Context, nationalID string) (User, error)
 		&i.FullName,
 		&i.Email,
 		pq.Array(&i.Permission),
-		&i.HasVoted,
 		&i.PasswordChangedAt,
 		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at FROM users
+WHERE email = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const getUserPasswordChangedAt = `-- name: GetUserPasswordChangedAt :one
+SELECT password_changed_at FROM users
+WHERE national_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserPasswordChangedAt(ctx context.Context, nationalID string) (time.Time, error) {
+	row := q.db.QueryRowContext(ctx, getUserPasswordChangedAt, nationalID)
+	var password_changed_at time.Time
+	err := row.Scan(&password_changed_at)
+	return password_changed_at, err
+}
+
+const getUserPermissions = `-- name: GetUserPermissions :one
+SELECT permission FROM users
+WHERE national_id = $1 LIMIT 1
+`
+
+func (q *Queries) GetUserPermissions(ctx context.Context, nationalID string) ([]string, error) {
+	row := q.db.QueryRowContext(ctx, getUserPermissions, nationalID)
+	var permission []string
+	err := row.Scan(pq.Array(&permission))
+	return permission, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at FROM users
+WHERE ($1::text = ''
+    OR national_id ILIKE '%' || $1::text || '%'
+    OR full_name ILIKE '%' || $1::text || '%'
+    OR email ILIKE '%' || $1::text || '%')
+  AND ($2::varchar = '' OR $2::varchar = ANY(permission))
+  AND national_id > $3::varchar
+ORDER BY national_id
+LIMIT $4
+`
+
+type ListUsersParams struct {
+	Search          string `json:"search"`
+	Permission      string `json:"permission"`
+	AfterNationalID string `json:"after_national_id"`
+	Limit           int32  `json:"limit"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers,
+		arg.Search,
+		arg.Permission,
+		arg.AfterNationalID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []User{}
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.NationalID,
+			&i.HashedPassword,
+			&i.FullName,
+			&i.Email,
+			pq.Array(&i.Permission),
+			&i.PasswordChangedAt,
+			&i.CreateAt,
+			&i.DistrictID,
+			&i.VerifiedAt,
+			&i.DisabledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeUserPermission = `-- name: RemoveUserPermission :one
+UPDATE users SET permission = array_remove(permission, $1::varchar)
+WHERE national_id = $2 AND $1 = ANY(permission)
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+type RemoveUserPermissionParams struct {
+	Permission string `json:"permission"`
+	NationalID string `json:"national_id"`
+}
+
+func (q *Queries) RemoveUserPermission(ctx context.Context, arg RemoveUserPermissionParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, removeUserPermission, arg.Permission, arg.NationalID)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const updateUserDistrict = `-- name: UpdateUserDistrict :one
+UPDATE users SET district_id = $2
+WHERE national_id = $1
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+type UpdateUserDistrictParams struct {
+	NationalID string        `json:"national_id"`
+	DistrictID sql.NullInt64 `json:"district_id"`
+}
+
+func (q *Queries) UpdateUserDistrict(ctx context.Context, arg UpdateUserDistrictParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserDistrict, arg.NationalID, arg.DistrictID)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users SET hashed_password = $2, password_changed_at = $3
+WHERE national_id = $1
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+type UpdateUserPasswordParams struct {
+	NationalID        string    `json:"national_id"`
+	HashedPassword    string    `json:"hashed_password"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserPassword, arg.NationalID, arg.HashedPassword, arg.PasswordChangedAt)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+UPDATE users
+SET
+  full_name = $1,
+  email = $2,
+  verified_at = CASE WHEN email = $2 THEN verified_at ELSE NULL END
+WHERE national_id = $3
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+type UpdateUserProfileParams struct {
+	FullName   string `json:"full_name"`
+	Email      string `json:"email"`
+	NationalID string `json:"national_id"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserProfile, arg.FullName, arg.Email, arg.NationalID)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+UPDATE users SET verified_at = now()
+WHERE national_id = $1 AND email = $2
+RETURNING national_id, hashed_password, full_name, email, permission, password_changed_at, create_at, district_id, verified_at, disabled_at
+`
+
+type VerifyUserEmailParams struct {
+	NationalID string `json:"national_id"`
+	Email      string `json:"email"`
+}
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, arg VerifyUserEmailParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, verifyUserEmail, arg.NationalID, arg.Email)
+	var i User
+	err := row.Scan(
+		&i.NationalID,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		pq.Array(&i.Permission),
+		&i.PasswordChangedAt,
+		&i.CreateAt,
+		&i.DistrictID,
+		&i.VerifiedAt,
+		&i.DisabledAt,
 	)
 	return i, err
 }
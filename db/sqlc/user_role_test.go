@@ -0,0 +1,111 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateUserRole(t *testing.T) {
+	admin := CreateUser(t)
+	user := CreateUser(t)
+	election := CreatePartyListElection(t)
+
+	global := CreateUserRole(t, user, admin, util.ObserverRole, sql.NullInt64{})
+	scoped := CreateUserRole(t, user, admin, util.ObserverRole, sql.NullInt64{Int64: election.ID, Valid: true})
+
+	// a role is held once for all elections and once per election
+	_, err := testQueries.CreateUserRole(context.Background(), CreateUserRoleParams{
+		NationalID: user.NationalID,
+		Role:       util.ObserverRole,
+		GrantedBy:  admin.NationalID,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	roles, err := testQueries.ListUserRoles(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.Equal(t, []UserRole{global, scoped}, roles)
+
+	permissions, err := testQueries.GetUserPermissions(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.Equal(t, user.Permission, permissions)
+}
+
+func TestDeleteUserRole(t *testing.T) {
+	admin := CreateUser(t)
+	user := CreateUser(t)
+	election := CreatePartyListElection(t)
+
+	CreateUserRole(t, user, admin, util.AuditorRole, sql.NullInt64{})
+	scoped := CreateUserRole(t, user, admin, util.AuditorRole, sql.NullInt64{Int64: election.ID, Valid: true})
+
+	arg := DeleteUserRoleParams{
+		NationalID: user.NationalID,
+		Role:       util.AuditorRole,
+	}
+	_, err := testQueries.DeleteUserRole(context.Background(), arg)
+	require.NoError(t, err)
+
+	_, err = testQueries.DeleteUserRole(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	roles, err := testQueries.ListUserRoles(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.Equal(t, []UserRole{scoped}, roles)
+}
+
+func TestAdminUpdateUserTxRole(t *testing.T) {
+	store := NewStore(testDB)
+
+	admin := CreateUser(t)
+	user := CreateUser(t)
+	election := CreatePartyListElection(t)
+
+	arg := AdminUpdateUserTxParams{
+		ActorNationalID:  admin.NationalID,
+		TargetNationalID: user.NationalID,
+		Action:           util.AssignRole,
+		Role:             util.ElectionOfficerRole,
+		ElectionID:       sql.NullInt64{Int64: election.ID, Valid: true},
+		Reason:           util.RandomString(20),
+	}
+	result, err := store.AdminUpdateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, user.NationalID, result.User.NationalID)
+	require.Equal(t, arg.Role, result.Audit.Role.String)
+	require.Equal(t, arg.ElectionID, result.Audit.ElectionID)
+
+	_, err = store.AdminUpdateUserTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	arg.Action = util.UnassignRole
+	result, err = store.AdminUpdateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, util.UnassignRole, result.Audit.Action)
+
+	roles, err := testQueries.ListUserRoles(context.Background(), user.NationalID)
+	require.NoError(t, err)
+	require.Empty(t, roles)
+}
+
+func CreateUserRole(t *testing.T, user, grantor User, role string, electionID sql.NullInt64) UserRole {
+	arg := CreateUserRoleParams{
+		NationalID: user.NationalID,
+		Role:       role,
+		ElectionID: electionID,
+		GrantedBy:  grantor.NationalID,
+	}
+
+	userRole, err := testQueries.CreateUserRole(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.NationalID, userRole.NationalID)
+	require.Equal(t, arg.Role, userRole.Role)
+	require.Equal(t, arg.ElectionID, userRole.ElectionID)
+	require.Equal(t, arg.GrantedBy, userRole.GrantedBy)
+	require.NotZero(t, userRole.CreateAt)
+	return userRole
+}
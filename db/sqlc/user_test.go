@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
@@ -26,12 +27,221 @@ func TestGetUser(t *testing.T) {
 	require.Equal(t, user1.FullName, user2.FullName)
 	require.Equal(t, user1.Email, user2.Email)
 	require.Equal(t, user1.Permission, user2.Permission)
-	require.Equal(t, user1.HasVoted, user2.HasVoted)
 
 	require.WithinDuration(t, user1.PasswordChangedAt, user2.PasswordChangedAt, time.Second)
 	require.WithinDuration(t, user1.CreateAt, user2.CreateAt, time.Second)
 }
 
+func TestUpdateUserProfile(t *testing.T) {
+	user1 := CreateUser(t)
+
+	arg := UpdateUserProfileParams{
+		NationalID: user1.NationalID,
+		FullName:   util.RandomName(),
+		Email:      user1.Email,
+	}
+
+	user2, err := testQueries.UpdateUserProfile(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.FullName, user2.FullName)
+	require.Equal(t, user1.VerifiedAt, user2.VerifiedAt)
+
+	arg.Email = util.RandomEmail()
+	user3, err := testQueries.UpdateUserProfile(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Email, user3.Email)
+	require.False(t, user3.VerifiedAt.Valid)
+}
+
+func TestUpdateUserPassword(t *testing.T) {
+	user1 := CreateUser(t)
+
+	hashedPassword, err := util.HashPassword(util.RandomString(6))
+	require.NoError(t, err)
+
+	arg := UpdateUserPasswordParams{
+		NationalID:        user1.NationalID,
+		HashedPassword:    hashedPassword,
+		PasswordChangedAt: time.Now(),
+	}
+
+	user2, err := testQueries.UpdateUserPassword(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.HashedPassword, user2.HashedPassword)
+	require.WithinDuration(t, arg.PasswordChangedAt, user2.PasswordChangedAt, time.Millisecond)
+
+	changedAt, err := testQueries.GetUserPasswordChangedAt(context.Background(), user1.NationalID)
+	require.NoError(t, err)
+	require.WithinDuration(t, arg.PasswordChangedAt, changedAt, time.Millisecond)
+}
+
+func TestDeleteUserTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	delegation := CreateDelegation(t)
+
+	err := store.DeleteUserTx(context.Background(), delegation.ProxyNationalID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetUser(context.Background(), delegation.ProxyNationalID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.GetDelegation(context.Background(), delegation.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	vote := CreateVote(t)
+
+	err = store.DeleteUserTx(context.Background(), vote.VoteNationalID)
+	require.ErrorIs(t, err, ErrUserHasVoted)
+
+	_, err = testQueries.GetUser(context.Background(), vote.VoteNationalID)
+	require.NoError(t, err)
+}
+
+func TestListUsers(t *testing.T) {
+	user := CreateUser(t)
+
+	users, err := testQueries.ListUsers(context.Background(), ListUsersParams{
+		Search:     user.Email,
+		Permission: util.Vote,
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Len(t, users, 1)
+	require.Equal(t, user.NationalID, users[0].NationalID)
+
+	users, err = testQueries.ListUsers(context.Background(), ListUsersParams{
+		Search:          user.Email,
+		Permission:      util.Vote,
+		AfterNationalID: user.NationalID,
+		Limit:           5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, users)
+
+	users, err = testQueries.ListUsers(context.Background(), ListUsersParams{
+		Search:     user.Email,
+		Permission: util.ManageElection,
+		Limit:      5,
+	})
+	require.NoError(t, err)
+	require.Empty(t, users)
+}
+
+func TestAdminUpdateUserTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	admin := CreateUser(t)
+	user := CreateUser(t)
+
+	arg := AdminUpdateUserTxParams{
+		ActorNationalID:  admin.NationalID,
+		TargetNationalID: user.NationalID,
+		Action:           util.GrantPermission,
+		Permission:       util.ManageElection,
+		Reason:           util.RandomString(20),
+	}
+	result, err := store.AdminUpdateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []string{util.Vote, util.ManageElection}, result.User.Permission)
+	require.Equal(t, arg.Reason, result.Audit.Reason)
+	require.Equal(t, util.ManageElection, result.Audit.Permission.String)
+
+	_, err = store.AdminUpdateUserTx(context.Background(), arg)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	arg.Action = util.DisableAccount
+	arg.Permission = ""
+	result, err = store.AdminUpdateUserTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, result.User.DisabledAt.Valid)
+	require.True(t, result.User.PasswordChangedAt.After(user.PasswordChangedAt))
+	require.False(t, result.Audit.Permission.Valid)
+
+	audits, err := testQueries.ListUserAudits(context.Background(), ListUserAuditsParams{
+		TargetNationalID: user.NationalID,
+		Limit:            5,
+	})
+	require.NoError(t, err)
+	require.Len(t, audits, 2)
+	require.Equal(t, util.DisableAccount, audits[0].Action)
+	require.Equal(t, util.GrantPermission, audits[1].Action)
+
+	audits, err = testQueries.ListUserAudits(context.Background(), ListUserAuditsParams{
+		TargetNationalID: user.NationalID,
+		BeforeID:         audits[0].ID,
+		Limit:            5,
+	})
+	require.NoError(t, err)
+	require.Len(t, audits, 1)
+	require.Equal(t, util.GrantPermission, audits[0].Action)
+}
+
+func TestAdminUpdateUserTxResetVoterStatus(t *testing.T) {
+	store := NewStore(testDB)
+
+	admin := CreateUser(t)
+	vote := CreateVote(t)
+
+	candidate, err := testQueries.GetCandidate(context.Background(), vote.CandidateID)
+	require.NoError(t, err)
+
+	// the user has not voted in another election, so there is nothing to reset there
+	other := CreatePartyListElection(t)
+	_, err = store.AdminUpdateUserTx(context.Background(), AdminUpdateUserTxParams{
+		ActorNationalID:  admin.NationalID,
+		TargetNationalID: vote.VoteNationalID,
+		Action:           util.ResetVoterStatus,
+		ElectionID:       sql.NullInt64{Int64: other.ID, Valid: true},
+		Reason:           util.RandomString(20),
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	untouched, err := testQueries.GetVoteByReceipt(context.Background(), vote.ReceiptHash)
+	require.NoError(t, err)
+	require.False(t, untouched.SupersededAt.Valid)
+
+	result, err := store.AdminUpdateUserTx(context.Background(), AdminUpdateUserTxParams{
+		ActorNationalID:  admin.NationalID,
+		TargetNationalID: vote.VoteNationalID,
+		Action:           util.ResetVoterStatus,
+		ElectionID:       sql.NullInt64{Int64: vote.ElectionID, Valid: true},
+		Reason:           util.RandomString(20),
+	})
+	require.NoError(t, err)
+	require.Equal(t, vote.VoteNationalID, result.User.NationalID)
+
+	voted, err := testQueries.HasElectionVoterVoted(context.Background(), HasElectionVoterVotedParams{
+		ElectionID: vote.ElectionID,
+		NationalID: vote.VoteNationalID,
+	})
+	require.NoError(t, err)
+	require.False(t, voted)
+
+	// the ballot stays for audit but no longer counts
+	superseded, err := testQueries.GetVoteByReceipt(context.Background(), vote.ReceiptHash)
+	require.NoError(t, err)
+	require.True(t, superseded.SupersededAt.Valid)
+
+	recounted, err := testQueries.GetCandidate(context.Background(), vote.CandidateID)
+	require.NoError(t, err)
+	require.Equal(t, candidate.VoteCount-1, recounted.VoteCount)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	revote, err := testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: vote.VoteNationalID,
+		CandidateID:    vote.CandidateID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+	require.False(t, revote.SupersededAt.Valid)
+
+	recounted, err = testQueries.GetCandidate(context.Background(), vote.CandidateID)
+	require.NoError(t, err)
+	require.Equal(t, candidate.VoteCount, recounted.VoteCount)
+}
+
 func CreateUser(t *testing.T) User {
 	hasedPassword, err := util.HashPassword(util.RandomString(6))
 	require.NoError(t, err)
@@ -45,7 +255,6 @@ func CreateUser(t *testing.T) User {
 		FullName:       util.RandomName(),
 		Email:          util.RandomEmail(),
 		Permission:     permission,
-		HasVoted:       false,
 	}
 
 	user, err := testQueries.CreateUser(context.Background(), arg)
@@ -57,7 +266,7 @@ func CreateUser(t *testing.T) User {
 	require.Equal(t, arg.FullName, user.FullName)
 	require.Equal(t, arg.Email, user.Email)
 	require.Equal(t, arg.Permission, user.Permission)
-	require.Equal(t, arg.HasVoted, user.HasVoted)
 	require.NotZero(t, user.CreateAt)
+	require.False(t, user.VerifiedAt.Valid)
 	return user
 }
@@ -5,45 +5,138 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
+const countActiveVotes = `-- name: CountActiveVotes :one
+SELECT (
+  SELECT COUNT(DISTINCT v.vote_national_id) FROM votes v
+  WHERE v.election_id = $1 AND v.superseded_at IS NULL
+) + (
+  SELECT COUNT(*) FROM party_votes pv
+  WHERE pv.election_id = $1 AND pv.superseded_at IS NULL
+) AS count
+`
+
+func (q *Queries) CountActiveVotes(ctx context.Context, electionID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countActiveVotes, electionID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createVote = `-- name: CreateVote :one
 INSERT INTO votes (
-  vote_national_id, candidate_id
+  vote_national_id, candidate_id, receipt_hash, cast_by_national_id
 ) VALUES (
-  $1, $2
+  $1, $2, $3, $4
 )
-RETURNING id, vote_national_id, candidate_id, create_at
+RETURNING id, vote_national_id, candidate_id, create_at, receipt_hash, superseded_at, weight, cast_by_national_id, election_id, contest_id
 `
 
 type CreateVoteParams struct {
-	VoteNationalID string `json:"vote_national_id"`
-	CandidateID    int64  `json:"candidate_id"`
+	VoteNationalID   string         `json:"vote_national_id"`
+	CandidateID      int64          `json:"candidate_id"`
+	ReceiptHash      string         `json:"receipt_hash"`
+	CastByNationalID sql.NullString `json:"cast_by_national_id"`
 }
 
 func (q *Queries) CreateVote(ctx context.Context, arg CreateVoteParams) (Vote, error) {
-	row := q.db.QueryRowContext(ctx, createVote, arg.VoteNationalID, arg.CandidateID)
+	row := q.db.QueryRowContext(ctx, createVote,
+		arg.VoteNationalID,
+		arg.CandidateID,
+		arg.ReceiptHash,
+		arg.CastByNationalID,
+	)
+	var i Vote
+	err := row.Scan(
+		&i.ID,
+		&i.VoteNationalID,
+		&i.CandidateID,
+		&i.CreateAt,
+		&i.ReceiptHash,
+		&i.SupersededAt,
+		&i.Weight,
+		&i.CastByNationalID,
+		&i.ElectionID,
+		&i.ContestID,
+	)
+	return i, err
+}
+
+const getVoteByReceipt = `-- name: GetVoteByReceipt :one
+SELECT id, vote_national_id, candidate_id, create_at, receipt_hash, superseded_at, weight, cast_by_national_id, election_id, contest_id FROM votes
+WHERE receipt_hash = $1 LIMIT 1
+`
+
+func (q *Queries) GetVoteByReceipt(ctx context.Context, receiptHash string) (Vote, error) {
+	row := q.db.QueryRowContext(ctx, getVoteByReceipt, receiptHash)
 	var i Vote
 	err := row.Scan(
 		&i.ID,
 		&i.VoteNationalID,
 		&i.CandidateID,
 		&i.CreateAt,
+		&i.ReceiptHash,
+		&i.SupersededAt,
+		&i.Weight,
+		&i.CastByNationalID,
+		&i.ElectionID,
+		&i.ContestID,
 	)
 	return i, err
 }
 
+const listElectionVotes = `-- name: ListElectionVotes :many
+SELECT receipt_hash FROM votes
+WHERE election_id = $1 AND receipt_hash > $2
+ORDER BY receipt_hash
+LIMIT $3
+`
+
+type ListElectionVotesParams struct {
+	ElectionID  int64  `json:"election_id"`
+	ReceiptHash string `json:"receipt_hash"`
+	Limit       int32  `json:"limit"`
+}
+
+func (q *Queries) ListElectionVotes(ctx context.Context, arg ListElectionVotesParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, listElectionVotes, arg.ElectionID, arg.ReceiptHash, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var receipt_hash string
+		if err := rows.Scan(&receipt_hash); err != nil {
+			return nil, err
+		}
+		items = append(items, receipt_hash)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listVoteOrderByCandidate = `-- name: ListVoteOrderByCandidate :many
 SELECT 
  candidate_id,
- vote_national_id
+ vote_national_id,
+ cast_by_national_id
  FROM votes
+WHERE superseded_at IS NULL
 ORDER BY candidate_id
 `
 
 type ListVoteOrderByCandidateRow struct {
-	CandidateID    int64  `json:"candidate_id"`
-	VoteNationalID string `json:"vote_national_id"`
+	CandidateID      int64          `json:"candidate_id"`
+	VoteNationalID   string         `json:"vote_national_id"`
+	CastByNationalID sql.NullString `json:"cast_by_national_id"`
 }
 
 func (q *Queries) ListVoteOrderByCandidate(ctx context.Context) ([]ListVoteOrderByCandidateRow, error) {
@@ -55,7 +148,7 @@ func (q *Queries) ListVoteOrderByCandidate(ctx context.Context) ([]ListVoteOrder
 	items := []ListVoteOrderByCandidateRow{}
 	for rows.Next() {
 		var i ListVoteOrderByCandidateRow
-		if err := rows.Scan(&i.CandidateID, &i.VoteNationalID); err != nil {
+		if err := rows.Scan(&i.CandidateID, &i.VoteNationalID, &i.CastByNationalID); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -68,3 +161,18 @@ func (q *Queries) ListVoteOrderByCandidate(ctx context.Context) ([]ListVoteOrder
 	}
 	return items, nil
 }
+
+const supersedeUserVotes = `-- name: SupersedeUserVotes :exec
+UPDATE votes SET superseded_at = now()
+WHERE vote_national_id = $1 AND election_id = $2 AND superseded_at IS NULL
+`
+
+type SupersedeUserVotesParams struct {
+	VoteNationalID string `json:"vote_national_id"`
+	ElectionID     int64  `json:"election_id"`
+}
+
+func (q *Queries) SupersedeUserVotes(ctx context.Context, arg SupersedeUserVotesParams) error {
+	_, err := q.db.ExecContext(ctx, supersedeUserVotes, arg.VoteNationalID, arg.ElectionID)
+	return err
+}
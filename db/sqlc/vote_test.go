@@ -2,7 +2,11 @@ package db
 
 import (
 	"context"
+	"sort"
 	"testing"
+	"time"
+
+	"election/util"
 
 	"github.com/stretchr/testify/require"
 )
@@ -24,13 +28,150 @@ func TestListVoteOrderByCandidate(t *testing.T) {
 	}
 }
 
+func TestListElectionVotes(t *testing.T) {
+	var electionID int64
+	var created []string
+	for i := 0; i < 3; i++ {
+		voted := CreateVote(t)
+		electionID = voted.ElectionID
+		created = append(created, voted.ReceiptHash)
+	}
+	sort.Strings(created)
+	after := created[0][:len(created[0])-1]
+
+	votes, err := testQueries.ListElectionVotes(context.Background(), ListElectionVotesParams{
+		ElectionID:  electionID,
+		ReceiptHash: after,
+		Limit:       100,
+	})
+	require.NoError(t, err)
+	require.True(t, sort.StringsAreSorted(votes))
+	require.Subset(t, votes, created)
+
+	votes, err = testQueries.ListElectionVotes(context.Background(), ListElectionVotesParams{
+		ElectionID:  electionID,
+		ReceiptHash: created[1],
+		Limit:       100,
+	})
+	require.NoError(t, err)
+	require.Contains(t, votes, created[2])
+	require.NotContains(t, votes, created[1])
+	require.NotContains(t, votes, created[0])
+}
+
+func TestGetVoteByReceipt(t *testing.T) {
+	voted1 := CreateVote(t)
+
+	voted2, err := testQueries.GetVoteByReceipt(context.Background(), voted1.ReceiptHash)
+	require.NoError(t, err)
+	require.NotEmpty(t, voted2)
+
+	require.Equal(t, voted1.ID, voted2.ID)
+	require.Equal(t, voted1.VoteNationalID, voted2.VoteNationalID)
+	require.Equal(t, voted1.CandidateID, voted2.CandidateID)
+	require.WithinDuration(t, voted1.CreateAt, voted2.CreateAt, time.Second)
+}
+
+func TestRevote(t *testing.T) {
+	setRevoteEnabled(t, true)
+	defer setRevoteEnabled(t, false)
+
+	voted1 := CreateVote(t)
+	candidate1, err := testQueries.GetCandidate(context.Background(), voted1.CandidateID)
+	require.NoError(t, err)
+
+	candidate2 := CreateCandidate(t)
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	voted2, err := testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: voted1.VoteNationalID,
+		CandidateID:    candidate2.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+	require.False(t, voted2.SupersededAt.Valid)
+
+	superseded, err := testQueries.GetVoteByReceipt(context.Background(), voted1.ReceiptHash)
+	require.NoError(t, err)
+	require.True(t, superseded.SupersededAt.Valid)
+
+	updatedCandidate1, err := testQueries.GetCandidate(context.Background(), candidate1.ID)
+	require.NoError(t, err)
+	require.Equal(t, candidate1.VoteCount-1, updatedCandidate1.VoteCount)
+
+	updatedCandidate2, err := testQueries.GetCandidate(context.Background(), candidate2.ID)
+	require.NoError(t, err)
+	require.Equal(t, candidate2.VoteCount+1, updatedCandidate2.VoteCount)
+}
+
+func TestWeightedVote(t *testing.T) {
+	user := CreateUser(t)
+	candidate := CreateEditableCandidate(t)
+
+	voter, err := testQueries.SetElectionVoterWeight(context.Background(), SetElectionVoterWeightParams{
+		ElectionID: candidate.ElectionID,
+		NationalID: user.NationalID,
+		VoteWeight: util.RandomInt(2, 1000),
+	})
+	require.NoError(t, err)
+
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	voted, err := testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: user.NationalID,
+		CandidateID:    candidate.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.NoError(t, err)
+	require.Equal(t, voter.VoteWeight, voted.Weight)
+
+	candidate2, err := testQueries.GetCandidate(context.Background(), candidate.ID)
+	require.NoError(t, err)
+	require.Equal(t, candidate.VoteCount+1, candidate2.VoteCount)
+
+	results, err := testQueries.ListCandidatesResult(context.Background())
+	require.NoError(t, err)
+	for _, result := range results {
+		if result.ID == candidate.ID {
+			require.Equal(t, candidate.WeightedVoteCount+voter.VoteWeight, result.WeightedVoteCount)
+		}
+	}
+}
+
+func TestRevoteDisabled(t *testing.T) {
+	voted := CreateVote(t)
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateVote(context.Background(), CreateVoteParams{
+		VoteNationalID: voted.VoteNationalID,
+		CandidateID:    voted.CandidateID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
+	})
+	require.Error(t, err)
+}
+
+func setRevoteEnabled(t *testing.T, enable bool) {
+	_, err := testQueries.UpdateElectionProperty(context.Background(), UpdateElectionPropertyParams{
+		Name:  util.RevoteEnabled,
+		Value: enable,
+	})
+	require.NoError(t, err)
+}
+
 func CreateVote(t *testing.T) Vote {
 	user := CreateUser(t)
 	candidate := CreateCandidate(t)
 
+	receiptCode, err := util.NewReceiptCode()
+	require.NoError(t, err)
+
 	arg := CreateVoteParams{
 		VoteNationalID: user.NationalID,
 		CandidateID:    candidate.ID,
+		ReceiptHash:    util.HashReceiptCode(receiptCode),
 	}
 
 	voted, err := testQueries.CreateVote(context.Background(), arg)
@@ -39,6 +180,7 @@ func CreateVote(t *testing.T) Vote {
 
 	require.Equal(t, arg.VoteNationalID, voted.VoteNationalID)
 	require.Equal(t, arg.CandidateID, voted.CandidateID)
+	require.Equal(t, arg.ReceiptHash, voted.ReceiptHash)
 
 	candidate2, err := testQueries.GetCandidate(context.Background(), candidate.ID)
 	require.NoError(t, err)
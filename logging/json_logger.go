@@ -0,0 +1,106 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONLogger writes every entry as a line of JSON with the time, level and message first
+type JSONLogger struct {
+	mu     *sync.Mutex
+	out    io.Writer
+	level  Level
+	fields []Field
+	now    func() time.Time
+}
+
+// NewJSONLogger creates a JSONLogger writing the entries at or above the level
+func NewJSONLogger(out io.Writer, level Level) Logger {
+	return &JSONLogger{
+		mu:    &sync.Mutex{},
+		out:   out,
+		level: level,
+		now:   time.Now,
+	}
+}
+
+// NewNopLogger creates a logger which discards every entry
+func NewNopLogger() Logger {
+	return NewJSONLogger(io.Discard, ErrorLevel+1)
+}
+
+// Debug implements Logger
+func (logger *JSONLogger) Debug(msg string, fields ...Field) {
+	logger.write(DebugLevel, msg, fields)
+}
+
+// Info implements Logger
+func (logger *JSONLogger) Info(msg string, fields ...Field) {
+	logger.write(InfoLevel, msg, fields)
+}
+
+// Warn implements Logger
+func (logger *JSONLogger) Warn(msg string, fields ...Field) {
+	logger.write(WarnLevel, msg, fields)
+}
+
+// Error implements Logger
+func (logger *JSONLogger) Error(msg string, fields ...Field) {
+	logger.write(ErrorLevel, msg, fields)
+}
+
+// With implements Logger
+func (logger *JSONLogger) With(fields ...Field) Logger {
+	child := *logger
+	child.fields = make([]Field, 0, len(logger.fields)+len(fields))
+	child.fields = append(child.fields, logger.fields...)
+	child.fields = append(child.fields, fields...)
+	return &child
+}
+
+func (logger *JSONLogger) write(level Level, msg string, fields []Field) {
+	if level < logger.level {
+		return
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, logger.now().UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, RedactString(msg))
+
+	for _, field := range logger.fields {
+		writeField(&buf, field)
+	}
+	for _, field := range fields {
+		writeField(&buf, field)
+	}
+	buf.WriteString("}\n")
+
+	logger.mu.Lock()
+	defer logger.mu.Unlock()
+	logger.out.Write(buf.Bytes())
+}
+
+func writeField(buf *bytes.Buffer, field Field) {
+	field = Redact(field)
+
+	buf.WriteByte(',')
+	writeJSON(buf, field.Key)
+	buf.WriteByte(':')
+	writeJSON(buf, field.Value)
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		// every field constructor creates a value JSON can encode, an unsupported value is logged as its error
+		data, _ = json.Marshal("!" + err.Error())
+	}
+	buf.Write(data)
+}
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestLogger(level Level) (*JSONLogger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	logger := NewJSONLogger(&buf, level).(*JSONLogger)
+	logger.now = func() time.Time {
+		return time.Date(2022, 7, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return logger, &buf
+}
+
+func TestJSONLogger(t *testing.T) {
+	logger, buf := newTestLogger(InfoLevel)
+
+	requestLogger := logger.With(String("request_id", "abc"))
+	requestLogger.Info("request served",
+		String("route", "/api/vote"),
+		Int("status", 200),
+		Int64("election_id", 1),
+		Bool("cached", false),
+		Duration("latency", 1500*time.Millisecond),
+	)
+	logger.Error("request failed", Err(errors.New("connection refused")))
+
+	require.Equal(t, `{"time":"2022-07-01T12:00:00Z","level":"info","msg":"request served","request_id":"abc","route":"/api/vote","status":200,"election_id":1,"cached":false,"latency":1.5}
+{"time":"2022-07-01T12:00:00Z","level":"error","msg":"request failed","error":"connection refused"}
+`, buf.String())
+}
+
+func TestJSONLoggerLevel(t *testing.T) {
+	logger, buf := newTestLogger(WarnLevel)
+
+	logger.Debug("debug")
+	logger.Info("info")
+	logger.Warn("warn")
+	logger.Error("error")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	require.Equal(t, "warn", entry["level"])
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	require.Equal(t, "error", entry["level"])
+}
+
+func TestJSONLoggerRedaction(t *testing.T) {
+	logger, buf := newTestLogger(DebugLevel)
+
+	logger.Warn("login failed for 1234567890123",
+		String("national_id", "1234567890123"),
+		String("password", "secret"),
+		String("path", "/api/admin/users/1234567890123/disable"),
+		Err(errors.New(`pq: duplicate key value violates unique constraint, Key (national_id)=(1234567890123) already exists`)),
+	)
+
+	require.NotContains(t, buf.String(), "1234567890123")
+	require.NotContains(t, buf.String(), `"secret"`)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	require.Equal(t, "login failed for "+Redacted, entry["msg"])
+	require.Equal(t, Redacted, entry["national_id"])
+	require.Equal(t, Redacted, entry["password"])
+	require.Equal(t, "/api/admin/users/"+Redacted+"/disable", entry["path"])
+}
+
+func TestNopLogger(t *testing.T) {
+	logger := NewNopLogger()
+	logger.Error("discarded")
+	logger.With(String("key", "value")).Error("discarded")
+}
+
+func TestParseLevel(t *testing.T) {
+	for name, level := range map[string]Level{"": InfoLevel, "debug": DebugLevel, "INFO": InfoLevel, "warn": WarnLevel, "error": ErrorLevel} {
+		parsed, err := ParseLevel(name)
+		require.NoError(t, err)
+		require.Equal(t, level, parsed)
+	}
+
+	_, err := ParseLevel("verbose")
+	require.Error(t, err)
+}
+
+func TestRequestID(t *testing.T) {
+	ctx := context.Background()
+	require.Empty(t, RequestID(ctx))
+
+	ctx = WithRequestID(ctx, "abc")
+	require.Equal(t, "abc", RequestID(ctx))
+}
+
+func TestIsValidRequestID(t *testing.T) {
+	require.True(t, IsValidRequestID("4f9c2d1e-7b1a-4c55-9d3e-2a6f8e0b1c7d"))
+	require.True(t, IsValidRequestID("frontend.42_a"))
+	require.False(t, IsValidRequestID(""))
+	require.False(t, IsValidRequestID(strings.Repeat("a", 65)))
+	require.False(t, IsValidRequestID("*/ DROP TABLE users; /*"))
+	require.False(t, IsValidRequestID("id\nwith newline"))
+}
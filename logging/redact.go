@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces a value which must not be written to the logs
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys are the normalized field keys whose values are always redacted
+var sensitiveKeys = map[string]bool{
+	"authorization": true,
+	"cookie":        true,
+	"code":          true,
+	"codeverifier":  true,
+	"recoverycode":  true,
+}
+
+// sensitiveSuffixes redact the value of a key ending in one of them, e.g. "new_password" or "access_token"
+var sensitiveSuffixes = []string{
+	"nationalid",
+	"password",
+	"token",
+	"secret",
+}
+
+var (
+	// nationalIDPattern matches a 13 digits national ID which is not part of a longer number
+	nationalIDPattern = regexp.MustCompile(`(^|\D)\d{13}(\D|$)`)
+	// bearerPattern matches the credentials of an authorization header
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[^\s"',]+`)
+	// pasetoPattern matches a PASETO access token
+	pasetoPattern = regexp.MustCompile(`\bv[1-4]\.(local|public)\.[A-Za-z0-9_\-]+(\.[A-Za-z0-9_\-]+)?`)
+	// queryPattern matches a secret passed as a query or form parameter
+	queryPattern = regexp.MustCompile(`(?i)\b(token|code|state|password|secret)=[^&\s"']+`)
+)
+
+// Redact returns the field with its value redacted when the key is sensitive, and with national
+// IDs and tokens removed from a string value
+func Redact(field Field) Field {
+	if isSensitiveKey(field.Key) {
+		return Field{Key: field.Key, Value: Redacted}
+	}
+	if value, ok := field.Value.(string); ok {
+		return Field{Key: field.Key, Value: RedactString(value)}
+	}
+	return field
+}
+
+// RedactString removes national IDs, bearer credentials, access tokens and secret query
+// parameters from a message
+func RedactString(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "$1 "+Redacted)
+	s = pasetoPattern.ReplaceAllString(s, Redacted)
+	s = queryPattern.ReplaceAllString(s, "$1="+Redacted)
+	// the pattern consumes the delimiters, adjacent IDs need a second pass
+	for nationalIDPattern.MatchString(s) {
+		s = nationalIDPattern.ReplaceAllString(s, "${1}"+Redacted+"${2}")
+	}
+	return s
+}
+
+func isSensitiveKey(key string) bool {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "", ".", "").Replace(key))
+	if sensitiveKeys[normalized] {
+		return true
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer, err := NewFileMailer(dir, "election@example.com")
+	require.NoError(t, err)
+
+	msg := Message{
+		To:       "jane@example.com",
+		Subject:  "Hello",
+		TextBody: "plain body",
+		HTMLBody: "<p>html body</p>",
+	}
+	err = mailer.Send(context.Background(), msg)
+	require.NoError(t, err)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	data, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	require.Contains(t, string(data), "From: election@example.com\r\n")
+	require.Contains(t, string(data), "To: jane@example.com\r\n")
+	require.Contains(t, string(data), "multipart/alternative")
+	require.Contains(t, string(data), "plain body")
+	require.Contains(t, string(data), "<p>html body</p>")
+}
+
+func TestFileMailerInvalidHeader(t *testing.T) {
+	mailer, err := NewFileMailer(t.TempDir(), "election@example.com")
+	require.NoError(t, err)
+
+	for _, msg := range []Message{
+		{To: "jane@example.com\r\nBcc: eve@example.com", Subject: "Hello"},
+		{To: "jane@example.com", Subject: "Hello\nBcc: eve@example.com"},
+	} {
+		err = mailer.Send(context.Background(), msg)
+		require.ErrorIs(t, err, ErrInvalidHeader)
+	}
+}
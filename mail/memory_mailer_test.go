@@ -0,0 +1,17 @@
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryMailer(t *testing.T) {
+	mailer := NewMemoryMailer()
+
+	msg := Message{To: "jane@example.com", Subject: "Hello", TextBody: "body"}
+	require.NoError(t, mailer.Send(context.Background(), msg))
+
+	require.Equal(t, []Message{msg}, mailer.Messages())
+}
@@ -0,0 +1,90 @@
+package mail
+
+import (
+	"context"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// serveSMTP answers a single SMTP session without STARTTLS or AUTH and returns the received mail data
+func serveSMTP(listener net.Listener) <-chan string {
+	received := make(chan string, 1)
+
+	go func() {
+		defer close(received)
+
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+
+			switch command := strings.ToUpper(strings.Fields(line)[0]); command {
+			case "EHLO", "HELO":
+				text.PrintfLine("250 localhost")
+			case "MAIL", "RCPT":
+				text.PrintfLine("250 OK")
+			case "DATA":
+				text.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				received <- string(data)
+				text.PrintfLine("250 OK")
+			case "QUIT":
+				text.PrintfLine("221 bye")
+				return
+			default:
+				text.PrintfLine("502 not implemented")
+			}
+		}
+	}()
+
+	return received
+}
+
+func TestSMTPMailer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	received := serveSMTP(listener)
+
+	addr := listener.Addr().(*net.TCPAddr)
+	mailer, err := NewSMTPMailer("127.0.0.1", addr.Port, "", "", "election@example.com")
+	require.NoError(t, err)
+
+	err = mailer.Send(context.Background(), Message{
+		To:       "jane@example.com",
+		Subject:  "Hello",
+		TextBody: "plain body",
+	})
+	require.NoError(t, err)
+
+	data := <-received
+	require.Contains(t, data, "To: jane@example.com")
+	require.Contains(t, data, "Subject: Hello")
+	require.Contains(t, data, "plain body")
+}
+
+func TestNewSMTPMailerInvalid(t *testing.T) {
+	_, err := NewSMTPMailer("", 25, "", "", "election@example.com")
+	require.Error(t, err)
+
+	_, err = NewSMTPMailer("localhost", 25, "", "", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,78 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Names of the email templates below the templates directory
+const (
+	PasswordResetTemplate     = "password_reset"
+	EmailVerificationTemplate = "email_verification"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+// mailTemplate is a template file parsed once for the plain text parts and once for the html body,
+// so the html body is escaped for its context
+type mailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]mailTemplate {
+	files, err := fs.Glob(templateFiles, "templates/*.tmpl")
+	if err != nil {
+		panic(err)
+	}
+
+	templates := make(map[string]mailTemplate, len(files))
+	for _, file := range files {
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+		templates[name] = mailTemplate{
+			text: texttemplate.Must(texttemplate.ParseFS(templateFiles, file)),
+			html: htmltemplate.Must(htmltemplate.ParseFS(templateFiles, file)),
+		}
+	}
+	return templates
+}
+
+// Render builds the message of a template for the recipient, every template file defines
+// a "subject", a plain "text" body and an "html" body
+func Render(name string, to string, data interface{}) (Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown mail template %q", name)
+	}
+
+	msg := Message{To: to}
+
+	var buf bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&buf, "subject", data); err != nil {
+		return Message{}, err
+	}
+	msg.Subject = strings.TrimSpace(buf.String())
+
+	buf.Reset()
+	if err := tmpl.text.ExecuteTemplate(&buf, "text", data); err != nil {
+		return Message{}, err
+	}
+	msg.TextBody = buf.String()
+
+	buf.Reset()
+	if err := tmpl.html.ExecuteTemplate(&buf, "html", data); err != nil {
+		return Message{}, err
+	}
+	msg.HTMLBody = buf.String()
+
+	return msg, nil
+}
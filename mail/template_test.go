@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPasswordReset(t *testing.T) {
+	data := map[string]string{
+		"Name":      "<b>Jane</b>",
+		"Link":      "https://vote.example.com/reset-password?token=abc",
+		"ExpiresIn": "1h0m0s",
+	}
+
+	msg, err := Render(PasswordResetTemplate, "jane@example.com", data)
+	require.NoError(t, err)
+	require.Equal(t, "jane@example.com", msg.To)
+	require.Equal(t, "Reset your election account password", msg.Subject)
+
+	require.Contains(t, msg.TextBody, "Hello <b>Jane</b>")
+	require.Contains(t, msg.TextBody, data["Link"])
+
+	require.Contains(t, msg.HTMLBody, "Hello &lt;b&gt;Jane&lt;/b&gt;")
+	require.Contains(t, msg.HTMLBody, `href="https://vote.example.com/reset-password?token=abc"`)
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	_, err := Render("missing", "jane@example.com", nil)
+	require.Error(t, err)
+}
+
+func TestRenderEmailVerification(t *testing.T) {
+	data := map[string]string{
+		"Name":      "Jane",
+		"Email":     "jane@example.com",
+		"Link":      "https://vote.example.com/verify-email?token=abc",
+		"ExpiresIn": "24h0m0s",
+	}
+
+	msg, err := Render(EmailVerificationTemplate, "jane@example.com", data)
+	require.NoError(t, err)
+	require.Equal(t, "Confirm the email of your election account", msg.Subject)
+	require.Contains(t, msg.TextBody, data["Link"])
+	require.Contains(t, msg.HTMLBody, `href="https://vote.example.com/verify-email?token=abc"`)
+}
@@ -2,36 +2,41 @@ package main
 
 import (
 	"database/sql"
-	"log"
+	"os"
 
 	"election/api"
 	db "election/db/sqlc"
+	"election/logging"
 	"election/util"
 
 	_ "github.com/lib/pq"
 )
 
 func main() {
+	logger := logging.NewJSONLogger(os.Stderr, logging.InfoLevel)
 
 	config, err := util.LoadConfig(".")
 	if err != nil {
-		log.Fatal("cannot load configuartion: ", err)
+		logger.Error("cannot load configuration", logging.Err(err))
+		os.Exit(1)
 	}
 
 	conn, err := sql.Open(config.DBDriver, config.DBSource)
 	if err != nil {
-		log.Fatal("cannot connect to db: ", err)
+		logger.Error("cannot connect to db", logging.Err(err))
+		os.Exit(1)
 	}
 
 	store := db.NewStore(conn)
 	server, err := api.NewServer(config, store)
 	if err != nil {
-		log.Fatal("cannot create a server: ", err)
+		logger.Error("cannot create a server", logging.Err(err))
+		os.Exit(1)
 	}
 
 	err = server.Start(config.ServerAddress)
 	if err != nil {
-		log.Fatal("cannot start server:", err)
+		logger.Error("cannot start server", logging.Err(err))
+		os.Exit(1)
 	}
-
 }
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+)
+
+// collected is a metric whose samples are read when the registry is written, e.g. database state
+type collected struct {
+	desc
+	collect CollectFunc
+}
+
+// NewGaugeFunc registers a gauge, a value which goes up and down, read by collect on every write
+func (registry *Registry) NewGaugeFunc(name, help string, labels []string, collect CollectFunc) {
+	registry.register(&collected{
+		desc:    desc{metricName: name, help: help, metricType: gaugeType, labels: labels},
+		collect: collect,
+	})
+}
+
+// NewCounterFunc registers a counter kept elsewhere, e.g. by database/sql, read by collect on every write
+func (registry *Registry) NewCounterFunc(name, help string, labels []string, collect CollectFunc) {
+	registry.register(&collected{
+		desc:    desc{metricName: name, help: help, metricType: counterType, labels: labels},
+		collect: collect,
+	})
+}
+
+func (c *collected) write(ctx context.Context, w *bufio.Writer) error {
+	samples, err := c.collect(ctx)
+	if err != nil {
+		return err
+	}
+
+	c.writeHeader(w)
+	for _, sample := range samples {
+		c.checkLabelValues(sample.LabelValues)
+		c.writeSample(w, "", sample.LabelValues, "", sample.Value)
+	}
+	return nil
+}
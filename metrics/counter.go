@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"sort"
+	"sync"
+)
+
+// Counter is a value which only goes up, e.g. the number of requests, counted per label values
+type Counter struct {
+	desc
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+// NewCounter registers a counter with the names of its labels
+func (registry *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	counter := &Counter{
+		desc:   desc{metricName: name, help: help, metricType: counterType, labels: labels},
+		series: make(map[string]*counterSeries),
+	}
+	registry.register(counter)
+	return counter
+}
+
+// Inc adds one to the series of the label values
+func (counter *Counter) Inc(labelValues ...string) {
+	counter.Add(1, labelValues...)
+}
+
+// Add adds a non-negative value to the series of the label values
+func (counter *Counter) Add(value float64, labelValues ...string) {
+	counter.checkLabelValues(labelValues)
+	if value < 0 {
+		panic("metrics: counter " + counter.metricName + " cannot decrease")
+	}
+
+	key := seriesKey(labelValues)
+
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	series, ok := counter.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: append([]string(nil), labelValues...)}
+		counter.series[key] = series
+	}
+	series.value += value
+}
+
+// Value returns the current value of the series of the label values
+func (counter *Counter) Value(labelValues ...string) float64 {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	if series, ok := counter.series[seriesKey(labelValues)]; ok {
+		return series.value
+	}
+	return 0
+}
+
+func (counter *Counter) write(ctx context.Context, w *bufio.Writer) error {
+	counter.mu.Lock()
+	defer counter.mu.Unlock()
+
+	keys := make([]string, 0, len(counter.series))
+	for key := range counter.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	counter.writeHeader(w)
+	for _, key := range keys {
+		series := counter.series[key]
+		counter.writeSample(w, "", series.labelValues, "", series.value)
+	}
+	return nil
+}
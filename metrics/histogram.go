@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"sort"
+	"sync"
+)
+
+// Histogram counts observations, e.g. request latencies, in buckets per label values
+type Histogram struct {
+	desc
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64
+	count       uint64
+	sum         float64
+}
+
+// NewHistogram registers a histogram with the upper bounds of its buckets and the names of its labels
+func (registry *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+
+	histogram := &Histogram{
+		desc:    desc{metricName: name, help: help, metricType: histogramType, labels: labels},
+		buckets: bounds,
+		series:  make(map[string]*histogramSeries),
+	}
+	registry.register(histogram)
+	return histogram
+}
+
+// Observe records a value in the series of the label values
+func (histogram *Histogram) Observe(value float64, labelValues ...string) {
+	histogram.checkLabelValues(labelValues)
+	key := seriesKey(labelValues)
+
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	series, ok := histogram.series[key]
+	if !ok {
+		series = &histogramSeries{
+			labelValues: append([]string(nil), labelValues...),
+			counts:      make([]uint64, len(histogram.buckets)),
+		}
+		histogram.series[key] = series
+	}
+
+	// buckets are stored non-cumulative and summed when written
+	if i := sort.SearchFloat64s(histogram.buckets, value); i < len(histogram.buckets) {
+		series.counts[i]++
+	}
+	series.count++
+	series.sum += value
+}
+
+// Count returns the number of observations in the series of the label values
+func (histogram *Histogram) Count(labelValues ...string) uint64 {
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	if series, ok := histogram.series[seriesKey(labelValues)]; ok {
+		return series.count
+	}
+	return 0
+}
+
+func (histogram *Histogram) write(ctx context.Context, w *bufio.Writer) error {
+	histogram.mu.Lock()
+	defer histogram.mu.Unlock()
+
+	keys := make([]string, 0, len(histogram.series))
+	for key := range histogram.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	histogram.writeHeader(w)
+	for _, key := range keys {
+		series := histogram.series[key]
+
+		var cumulative uint64
+		for i, bound := range histogram.buckets {
+			cumulative += series.counts[i]
+			histogram.writeSample(w, "_bucket", series.labelValues, `le="`+formatFloat(bound)+`"`, float64(cumulative))
+		}
+		histogram.writeSample(w, "_bucket", series.labelValues, `le="+Inf"`, float64(series.count))
+		histogram.writeSample(w, "_sum", series.labelValues, "", series.sum)
+		histogram.writeSample(w, "_count", series.labelValues, "", float64(series.count))
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Package metrics keeps counters, histograms and gauges and exposes them in the Prometheus text format
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the content type of the text format written by Registry.Write
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+)
+
+// DefaultBuckets are the upper bounds in seconds of the request latency histograms
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Sample is a value of a metric collected when the registry is written
+type Sample struct {
+	LabelValues []string
+	Value       float64
+}
+
+// CollectFunc reads the current samples of a metric, e.g. from the database
+type CollectFunc func(ctx context.Context) ([]Sample, error)
+
+// family is a metric with all its labelled series
+type family interface {
+	name() string
+	write(ctx context.Context, w *bufio.Writer) error
+}
+
+// Registry holds the metrics of a process
+type Registry struct {
+	mu       sync.Mutex
+	families []family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (registry *Registry) register(f family) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for _, existing := range registry.families {
+		if existing.name() == f.name() {
+			panic(fmt.Sprintf("metrics: %s registered twice", f.name()))
+		}
+	}
+	registry.families = append(registry.families, f)
+}
+
+// Write writes every metric in the text format, it fails when a collected metric cannot be read
+func (registry *Registry) Write(ctx context.Context, w io.Writer) error {
+	registry.mu.Lock()
+	families := make([]family, len(registry.families))
+	copy(families, registry.families)
+	registry.mu.Unlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name() < families[j].name()
+	})
+
+	buf := bufio.NewWriter(w)
+	for _, f := range families {
+		if err := f.write(ctx, buf); err != nil {
+			return fmt.Errorf("failed to collect %s: %w", f.name(), err)
+		}
+	}
+	return buf.Flush()
+}
+
+// desc describes a metric and the names of its labels
+type desc struct {
+	metricName string
+	help       string
+	metricType string
+	labels     []string
+}
+
+func (d desc) name() string {
+	return d.metricName
+}
+
+func (d desc) writeHeader(w *bufio.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", d.metricName, escapeHelp(d.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", d.metricName, d.metricType)
+}
+
+// writeSample writes a line of the metric, extra is appended to the labels, e.g. the bucket bound
+func (d desc) writeSample(w *bufio.Writer, suffix string, labelValues []string, extra string, value float64) {
+	w.WriteString(d.metricName)
+	w.WriteString(suffix)
+
+	if len(d.labels) > 0 || extra != "" {
+		pairs := make([]string, 0, len(d.labels)+1)
+		for i, label := range d.labels {
+			pairs = append(pairs, label+`="`+escapeLabelValue(labelValues[i])+`"`)
+		}
+		if extra != "" {
+			pairs = append(pairs, extra)
+		}
+		w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	w.WriteString(" ")
+	w.WriteString(formatFloat(value))
+	w.WriteString("\n")
+}
+
+func (d desc) checkLabelValues(labelValues []string) {
+	if len(labelValues) != len(d.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", d.metricName, len(d.labels), len(labelValues)))
+	}
+}
+
+// seriesKey joins label values into a map key, the separator cannot appear in valid UTF-8 text
+func seriesKey(labelValues []string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelEscaper.Replace(value)
+}
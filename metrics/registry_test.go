@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryWrite(t *testing.T) {
+	registry := NewRegistry()
+
+	requests := registry.NewCounter("http_requests_total", "Requests served.", "route", "status")
+	requests.Inc("/users/login", "200")
+	requests.Inc("/users/login", "200")
+	requests.Inc("/users/login", "401")
+	require.Equal(t, float64(2), requests.Value("/users/login", "200"))
+
+	latency := registry.NewHistogram("http_request_duration_seconds", "Request latency.", []float64{0.5, 0.1}, "route")
+	latency.Observe(0.05, "/users/login")
+	latency.Observe(0.3, "/users/login")
+	latency.Observe(2, "/users/login")
+	require.Equal(t, uint64(3), latency.Count("/users/login"))
+
+	registry.NewGaugeFunc("election_closed", "Whether the election is closed.", []string{"election_id"}, func(ctx context.Context) ([]Sample, error) {
+		return []Sample{{LabelValues: []string{"1"}, Value: 1}}, nil
+	})
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.Write(context.Background(), &buf))
+	require.Equal(t, `# HELP election_closed Whether the election is closed.
+# TYPE election_closed gauge
+election_closed{election_id="1"} 1
+# HELP http_request_duration_seconds Request latency.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{route="/users/login",le="0.1"} 1
+http_request_duration_seconds_bucket{route="/users/login",le="0.5"} 2
+http_request_duration_seconds_bucket{route="/users/login",le="+Inf"} 3
+http_request_duration_seconds_sum{route="/users/login"} 2.35
+http_request_duration_seconds_count{route="/users/login"} 3
+# HELP http_requests_total Requests served.
+# TYPE http_requests_total counter
+http_requests_total{route="/users/login",status="200"} 2
+http_requests_total{route="/users/login",status="401"} 1
+`, buf.String())
+}
+
+func TestRegistryEscaping(t *testing.T) {
+	registry := NewRegistry()
+
+	counter := registry.NewCounter("escaped_total", "Help with \\ and\nnewline.", "value")
+	counter.Inc("quote \" backslash \\ newline \n")
+
+	var buf bytes.Buffer
+	require.NoError(t, registry.Write(context.Background(), &buf))
+	require.Equal(t, `# HELP escaped_total Help with \\ and\nnewline.
+# TYPE escaped_total counter
+escaped_total{value="quote \" backslash \\ newline \n"} 1
+`, buf.String())
+}
+
+func TestRegistryCollectError(t *testing.T) {
+	registry := NewRegistry()
+	errCollect := errors.New("database is down")
+
+	registry.NewGaugeFunc("broken", "Cannot be read.", nil, func(ctx context.Context) ([]Sample, error) {
+		return nil, errCollect
+	})
+
+	var buf bytes.Buffer
+	require.ErrorIs(t, registry.Write(context.Background(), &buf), errCollect)
+}
+
+func TestRegistryMisuse(t *testing.T) {
+	registry := NewRegistry()
+	counter := registry.NewCounter("duplicate_total", "Registered once.", "label")
+
+	require.Panics(t, func() {
+		registry.NewCounter("duplicate_total", "Registered twice.")
+	})
+	require.Panics(t, func() {
+		counter.Inc()
+	})
+	require.Panics(t, func() {
+		counter.Add(-1, "value")
+	})
+}
@@ -0,0 +1,194 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidIssuer = errors.New("invalid issuer url")
+	ErrCodeRejected  = errors.New("authorization code rejected by the identity provider")
+	ErrMissingToken  = errors.New("identity provider returned no id token")
+)
+
+// Provider signs users in with an OpenID Connect identity provider using the
+// authorization code flow with PKCE
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	client       *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+}
+
+// discovery is the part of the provider metadata used by the authorization code flow
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider creates a provider for the issuer, its metadata is discovered on first use
+// so the server starts while the identity provider is unavailable
+func NewProvider(issuer, clientID, clientSecret, redirectURL string) (*Provider, error) {
+	u, err := url.Parse(issuer)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidIssuer, issuer)
+	}
+
+	return &Provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		client:       &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// CodeChallenge derives the S256 PKCE challenge sent with the authorization request from the verifier
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the authorization endpoint URL the user is sent to, the state, nonce and
+// code verifier must be kept until the user returns with the authorization code
+func (provider *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	d, err := provider.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", provider.clientID)
+	query.Set("redirect_uri", provider.redirectURL)
+	query.Set("scope", "openid profile email")
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", CodeChallenge(codeVerifier))
+	query.Set("code_challenge_method", "S256")
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return d.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+// Exchange redeems the authorization code at the token endpoint and returns the raw id token,
+// ErrCodeRejected is returned when the provider refuses the code or the verifier
+func (provider *Provider) Exchange(ctx context.Context, code, codeVerifier string) (string, error) {
+	d, err := provider.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", provider.redirectURL)
+	form.Set("client_id", provider.clientID)
+	form.Set("code_verifier", codeVerifier)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if provider.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(provider.clientID), url.QueryEscape(provider.clientSecret))
+	}
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to redeem authorization code: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 400 && res.StatusCode < 500 {
+		return "", ErrCodeRejected
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to redeem authorization code: status %d", res.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&token); err != nil {
+		return "", fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return "", ErrMissingToken
+	}
+	return token.IDToken, nil
+}
+
+// discover fetches the provider metadata once, a failed attempt is retried on the next call.
+// The fetch runs outside the lock so a slow identity provider does not block the key lookups
+// of other requests, concurrent first calls may each fetch and the first result is kept.
+func (provider *Provider) discover(ctx context.Context) (*discovery, error) {
+	provider.mu.Lock()
+	cached := provider.discovery
+	provider.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var d discovery
+	if err := provider.getJSON(ctx, provider.issuer+"/.well-known/openid-configuration", &d); err != nil {
+		return nil, fmt.Errorf("failed to discover identity provider: %w", err)
+	}
+	if strings.TrimSuffix(d.Issuer, "/") != provider.issuer {
+		return nil, fmt.Errorf("%w: discovered %q", ErrInvalidIssuer, d.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("identity provider metadata is incomplete")
+	}
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	if provider.discovery == nil {
+		provider.discovery = &d
+	}
+	return provider.discovery, nil
+}
+
+func (provider *Provider) getJSON(ctx context.Context, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	res, err := provider.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", res.StatusCode, url)
+	}
+	return json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(v)
+}
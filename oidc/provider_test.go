@@ -0,0 +1,244 @@
+package oidc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"election/oidc/oidctest"
+	"election/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProvider(t *testing.T) (*Provider, *oidctest.IdP) {
+	idp, err := oidctest.NewIdP("election", util.RandomString(32))
+	require.NoError(t, err)
+	t.Cleanup(idp.Close)
+
+	provider, err := NewProvider(idp.URL, idp.ClientID, idp.ClientSecret, "http://localhost:4200/login/oidc")
+	require.NoError(t, err)
+	return provider, idp
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	provider, idp := newTestProvider(t)
+
+	state := util.RandomString(32)
+	nonce := util.RandomString(32)
+	verifier := util.RandomString(43)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err)
+	require.Contains(t, authURL, "code_challenge="+CodeChallenge(verifier))
+
+	code, returnedState, err := idp.Authorize(authURL, map[string]interface{}{
+		"sub":         "user-1",
+		"national_id": "1234567890123",
+		"email":       "voter@example.com",
+	})
+	require.NoError(t, err)
+	require.Equal(t, state, returnedState)
+
+	idToken, err := provider.Exchange(context.Background(), code, verifier)
+	require.NoError(t, err)
+
+	claims, err := provider.Verify(context.Background(), idToken, nonce)
+	require.NoError(t, err)
+	require.Equal(t, "1234567890123", claims.String("national_id"))
+	require.Equal(t, "voter@example.com", claims.String("email"))
+	require.False(t, claims.Bool("email_verified"))
+
+	// a code is redeemed once
+	_, err = provider.Exchange(context.Background(), code, verifier)
+	require.ErrorIs(t, err, ErrCodeRejected)
+
+	_, err = provider.Verify(context.Background(), idToken, util.RandomString(32))
+	require.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestExchangeWrongVerifier(t *testing.T) {
+	provider, idp := newTestProvider(t)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state", "nonce", util.RandomString(43))
+	require.NoError(t, err)
+
+	code, _, err := idp.Authorize(authURL, nil)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, util.RandomString(43))
+	require.ErrorIs(t, err, ErrCodeRejected)
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	provider, idp := newTestProvider(t)
+	nonce := util.RandomString(32)
+
+	validClaims := func() map[string]interface{} {
+		return map[string]interface{}{
+			"iss":   idp.URL,
+			"aud":   []string{"other", idp.ClientID},
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": nonce,
+		}
+	}
+
+	testCases := []struct {
+		name     string
+		token    func() string
+		checkErr func(err error)
+	}{
+		{
+			name: "OK",
+			token: func() string {
+				token, err := idp.SignToken(validClaims())
+				require.NoError(t, err)
+				return token
+			},
+			checkErr: func(err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-2 * clockSkew).Unix()
+				token, err := idp.SignToken(claims)
+				require.NoError(t, err)
+				return token
+			},
+			checkErr: func(err error) {
+				require.ErrorIs(t, err, ErrExpiredToken)
+			},
+		},
+		{
+			name: "WrongAudience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "other"
+				token, err := idp.SignToken(claims)
+				require.NoError(t, err)
+				return token
+			},
+			checkErr: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		},
+		{
+			name: "WrongIssuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://attacker.example.com"
+				token, err := idp.SignToken(claims)
+				require.NoError(t, err)
+				return token
+			},
+			checkErr: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		},
+		{
+			name: "TamperedPayload",
+			token: func() string {
+				token, err := idp.SignToken(validClaims())
+				require.NoError(t, err)
+
+				parts := strings.Split(token, ".")
+				claims := validClaims()
+				claims["national_id"] = "1234567890123"
+				tampered, err := idp.SignToken(claims)
+				require.NoError(t, err)
+				parts[1] = strings.Split(tampered, ".")[1]
+				return strings.Join(parts, ".")
+			},
+			checkErr: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		},
+		{
+			name: "UnsignedToken",
+			token: func() string {
+				header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`))
+				token, err := idp.SignToken(validClaims())
+				require.NoError(t, err)
+				return header + "." + strings.Split(token, ".")[1] + "."
+			},
+			checkErr: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		},
+		{
+			name: "Malformed",
+			token: func() string {
+				return "not-a-token"
+			},
+			checkErr: func(err error) {
+				require.ErrorIs(t, err, ErrInvalidToken)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := provider.Verify(context.Background(), tc.token(), nonce)
+			tc.checkErr(err)
+		})
+	}
+}
+
+func TestNewProviderInvalidIssuer(t *testing.T) {
+	_, err := NewProvider("not a url", "election", "secret", "http://localhost:4200/login/oidc")
+	require.ErrorIs(t, err, ErrInvalidIssuer)
+}
+
+func TestDiscoverOutsideLock(t *testing.T) {
+	fetching := make(chan struct{})
+	release := make(chan struct{})
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(fetching)
+		<-release
+		json.NewEncoder(w).Encode(discovery{
+			Issuer:                server.URL,
+			AuthorizationEndpoint: server.URL + "/authorize",
+			TokenEndpoint:         server.URL + "/token",
+			JWKSURI:               server.URL + "/keys",
+		})
+	}))
+	defer server.Close()
+
+	var once sync.Once
+	unblock := func() { once.Do(func() { close(release) }) }
+	defer unblock()
+
+	provider, err := NewProvider(server.URL, "election", "secret", "http://localhost:4200/login/oidc")
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := provider.discover(context.Background())
+		done <- err
+	}()
+
+	// the lock is free while the identity provider answers slowly
+	<-fetching
+	require.True(t, provider.mu.TryLock())
+	provider.mu.Unlock()
+
+	unblock()
+	require.NoError(t, <-done)
+
+	d, err := provider.discover(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, server.URL+"/token", d.TokenEndpoint)
+}
@@ -15,8 +15,8 @@ type PasetoMaker struct {
 }
 
 // CreateToken implements Maker
-func (maker *PasetoMaker) CreateToken(nationalID string, duration time.Duration) (string, *Payload, error) {
-	payload, err := NewPayload(nationalID, duration)
+func (maker *PasetoMaker) CreateToken(nationalID string, twoFactorVerified bool, duration time.Duration) (string, *Payload, error) {
+	payload, err := NewPayload(nationalID, twoFactorVerified, duration)
 	if err != nil {
 		return "", payload, err
 	}
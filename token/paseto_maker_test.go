@@ -19,7 +19,7 @@ func TestPasetoMaker(t *testing.T) {
 	issuedAt := time.Now()
 	expiredAt := time.Now().Add(duration)
 
-	token, payload, err := maker.CreateToken(nationalID, duration)
+	token, payload, err := maker.CreateToken(nationalID, true, duration)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
@@ -29,6 +29,7 @@ func TestPasetoMaker(t *testing.T) {
 
 	require.NotZero(t, payload.ID)
 	require.Equal(t, nationalID, payload.NationalID)
+	require.True(t, payload.TwoFactorVerified)
 	require.WithinDuration(t, issuedAt, payload.IssuedAt, time.Second)
 	require.WithinDuration(t, expiredAt, payload.ExpiredAt, time.Second)
 }
@@ -37,7 +38,7 @@ func TestExpiredPasetoToken(t *testing.T) {
 	maker, err := NewPasetoMaker(util.RandomString(32))
 	require.NoError(t, err)
 
-	token, payload, err := maker.CreateToken(util.RandomString(13), -time.Minute)
+	token, payload, err := maker.CreateToken(util.RandomString(13), false, -time.Minute)
 	require.NoError(t, err)
 	require.NotEmpty(t, token)
 
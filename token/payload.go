@@ -18,22 +18,26 @@ type Payload struct {
 	ID          uuid.UUID `json:"id"`
 	NationalID  string    `json:"national_id"`
 	Permissions []string  `json:"permissions"`
-	IssuedAt    time.Time `json:"issued_at"`
-	ExpiredAt   time.Time `json:"expired_at"`
+	// TwoFactorVerified is set when the session was signed in with a second factor
+	TwoFactorVerified bool      `json:"two_factor_verified"`
+	IssuedAt          time.Time `json:"issued_at"`
+	ExpiredAt         time.Time `json:"expired_at"`
 }
 
-//NewPayload creates a new token with specific national id and duration
-func NewPayload(nationalID string, duration time.Duration) (*Payload, error) {
+//NewPayload creates a new token with specific national id and duration, twoFactorVerified tells
+//whether the user signed in with a second factor
+func NewPayload(nationalID string, twoFactorVerified bool, duration time.Duration) (*Payload, error) {
 	tokenID, err := uuid.NewRandom()
 	if err != nil {
 		return nil, err
 	}
 
 	payload := &Payload{
-		ID:         tokenID,
-		NationalID: nationalID,
-		IssuedAt:   time.Now(),
-		ExpiredAt:  time.Now().Add(duration),
+		ID:                tokenID,
+		NationalID:        nationalID,
+		TwoFactorVerified: twoFactorVerified,
+		IssuedAt:          time.Now(),
+		ExpiredAt:         time.Now().Add(duration),
 	}
 
 	return payload, nil
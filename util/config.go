@@ -7,11 +7,36 @@ import (
 )
 
 type Config struct {
-	DBDriver            string        `mapstructure:"DB_DRIVER"`
-	DBSource            string        `mapstructure:"DB_SOURCE"`
-	ServerAddress       string        `mapstructure:"SERVER_ADDRESS"`
-	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	DBDriver                  string        `mapstructure:"DB_DRIVER"`
+	DBSource                  string        `mapstructure:"DB_SOURCE"`
+	ServerAddress             string        `mapstructure:"SERVER_ADDRESS"`
+	TokenSymmetricKey         string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration       time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	MaxProxiesPerHolder       int64         `mapstructure:"MAX_PROXIES_PER_HOLDER"`
+	ImageMaxBytes             int64         `mapstructure:"IMAGE_MAX_BYTES"`
+	ImageStorageDir           string        `mapstructure:"IMAGE_STORAGE_DIR"`
+	ImageS3Endpoint           string        `mapstructure:"IMAGE_S3_ENDPOINT"`
+	ImageS3Region             string        `mapstructure:"IMAGE_S3_REGION"`
+	ImageS3Bucket             string        `mapstructure:"IMAGE_S3_BUCKET"`
+	ImageS3AccessKey          string        `mapstructure:"IMAGE_S3_ACCESS_KEY"`
+	ImageS3SecretKey          string        `mapstructure:"IMAGE_S3_SECRET_KEY"`
+	AppBaseURL                string        `mapstructure:"APP_BASE_URL"`
+	MailSender                string        `mapstructure:"MAIL_SENDER"`
+	MailDir                   string        `mapstructure:"MAIL_DIR"`
+	SMTPHost                  string        `mapstructure:"SMTP_HOST"`
+	SMTPPort                  int           `mapstructure:"SMTP_PORT"`
+	SMTPUsername              string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword              string        `mapstructure:"SMTP_PASSWORD"`
+	PasswordResetDuration     time.Duration `mapstructure:"PASSWORD_RESET_DURATION"`
+	EmailVerificationDuration time.Duration `mapstructure:"EMAIL_VERIFICATION_DURATION"`
+	EmailVerificationInterval time.Duration `mapstructure:"EMAIL_VERIFICATION_INTERVAL"`
+	OIDCIssuerURL             string        `mapstructure:"OIDC_ISSUER_URL"`
+	OIDCClientID              string        `mapstructure:"OIDC_CLIENT_ID"`
+	OIDCClientSecret          string        `mapstructure:"OIDC_CLIENT_SECRET"`
+	OIDCRedirectURL           string        `mapstructure:"OIDC_REDIRECT_URL"`
+	OIDCNationalIDClaim       string        `mapstructure:"OIDC_NATIONAL_ID_CLAIM"`
+	MetricsToken              string        `mapstructure:"METRICS_TOKEN"`
+	LogLevel                  string        `mapstructure:"LOG_LEVEL"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -1,7 +1,27 @@
 package util
 
 const (
-	ManageElection = "MANAGE_ELECTION"
-	Vote           = "VOTE"
-	ElectionClosed = "ELECTION_CLOSED"
+	ManageElection          = "MANAGE_ELECTION"
+	Vote                    = "VOTE"
+	ElectionClosed          = "ELECTION_CLOSED"
+	RevoteEnabled           = "REVOTE_ENABLED"
+	WithdrawAfterVotes      = "WITHDRAW_AFTER_VOTES"
+	RequireVerifiedEmail    = "REQUIRE_VERIFIED_EMAIL"
+	RequireManagerTwoFactor = "REQUIRE_MANAGER_TWO_FACTOR"
+)
+
+const (
+	DelegationPending  = "PENDING"
+	DelegationApproved = "APPROVED"
+	DelegationRevoked  = "REVOKED"
+)
+
+const (
+	GrantPermission  = "GRANT_PERMISSION"
+	RevokePermission = "REVOKE_PERMISSION"
+	DisableAccount   = "DISABLE_ACCOUNT"
+	EnableAccount    = "ENABLE_ACCOUNT"
+	ResetVoterStatus = "RESET_VOTER_STATUS"
+	AssignRole       = "ASSIGN_ROLE"
+	UnassignRole     = "UNASSIGN_ROLE"
 )
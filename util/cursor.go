@@ -0,0 +1,60 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("Invalid cursor")
+
+var cursorEncoding = base64.RawURLEncoding
+
+// SignCursor encodes the position of a page as an opaque token, the position is
+// signed with the key so a client cannot forge or alter it
+func SignCursor(key string, position interface{}) (string, error) {
+	payload, err := json.Marshal(position)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return cursorEncoding.EncodeToString(payload) + "." + cursorEncoding.EncodeToString(cursorMAC(key, payload)), nil
+}
+
+// VerifyCursor checks the signature of a token made by SignCursor and decodes the position
+func VerifyCursor(key string, cursor string, position interface{}) error {
+	encodedPayload, encodedMAC, found := strings.Cut(cursor, ".")
+	if !found {
+		return ErrInvalidCursor
+	}
+
+	payload, err := cursorEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	mac, err := cursorEncoding.DecodeString(encodedMAC)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if !hmac.Equal(mac, cursorMAC(key, payload)) {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(payload, position); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func cursorMAC(key string, payload []byte) []byte {
+	// the cursor key is derived so a cursor signature can never pass for a token signature
+	derived := sha256.Sum256([]byte("cursor:" + key))
+	mac := hmac.New(sha256.New, derived[:])
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,53 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type testPosition struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestCursor(t *testing.T) {
+	key := RandomString(32)
+	position := testPosition{ID: RandomInt(1, 1000), Name: RandomName()}
+
+	cursor, err := SignCursor(key, position)
+	require.NoError(t, err)
+	require.NotContains(t, cursor, position.Name)
+
+	var got testPosition
+	err = VerifyCursor(key, cursor, &got)
+	require.NoError(t, err)
+	require.Equal(t, position, got)
+}
+
+func TestCursorTampered(t *testing.T) {
+	key := RandomString(32)
+
+	cursor, err := SignCursor(key, testPosition{ID: 1})
+	require.NoError(t, err)
+
+	forged, err := SignCursor(RandomString(32), testPosition{ID: 2})
+	require.NoError(t, err)
+
+	payload, mac, _ := strings.Cut(cursor, ".")
+	forgedPayload, _, _ := strings.Cut(forged, ".")
+
+	for _, bad := range []string{
+		forged,
+		forgedPayload + "." + mac,
+		payload,
+		payload + ".",
+		"!!." + mac,
+		"",
+	} {
+		var got testPosition
+		err = VerifyCursor(key, bad, &got)
+		require.ErrorIs(t, err, ErrInvalidCursor)
+	}
+}
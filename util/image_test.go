@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestDecodeImage(t *testing.T) {
+	img, err := DecodeImage(randomPNG(t, 40, 20))
+	require.NoError(t, err)
+	require.Equal(t, 40, img.Bounds().Dx())
+
+	_, err = DecodeImage([]byte("<html><body>not an image</body></html>"))
+	require.ErrorIs(t, err, ErrUnsupportedImage)
+
+	_, err = DecodeImage([]byte("\x89PNG\r\n\x1a\ntruncated"))
+	require.Error(t, err)
+}
+
+func TestFitImage(t *testing.T) {
+	img, err := DecodeImage(randomPNG(t, 400, 100))
+	require.NoError(t, err)
+
+	fitted := FitImage(img, 200)
+	require.Equal(t, 200, fitted.Bounds().Dx())
+	require.Equal(t, 50, fitted.Bounds().Dy())
+
+	tall := FitImage(image.NewRGBA(image.Rect(0, 0, 10, 1000)), 100)
+	require.Equal(t, 1, tall.Bounds().Dx())
+	require.Equal(t, 100, tall.Bounds().Dy())
+
+	small := FitImage(img, 1000)
+	require.Equal(t, img.Bounds(), small.Bounds())
+}
+
+func TestFitImageAverages(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, color.RGBA{A: 255})
+	img.Set(1, 0, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+	img.Set(0, 1, color.RGBA{A: 255})
+	img.Set(1, 1, color.RGBA{R: 255, G: 255, B: 255, A: 255})
+
+	r, _, _, a := FitImage(img, 1).At(0, 0).RGBA()
+	require.InDelta(t, 0x7fff, r, 0x100)
+	require.Equal(t, uint32(0xffff), a)
+}
+
+func TestEncodeJPEG(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+
+	data, err := EncodeJPEG(img)
+	require.NoError(t, err)
+
+	decoded, err := jpeg.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 10, decoded.Bounds().Dx())
+
+	// a transparent image is flattened onto white
+	r, g, b, _ := decoded.At(5, 5).RGBA()
+	require.Greater(t, r, uint32(0xf000))
+	require.Greater(t, g, uint32(0xf000))
+	require.Greater(t, b, uint32(0xf000))
+}
@@ -0,0 +1,166 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+)
+
+const (
+	WinnerRulePlurality        = "PLURALITY"
+	WinnerRuleAbsoluteMajority = "ABSOLUTE_MAJORITY"
+)
+
+const (
+	TieBreakLottery = "LOTTERY"
+	TieBreakRunoff  = "RUNOFF"
+)
+
+const (
+	OutcomeWinner   = "WINNER"
+	OutcomeRunoff   = "RUNOFF"
+	OutcomeNoQuorum = "NO_QUORUM"
+	OutcomeNoVotes  = "NO_VOTES"
+)
+
+// ExceedsThreshold reports whether votes are more than percentage percent of the total votes
+func ExceedsThreshold(votes, total int64, percentage int32) bool {
+	return total > 0 && votes*100 > int64(percentage)*total
+}
+
+// MeetsQuorum reports whether the turnout reaches percentage percent of the eligible voters
+func MeetsQuorum(turnout, eligible int64, percentage int32) bool {
+	return turnout*100 >= int64(percentage)*eligible
+}
+
+// Tally is the number of votes received by a candidate
+type Tally struct {
+	CandidateID int64
+	Votes       int64
+}
+
+// OutcomeRules are the winner determination rules of an election
+type OutcomeRules struct {
+	WinnerRule       string
+	QuorumPercentage int32
+	TieBreak         string
+	TieBreakSeed     string
+}
+
+// Outcome is the decision of an election given its tallies
+type Outcome struct {
+	Result string
+	Tie    bool
+	Winner int64
+	Runoff []int64
+}
+
+// DetermineOutcome decides the winner of an election from the tallies. Without quorum there is
+// no winner. Under plurality the candidate with most votes wins, under absolute majority it must
+// also have more than half of the votes, otherwise the top two candidates go to a runoff.
+// A tie for the first place is broken by the seeded lottery or sent to a runoff.
+func DetermineOutcome(tallies []Tally, turnout, eligible int64, rules OutcomeRules) Outcome {
+	if !MeetsQuorum(turnout, eligible, rules.QuorumPercentage) {
+		return Outcome{Result: OutcomeNoQuorum}
+	}
+
+	sorted := make([]Tally, len(tallies))
+	copy(sorted, tallies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Votes > sorted[j].Votes
+	})
+
+	var total int64
+	for _, tally := range sorted {
+		total += tally.Votes
+	}
+	if total == 0 {
+		return Outcome{Result: OutcomeNoVotes}
+	}
+
+	leaders := tiedAt(sorted, sorted[0].Votes)
+	tie := len(leaders) > 1
+
+	if rules.WinnerRule == WinnerRuleAbsoluteMajority && !ExceedsThreshold(sorted[0].Votes, total, 50) {
+		runoff := leaders
+		if !tie && len(sorted) > 1 {
+			runoff = append(runoff, tiedAt(sorted, sorted[1].Votes)...)
+		}
+		return Outcome{Result: OutcomeRunoff, Tie: tie, Runoff: runoff}
+	}
+
+	if !tie {
+		return Outcome{Result: OutcomeWinner, Winner: leaders[0]}
+	}
+
+	if rules.TieBreak == TieBreakLottery {
+		return Outcome{Result: OutcomeWinner, Tie: true, Winner: LotteryOrder(rules.TieBreakSeed, leaders)[0]}
+	}
+
+	return Outcome{Result: OutcomeRunoff, Tie: true, Runoff: leaders}
+}
+
+// RunoffCandidates picks the top n candidates by votes for a runoff, candidates tied
+// with the n-th place are included as well so no tie is decided by the list order
+func RunoffCandidates(tallies []Tally, n int) []int64 {
+	if n < 1 {
+		return nil
+	}
+
+	sorted := make([]Tally, len(tallies))
+	copy(sorted, tallies)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Votes > sorted[j].Votes
+	})
+
+	var ids []int64
+	for i, tally := range sorted {
+		if i >= n && tally.Votes != sorted[n-1].Votes {
+			break
+		}
+		ids = append(ids, tally.CandidateID)
+	}
+	return ids
+}
+
+func tiedAt(sorted []Tally, votes int64) []int64 {
+	var ids []int64
+	for _, tally := range sorted {
+		if tally.Votes == votes {
+			ids = append(ids, tally.CandidateID)
+		}
+	}
+	return ids
+}
+
+const tieBreakSeedBytes = 16
+
+// NewTieBreakSeed draws an unpredictable lottery seed, it is published with the election
+// so the draw can be reproduced once the votes are in
+func NewTieBreakSeed() (string, error) {
+	b := make([]byte, tieBreakSeedBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate tie break seed: %w", err)
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// LotteryOrder orders the candidates by the hash of the published seed and their id,
+// so anyone knowing the seed can reproduce the draw
+func LotteryOrder(seed string, candidateIDs []int64) []int64 {
+	draws := make(map[int64]string, len(candidateIDs))
+	for _, id := range candidateIDs {
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s:%d", seed, id)))
+		draws[id] = hex.EncodeToString(sum[:])
+	}
+
+	ordered := make([]int64, len(candidateIDs))
+	copy(ordered, candidateIDs)
+	sort.Slice(ordered, func(i, j int) bool {
+		return draws[ordered[i]] < draws[ordered[j]]
+	})
+	return ordered
+}
@@ -0,0 +1,112 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExceedsThreshold(t *testing.T) {
+	require.True(t, ExceedsThreshold(51, 100, 50))
+	require.False(t, ExceedsThreshold(50, 100, 50))
+	require.True(t, ExceedsThreshold(2, 3, 66))
+	require.False(t, ExceedsThreshold(66, 100, 66))
+	require.False(t, ExceedsThreshold(0, 0, 50))
+}
+
+func TestMeetsQuorum(t *testing.T) {
+	require.True(t, MeetsQuorum(25, 100, 25))
+	require.False(t, MeetsQuorum(24, 100, 25))
+	require.True(t, MeetsQuorum(0, 100, 0))
+	require.True(t, MeetsQuorum(0, 0, 50))
+}
+
+func TestDetermineOutcomePlurality(t *testing.T) {
+	rules := OutcomeRules{WinnerRule: WinnerRulePlurality, TieBreak: TieBreakRunoff}
+	tallies := []Tally{{1, 3}, {2, 5}, {3, 2}}
+
+	outcome := DetermineOutcome(tallies, 10, 20, rules)
+	require.Equal(t, OutcomeWinner, outcome.Result)
+	require.Equal(t, int64(2), outcome.Winner)
+	require.False(t, outcome.Tie)
+}
+
+func TestDetermineOutcomeNoQuorum(t *testing.T) {
+	rules := OutcomeRules{WinnerRule: WinnerRulePlurality, QuorumPercentage: 60}
+	tallies := []Tally{{1, 3}, {2, 5}}
+
+	outcome := DetermineOutcome(tallies, 8, 20, rules)
+	require.Equal(t, OutcomeNoQuorum, outcome.Result)
+	require.Zero(t, outcome.Winner)
+}
+
+func TestDetermineOutcomeNoVotes(t *testing.T) {
+	rules := OutcomeRules{WinnerRule: WinnerRulePlurality}
+
+	outcome := DetermineOutcome([]Tally{{1, 0}, {2, 0}}, 0, 20, rules)
+	require.Equal(t, OutcomeNoVotes, outcome.Result)
+}
+
+func TestDetermineOutcomeAbsoluteMajority(t *testing.T) {
+	rules := OutcomeRules{WinnerRule: WinnerRuleAbsoluteMajority, TieBreak: TieBreakRunoff}
+
+	outcome := DetermineOutcome([]Tally{{1, 6}, {2, 3}, {3, 1}}, 10, 10, rules)
+	require.Equal(t, OutcomeWinner, outcome.Result)
+	require.Equal(t, int64(1), outcome.Winner)
+
+	outcome = DetermineOutcome([]Tally{{1, 5}, {2, 3}, {3, 2}}, 10, 10, rules)
+	require.Equal(t, OutcomeRunoff, outcome.Result)
+	require.Equal(t, []int64{1, 2}, outcome.Runoff)
+
+	outcome = DetermineOutcome([]Tally{{1, 4}, {2, 3}, {3, 3}}, 10, 10, rules)
+	require.Equal(t, OutcomeRunoff, outcome.Result)
+	require.Equal(t, []int64{1, 2, 3}, outcome.Runoff)
+}
+
+func TestDetermineOutcomeTie(t *testing.T) {
+	tallies := []Tally{{1, 4}, {2, 4}, {3, 2}}
+
+	rules := OutcomeRules{WinnerRule: WinnerRulePlurality, TieBreak: TieBreakRunoff}
+	outcome := DetermineOutcome(tallies, 10, 10, rules)
+	require.Equal(t, OutcomeRunoff, outcome.Result)
+	require.True(t, outcome.Tie)
+	require.Equal(t, []int64{1, 2}, outcome.Runoff)
+
+	rules = OutcomeRules{WinnerRule: WinnerRulePlurality, TieBreak: TieBreakLottery, TieBreakSeed: RandomString(32)}
+	outcome = DetermineOutcome(tallies, 10, 10, rules)
+	require.Equal(t, OutcomeWinner, outcome.Result)
+	require.True(t, outcome.Tie)
+	require.Equal(t, LotteryOrder(rules.TieBreakSeed, []int64{1, 2})[0], outcome.Winner)
+}
+
+func TestLotteryOrder(t *testing.T) {
+	seed := RandomString(32)
+	ids := []int64{1, 2, 3, 4, 5}
+
+	order1 := LotteryOrder(seed, ids)
+	order2 := LotteryOrder(seed, []int64{5, 4, 3, 2, 1})
+	require.Equal(t, order1, order2)
+	require.ElementsMatch(t, ids, order1)
+	require.Equal(t, []int64{1, 2, 3, 4, 5}, ids)
+}
+
+func TestNewTieBreakSeed(t *testing.T) {
+	seed1, err := NewTieBreakSeed()
+	require.NoError(t, err)
+	require.Len(t, seed1, 32)
+
+	seed2, err := NewTieBreakSeed()
+	require.NoError(t, err)
+	require.NotEqual(t, seed1, seed2)
+}
+
+func TestRunoffCandidates(t *testing.T) {
+	tallies := []Tally{{1, 3}, {2, 5}, {3, 2}, {4, 4}}
+	require.Equal(t, []int64{2, 4}, RunoffCandidates(tallies, 2))
+	require.Equal(t, []int64{2, 4, 1}, RunoffCandidates(tallies, 3))
+
+	tied := []Tally{{1, 5}, {2, 3}, {3, 3}, {4, 1}}
+	require.Equal(t, []int64{1, 2, 3}, RunoffCandidates(tied, 2))
+	require.Len(t, RunoffCandidates(tied, 10), 4)
+	require.Empty(t, RunoffCandidates(nil, 2))
+}
@@ -0,0 +1,66 @@
+package util
+
+import "sort"
+
+// Permissions required by the API routes. VOTE and MANAGE_ELECTION are held directly by users,
+// MANAGE_ELECTION grants every permission, the others are granted through roles.
+const (
+	ViewAudit        = "VIEW_AUDIT"
+	ViewUsers        = "VIEW_USERS"
+	ManageUsers      = "MANAGE_USERS"
+	ManageCandidates = "MANAGE_CANDIDATES"
+	ManageVoters     = "MANAGE_VOTERS"
+	ConductElection  = "CONDUCT_ELECTION"
+)
+
+const (
+	ObserverRole         = "OBSERVER"
+	AuditorRole          = "AUDITOR"
+	CandidateManagerRole = "CANDIDATE_MANAGER"
+	ElectionOfficerRole  = "ELECTION_OFFICER"
+)
+
+// rolePermissions is the permission registry, every role and the permissions it grants
+var rolePermissions = map[string][]string{
+	ObserverRole:         {ViewAudit},
+	AuditorRole:          {ViewAudit, ViewUsers},
+	CandidateManagerRole: {ManageCandidates},
+	ElectionOfficerRole:  {ManageCandidates, ManageVoters, ConductElection},
+}
+
+// readOnlyPermissions do not change the election, they never need two-factor authentication
+var readOnlyPermissions = map[string]bool{
+	ViewAudit: true,
+	ViewUsers: true,
+}
+
+// Roles returns the names of all roles in alphabetical order
+func Roles() []string {
+	roles := make([]string, 0, len(rolePermissions))
+	for role := range rolePermissions {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// IsRole reports whether role is a known role
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// RoleGrants reports whether role grants permission
+func RoleGrants(role, permission string) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsReadOnlyPermission reports whether permission only gives access to read the election
+func IsReadOnlyPermission(permission string) bool {
+	return readOnlyPermissions[permission]
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoles(t *testing.T) {
+	require.Equal(t, []string{AuditorRole, CandidateManagerRole, ElectionOfficerRole, ObserverRole}, Roles())
+
+	for _, role := range Roles() {
+		require.True(t, IsRole(role))
+	}
+	require.False(t, IsRole(ManageElection))
+	require.False(t, IsRole(""))
+}
+
+func TestRoleGrants(t *testing.T) {
+	require.True(t, RoleGrants(ObserverRole, ViewAudit))
+	require.False(t, RoleGrants(ObserverRole, ManageCandidates))
+
+	require.True(t, RoleGrants(AuditorRole, ViewUsers))
+	require.False(t, RoleGrants(AuditorRole, ManageUsers))
+
+	require.True(t, RoleGrants(CandidateManagerRole, ManageCandidates))
+	require.False(t, RoleGrants(CandidateManagerRole, ConductElection))
+
+	require.True(t, RoleGrants(ElectionOfficerRole, ConductElection))
+	require.False(t, RoleGrants(ElectionOfficerRole, ManageUsers))
+
+	require.False(t, RoleGrants("UNKNOWN", ViewAudit))
+}
+
+func TestIsReadOnlyPermission(t *testing.T) {
+	require.True(t, IsReadOnlyPermission(ViewAudit))
+	require.False(t, IsReadOnlyPermission(ConductElection))
+	require.False(t, IsReadOnlyPermission(ManageUsers))
+}
@@ -0,0 +1,31 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const receiptCodeBytes = 15
+
+var receiptEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewReceiptCode generates an unguessable code given to the voter as a receipt of the ballot
+func NewReceiptCode() (string, error) {
+	b := make([]byte, receiptCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate receipt code :%w", err)
+	}
+
+	return receiptEncoding.EncodeToString(b), nil
+}
+
+// HashReceiptCode returns the hash of receipt code which is the only form stored in database
+func HashReceiptCode(code string) string {
+	normalized := strings.ToUpper(strings.TrimSpace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
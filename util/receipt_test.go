@@ -0,0 +1,30 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReceiptCode(t *testing.T) {
+	code1, err := NewReceiptCode()
+	require.NoError(t, err)
+	require.Len(t, code1, 24)
+
+	code2, err := NewReceiptCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code1, code2)
+}
+
+func TestHashReceiptCode(t *testing.T) {
+	code, err := NewReceiptCode()
+	require.NoError(t, err)
+
+	hash := HashReceiptCode(code)
+	require.Len(t, hash, 64)
+	require.NotContains(t, hash, code)
+
+	require.Equal(t, hash, HashReceiptCode(" "+strings.ToLower(code)+" "))
+	require.NotEqual(t, hash, HashReceiptCode(code+"A"))
+}
@@ -0,0 +1,32 @@
+package util
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+const recoveryCodeBytes = 7
+
+// NewRecoveryCode generates a single use code which replaces an authenticator code when the authenticator is lost,
+// it is formatted as two groups of five characters to be easy to write down
+func NewRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate recovery code: %w", err)
+	}
+
+	code := base32.StdEncoding.EncodeToString(b)
+	return code[:5] + "-" + code[5:10], nil
+}
+
+// HashRecoveryCode returns the hash of a recovery code which is the only form stored in database,
+// the code is compared regardless of case, spaces and dashes
+func HashRecoveryCode(code string) string {
+	normalized := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
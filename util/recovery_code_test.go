@@ -0,0 +1,29 @@
+package util
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRecoveryCode(t *testing.T) {
+	code1, err := NewRecoveryCode()
+	require.NoError(t, err)
+	require.Len(t, code1, 11)
+	require.Equal(t, "-", code1[5:6])
+
+	code2, err := NewRecoveryCode()
+	require.NoError(t, err)
+	require.NotEqual(t, code1, code2)
+}
+
+func TestHashRecoveryCode(t *testing.T) {
+	code, err := NewRecoveryCode()
+	require.NoError(t, err)
+
+	hash := HashRecoveryCode(code)
+	require.Len(t, hash, 64)
+	require.Equal(t, hash, HashRecoveryCode(strings.ToLower(strings.ReplaceAll(code, "-", " "))))
+	require.NotEqual(t, hash, HashRecoveryCode(code+"A"))
+}
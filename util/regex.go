@@ -14,3 +14,16 @@ func IsDateOfBirth(s string) bool {
 
 	return matched
 }
+
+const NationalIDRegex = `^\d{13}$`
+
+// IsNationalID checks string is a 13 digits national id
+func IsNationalID(s string) bool {
+	matched, err := regexp.MatchString(NationalIDRegex, s)
+
+	if err != nil {
+		return false
+	}
+
+	return matched
+}
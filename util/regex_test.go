@@ -23,3 +23,10 @@ func TestInvalidDobRegex(t *testing.T) {
 	isDobRegex := IsDateOfBirth(s)
 	require.False(t, isDobRegex)
 }
+
+func TestNationalIDRegex(t *testing.T) {
+	require.True(t, IsNationalID("1234567890123"))
+	require.False(t, IsNationalID("123456789012"))
+	require.False(t, IsNationalID("12345678901234"))
+	require.False(t, IsNationalID("123456789012a"))
+}
@@ -0,0 +1,66 @@
+package util
+
+const (
+	BallotTypeCandidate = "CANDIDATE"
+	BallotTypePartyList = "PARTY_LIST"
+)
+
+const (
+	SeatMethodDHondt      = "DHONDT"
+	SeatMethodSainteLague = "SAINTE_LAGUE"
+)
+
+// PartyTally is the number of votes received by a party
+type PartyTally struct {
+	PartyID int64
+	Votes   int64
+}
+
+// AllocateSeats distributes the seats among the parties by the highest averages method.
+// D'Hondt divides the votes of a party by 1, 2, 3, ... and Sainte-Laguë by 1, 3, 5, ...
+// for each seat already won, every seat goes to the party with the highest quotient.
+// Equal quotients go to the party with more votes, then by the seeded lottery.
+func AllocateSeats(tallies []PartyTally, seats int, method, seed string) map[int64]int {
+	allocation := make(map[int64]int, len(tallies))
+	ids := make([]int64, 0, len(tallies))
+	votes := make(map[int64]int64, len(tallies))
+	for _, tally := range tallies {
+		allocation[tally.PartyID] = 0
+		if tally.Votes > 0 {
+			ids = append(ids, tally.PartyID)
+			votes[tally.PartyID] = tally.Votes
+		}
+	}
+	if len(ids) == 0 {
+		return allocation
+	}
+
+	rank := make(map[int64]int, len(ids))
+	for i, id := range LotteryOrder(seed, ids) {
+		rank[id] = i
+	}
+
+	divisor := func(won int) int64 {
+		if method == SeatMethodSainteLague {
+			return int64(2*won + 1)
+		}
+		return int64(won + 1)
+	}
+
+	for seat := 0; seat < seats; seat++ {
+		best := ids[0]
+		for _, id := range ids[1:] {
+			// compare votes/divisor without rounding: a/b > c/d when a*d > c*b
+			left := votes[id] * divisor(allocation[best])
+			right := votes[best] * divisor(allocation[id])
+			if left > right ||
+				left == right && votes[id] > votes[best] ||
+				left == right && votes[id] == votes[best] && rank[id] < rank[best] {
+				best = id
+			}
+		}
+		allocation[best]++
+	}
+
+	return allocation
+}
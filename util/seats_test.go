@@ -0,0 +1,44 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocateSeatsDHondt(t *testing.T) {
+	tallies := []PartyTally{{1, 100000}, {2, 80000}, {3, 30000}, {4, 20000}}
+
+	seats := AllocateSeats(tallies, 8, SeatMethodDHondt, "seed")
+	require.Equal(t, map[int64]int{1: 4, 2: 3, 3: 1, 4: 0}, seats)
+}
+
+func TestAllocateSeatsSainteLague(t *testing.T) {
+	tallies := []PartyTally{{1, 100000}, {2, 80000}, {3, 30000}, {4, 20000}}
+
+	seats := AllocateSeats(tallies, 8, SeatMethodSainteLague, "seed")
+	require.Equal(t, map[int64]int{1: 3, 2: 3, 3: 1, 4: 1}, seats)
+}
+
+func TestAllocateSeatsEqualQuotient(t *testing.T) {
+	// the second seat is a tie between 60/2 and 30/1, the larger party wins it
+	tallies := []PartyTally{{1, 30}, {2, 60}}
+
+	seats := AllocateSeats(tallies, 2, SeatMethodDHondt, "seed")
+	require.Equal(t, map[int64]int{1: 0, 2: 2}, seats)
+
+	// a tie between equal parties follows the lottery order of the seed
+	tallies = []PartyTally{{1, 50}, {2, 50}}
+	winner := LotteryOrder("seed", []int64{1, 2})[0]
+
+	seats = AllocateSeats(tallies, 1, SeatMethodDHondt, "seed")
+	require.Equal(t, 1, seats[winner])
+}
+
+func TestAllocateSeatsNoVotes(t *testing.T) {
+	tallies := []PartyTally{{1, 0}, {2, 0}}
+
+	seats := AllocateSeats(tallies, 5, SeatMethodDHondt, "seed")
+	require.Equal(t, map[int64]int{1: 0, 2: 0}, seats)
+	require.Empty(t, AllocateSeats(nil, 5, SeatMethodSainteLague, "seed"))
+}